@@ -16,7 +16,18 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const clientFilterHelp = "Filter clients by field values in format field=value1,value2, values might contain wildcards, " +
+	"can be repeated, e.g. --filter os_family=linux --filter tags=prod --filter connection_state=connected"
+
 func init() {
+	clientsListCmd.Flags().StringArray(client.FilterFlag, []string{}, clientFilterHelp)
+	clientsListCmd.Flags().String(
+		controllers.ClientsSort,
+		"",
+		"Comma separated fields to sort clients by, prefix a field with '-' for descending order, e.g. -connection_state,name",
+	)
+	clientsListCmd.Flags().Int(controllers.ClientsLimit, 0, "Show at most the given number of clients, 0 means all clients")
+	clientsListCmd.Flags().Int(controllers.ClientsOffset, 0, "Skip the given number of clients")
 	clientsCmd.AddCommand(clientsListCmd)
 	clientCmd.Flags().StringP(controllers.ClientNameFlag, "n", "", "Get client by name")
 	clientCmd.Flags().BoolP("all", "a", false, "Show client info with additional details")
@@ -42,6 +53,7 @@ var clientsListCmd = &cobra.Command{
 			ColCountCalculator: utils.CalcTerminalColumnsCount,
 			Writer:             os.Stdout,
 			Format:             getOutputFormat(),
+			Columns:            getColumns(),
 		}
 
 		clientSearch := &client.Search{
@@ -57,7 +69,7 @@ var clientsListCmd = &cobra.Command{
 		ctx, cancel := buildContext(context.Background())
 		defer cancel()
 
-		return clientsController.Clients(ctx)
+		return clientsController.Clients(ctx, params)
 	},
 }
 
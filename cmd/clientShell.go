@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/breathbath/go_utils/v2/pkg/env"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/client"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/controllers"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+)
+
+func init() {
+	clientShellCmd.Flags().StringP(controllers.ClientNameFlag, "n", "", "Name of the client to open the shell on")
+	clientShellCmd.Flags().StringArray(client.FilterFlag, []string{}, clientFilterHelp+", the filters must select one client")
+	clientShellCmd.Flags().StringP(controllers.Cwd, "w", "", "Initial working directory, the default one of the client if not provided")
+	clientShellCmd.Flags().BoolP(controllers.IsSudo, "u", false, "execute commands as sudo, can be changed in the shell with !sudo on|off")
+	clientShellCmd.Flags().StringP(controllers.Interpreter, "i", "", "interpreter/shell name for the commands execution")
+	clientShellCmd.Flags().IntP(
+		controllers.Timeout,
+		"t",
+		controllers.DefaultCmdTimeoutSeconds,
+		"timeout of each command in seconds, can be changed in the shell with !timeout <SECONDS>",
+	)
+	clientsCmd.AddCommand(clientShellCmd)
+}
+
+var clientShellCmd = &cobra.Command{
+	Use:   "shell [ID|NAME]",
+	Short: "open an interactive shell which executes the entered commands on a client",
+	Long: "open an interactive shell which executes the entered commands on a client, the working directory is kept " +
+		"between commands, Ctrl-C aborts the running command by killing its process on the client, " +
+		"lines starting with ! are executed locally",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, syscall.SIGINT)
+		defer signal.Stop(interrupts)
+
+		tokenValidity := env.ReadEnvInt(config.SessionValiditySecondsEnvVar, api.DefaultTokenValiditySeconds)
+		bearerAuth := buildBearerAuth(params)
+		wsURLBuilder := &api.WsCommandURLProvider{
+			WsURLProvider: &api.WsURLProvider{
+				TokenProvider: func() (string, error) {
+					return bearerAuth.Token(ctx)
+				},
+				BaseURL:              readServerURL(params),
+				TokenValiditySeconds: tokenValidity,
+			},
+		}
+
+		var lineReader controllers.ShellLineReader
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			lineReader = utils.NewTerminalLineReader(os.Stdin, os.Stdout)
+		} else {
+			lineReader = &utils.ScannerLineReader{Sc: bufio.NewScanner(os.Stdin)}
+		}
+
+		rportAPI := buildRportWithBearerAuth(params, bearerAuth)
+		shellController := &controllers.ShellController{
+			ExecutionHelper: &controllers.ExecutionHelper{
+				JobRenderer:  &output.ShellJobRenderer{Writer: os.Stdout},
+				ClientSearch: &client.Search{DataProvider: rportAPI},
+				JobStarter:   rportAPI,
+			},
+			Rport: rportAPI,
+			ReadWriterProvider: func(ctx context.Context) (controllers.ReadWriter, error) {
+				return utils.NewWsClient(ctx, wsURLBuilder.BuildWsURL, bearerAuth)
+			},
+			LineReader: lineReader,
+			LocalCommandRunner: func(ctx context.Context, command string) error {
+				return utils.RunLocalCommand(ctx, command, os.Stdin, os.Stdout, os.Stderr)
+			},
+			Interrupts: interrupts,
+			Writer:     os.Stdout,
+		}
+
+		clientTerm := ""
+		if len(args) > 0 {
+			clientTerm = args[0]
+		}
+
+		return shellController.Start(ctx, clientTerm, params)
+	},
+}
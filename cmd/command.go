@@ -3,12 +3,16 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strconv"
 	"syscall"
 
 	"github.com/breathbath/go_utils/v2/pkg/env"
+	"github.com/fatih/color"
+	"golang.org/x/term"
 
 	options "github.com/breathbath/go_utils/v2/pkg/config"
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/client"
@@ -26,6 +30,30 @@ import (
 func init() {
 	config.DefineCommandInputs(executeCmd, getCommandRequirements())
 	commandCmd.AddCommand(executeCmd)
+
+	listJobsCmd.Flags().StringP(controllers.ClientIDs, "d", "", "Comma separated client ids to list jobs of")
+	listJobsCmd.Flags().StringP(controllers.ClientNameFlag, "n", "", "Comma separated client names to list jobs of")
+	listJobsCmd.Flags().StringP(controllers.JobStatus, "s", "", "Show only jobs with the given status, e.g. successful, failed, running")
+	listJobsCmd.Flags().String(controllers.JobsSince, "", "Show only jobs started after the given time, e.g. 24h, 2021-01-01 or 2021-01-01T10:00:00Z")
+	listJobsCmd.Flags().String(
+		controllers.JobsUntil,
+		"",
+		"Show only jobs started before the given time, e.g. 1h, 2021-01-02T10:00:00Z or 2021-01-02 to include the jobs of the day",
+	)
+	commandCmd.AddCommand(listJobsCmd)
+
+	getJobCmd.Flags().StringP(controllers.ClientID, "c", "", "Client id the job was executed on")
+	getJobCmd.Flags().StringP(controllers.ClientNameFlag, "n", "", "Client name the job was executed on")
+	commandCmd.AddCommand(getJobCmd)
+
+	waitJobCmd.Flags().String(
+		controllers.WaitInterval,
+		controllers.DefaultWaitInterval.String(),
+		"Interval between polls of the job status, e.g. 5s",
+	)
+	waitJobCmd.Flags().BoolP(controllers.IsFullOutput, "f", false, "output detailed information of a job execution")
+	commandCmd.AddCommand(waitJobCmd)
+
 	rootCmd.AddCommand(commandCmd)
 }
 
@@ -40,62 +68,202 @@ var executeCmd = &cobra.Command{
 	Short: "executes a remote command on an rport client(s)",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := config.LoadParamsFromFileAndEnvAndFlagsAndPrompt(cmd, getCommandRequirements(), newPromptReader())
+		if err != nil {
+			return err
+		}
+
 		ctx, cancel := buildContext(context.Background())
 		defer cancel()
 
-		sigs := make(chan os.Signal, 1)
-		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
-		promptReader := &utils.PromptReader{
-			Sc:              bufio.NewScanner(os.Stdin),
-			SigChan:         sigs,
-			PasswordScanner: utils.ReadPassword,
-		}
-
-		params, err := config.LoadParamsFromFileAndEnvAndFlagsAndPrompt(cmd, getCommandRequirements(), promptReader)
+		executionHelper, err := createCommandExecutionHelper(ctx, params)
 		if err != nil {
 			return err
 		}
 
-		tokenValidity := env.ReadEnvInt(config.SessionValiditySecondsEnvVar, api.DefaultTokenValiditySeconds)
-
-		baseRportURL := params.ReadString(config.ServerURL, config.DefaultServerURL)
-		wsURLBuilder := &api.WsCommandURLProvider{
-			WsURLProvider: &api.WsURLProvider{
-				TokenProvider: func() (token string, err error) {
-					token = params.ReadString(config.Token, "")
-					return
-				},
-				BaseURL:              baseRportURL,
-				TokenValiditySeconds: tokenValidity,
-			},
+		cmdExecutor := &controllers.CommandsController{
+			ExecutionHelper: executionHelper,
 		}
-		wsClient, err := utils.NewWsClient(ctx, wsURLBuilder.BuildWsURL)
+
+		return cmdExecutor.Start(ctx, params)
+	},
+}
+
+var listJobsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list jobs of the given clients or multi client jobs if no clients are given",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createJobsController(params, false).List(ctx, params)
+	},
+}
+
+var getJobCmd = &cobra.Command{
+	Use:   "get <JID>",
+	Short: "get a job of a client if client id or name is given, otherwise a multi client job with the results of all its jobs",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createJobsController(params, true).Get(ctx, args[0], params)
+	},
+}
+
+var waitJobCmd = &cobra.Command{
+	Use:   "wait <MULTI_JOB_ID>",
+	Short: "wait for a multi client job started with --detach and render results of each client as soon as they are finished",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		isFullJobOutput := params.ReadBool(controllers.IsFullOutput, false)
+
+		return createJobsController(params, isFullJobOutput).Wait(ctx, args[0], params)
+	},
+}
+
+func newPromptReader() *utils.PromptReader {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+	return &utils.PromptReader{
+		Sc:              bufio.NewScanner(os.Stdin),
+		SigChan:         sigs,
+		PasswordScanner: utils.ReadPassword,
+	}
+}
+
+func createCommandExecutionHelper(ctx context.Context, params *options.ParameterBag) (*controllers.ExecutionHelper, error) {
+	tokenValidity := env.ReadEnvInt(config.SessionValiditySecondsEnvVar, api.DefaultTokenValiditySeconds)
+
+	baseRportURL := params.ReadString(config.ServerURL, config.DefaultServerURL)
+	bearerAuth := buildBearerAuth(params)
+	wsURLBuilder := &api.WsCommandURLProvider{
+		WsURLProvider: &api.WsURLProvider{
+			TokenProvider: func() (string, error) {
+				return bearerAuth.Token(ctx)
+			},
+			BaseURL:              baseRportURL,
+			TokenValiditySeconds: tokenValidity,
+		},
+	}
+
+	var readWriter controllers.ReadWriter
+	var err error
+	if !params.ReadBool(controllers.Detach, false) {
+		readWriter, err = utils.NewWsClient(ctx, wsURLBuilder.BuildWsURL, bearerAuth)
 		if err != nil {
-			return err
+			return nil, err
 		}
+	}
+
+	rportAPI := buildRportWithBearerAuth(params, bearerAuth)
+	clientSearch := &client.Search{
+		DataProvider: rportAPI,
+	}
+
+	jobSaver, err := buildJobSaver(params)
+	if err != nil {
+		return nil, err
+	}
+
+	spinner, jobWriter := buildJobSpinner(params)
+	jobRenderer, err := buildExecJobRenderer(params, jobWriter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &controllers.ExecutionHelper{
+		ReadWriter:   readWriter,
+		JobRenderer:  jobRenderer,
+		ClientSearch: clientSearch,
+		JobStarter:   rportAPI,
+		Spinner:      spinner,
+		SummaryRenderer: &output.JobsSummaryRenderer{
+			ColCountCalculator: utils.CalcTerminalColumnsCount,
+			Writer:             os.Stderr,
+		},
+		JobSaver: jobSaver,
+	}, nil
+}
+
+func createJobsController(params *options.ParameterBag, isFullOutput bool) *controllers.JobsController {
+	rportAPI := buildRport(params)
 
-		rportAPI := buildRport(params)
-		clientSearch := &client.Search{
+	return &controllers.JobsController{
+		Rport: rportAPI,
+		ClientSearch: &client.Search{
 			DataProvider: rportAPI,
-		}
+		},
+		JobRenderer: &output.JobRenderer{
+			ColCountCalculator: utils.CalcTerminalColumnsCount,
+			Writer:             os.Stdout,
+			Format:             getOutputFormat(),
+			IsFullOutput:       isFullOutput,
+			Columns:            getColumns(),
+		},
+	}
+}
 
-		isFullJobOutput := params.ReadBool(controllers.IsFullOutput, false)
-		cmdExecutor := &controllers.CommandsController{
-			ExecutionHelper: &controllers.ExecutionHelper{
-				ReadWriter: wsClient,
-				JobRenderer: &output.JobRenderer{
-					Writer:       os.Stdout,
-					Format:       getOutputFormat(),
-					IsFullOutput: isFullJobOutput,
-				},
-				ClientSearch: clientSearch,
-			},
+// buildJobSpinner shows the progress of each client on stderr, as a live table if it's a terminal and as line events
+// otherwise, job results should be written to the returned writer, so they are printed above the live table
+func buildJobSpinner(params *options.ParameterBag) (controllers.Spinner, io.Writer) {
+	if params.ReadBool(controllers.NoProgress, false) || params.ReadBool(controllers.Detach, false) {
+		return nil, os.Stdout
+	}
+
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		spinner := &output.TerminalSpinner{
+			Writer:             color.Error,
+			ColCountCalculator: utils.CalcTerminalColumnsCount,
 		}
+		return spinner, spinner.Bypass(os.Stdout)
+	}
 
-		err = cmdExecutor.Start(ctx, params)
+	return &output.LineSpinner{Writer: os.Stderr}, os.Stdout
+}
 
-		return err
-	},
+// buildExecJobRenderer gives a renderer of the results of executed commands and scripts, results grouped by output
+// can't be rendered as delimited values
+func buildExecJobRenderer(params *options.ParameterBag, w io.Writer) (*output.JobRenderer, error) {
+	format := getOutputFormat()
+	groupByOutput := params.ReadBool(controllers.GroupByOutput, false)
+	if groupByOutput && output.IsDelimitedFormat(format) {
+		return nil, fmt.Errorf("--%s is not supported for %s format", controllers.GroupByOutput, format)
+	}
+
+	return &output.JobRenderer{
+		Writer:        w,
+		Format:        format,
+		IsFullOutput:  params.ReadBool(controllers.IsFullOutput, false),
+		GroupByOutput: groupByOutput,
+	}, nil
+}
+
+// buildJobSaver gives a writer of job results to files if an output directory is provided
+func buildJobSaver(params *options.ParameterBag) (controllers.JobSaver, error) {
+	dir := params.ReadString(controllers.OutputDir, "")
+	if dir == "" {
+		return nil, nil
+	}
+
+	jobFilesWriter, err := output.NewJobFilesWriter(dir, params.ReadString(controllers.OutputFileTemplate, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	return jobFilesWriter, nil
 }
 
 func getCommandRequirements() []config.ParameterRequirement {
@@ -105,10 +273,10 @@ func getCommandRequirements() []config.ParameterRequirement {
 			Help:     "Enter comma separated client IDs",
 			Validate: config.RequiredValidate,
 			Description: "[required] Comma separated client ids for which the command should be executed. " +
-				"Alternatively use -n to execute a command by client name(s)",
+				"Alternatively use -n or --filter to execute a command on clients selected by name(s) or filters",
 			ShortName: "d",
 			IsEnabled: func(providedParams *options.ParameterBag) bool {
-				return providedParams.ReadString(controllers.ClientNameFlag, "") == ""
+				return providedParams.ReadString(controllers.ClientNameFlag, "") == "" && !client.HasFilters(providedParams)
 			},
 			IsRequired: true,
 		},
@@ -117,6 +285,11 @@ func getCommandRequirements() []config.ParameterRequirement {
 			Description: "Comma separated client names for which the command should be executed",
 			ShortName:   "n",
 		},
+		{
+			Field:       client.FilterFlag,
+			Description: clientFilterHelp,
+			Type:        config.StringArrayRequirementType,
+		},
 		{
 			Field:       controllers.Command,
 			Help:        "Enter command",
@@ -185,5 +358,36 @@ func getCommandRequirements() []config.ParameterRequirement {
 			Type:        config.StringRequirementType,
 			Default:     "",
 		},
+		{
+			Field:       controllers.Detach,
+			Description: "start the command in background and print the job id without waiting for the results",
+			Type:        config.BoolRequirementType,
+			Default:     false,
+		},
+		{
+			Field:       controllers.OutputDir,
+			Description: "save stdout, stderr and metadata json of each client to files in the directory",
+			Type:        config.StringRequirementType,
+		},
+		{
+			Field: controllers.OutputFileTemplate,
+			Description: "go template of the file names in the output directory without extension, fields are " +
+				".Client (name or id), .ClientID, .ClientName, .Jid, .MultiJobID, .Status, .StartedAt and .FinishedAt",
+			Type:    config.StringRequirementType,
+			Default: output.DefaultJobFileNameTemplate,
+		},
+		{
+			Field: controllers.GroupByOutput,
+			Description: "render each distinct output once with the clients which produced it " +
+				"and a diff against the output of the majority of clients",
+			Type:    config.BoolRequirementType,
+			Default: false,
+		},
+		{
+			Field:       controllers.NoProgress,
+			Description: "don't show the progress of each client on stderr while the command runs",
+			Type:        config.BoolRequirementType,
+			Default:     false,
+		},
 	}
 }
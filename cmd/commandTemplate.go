@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+	"github.com/spf13/cobra"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/controllers"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+)
+
+func init() {
+	commandTemplateCmd.AddCommand(commandTemplateListCmd)
+	commandTemplateCmd.AddCommand(commandTemplateGetCmd)
+
+	commandTemplateCreateCmd.Flags().StringP(
+		controllers.Command,
+		"c",
+		"",
+		"[required] Command with {{param}} placeholders, e.g. 'systemctl restart {{service}}'",
+	)
+	commandTemplateCreateCmd.Flags().StringArray(
+		controllers.CommandTemplateTag,
+		[]string{},
+		"Tag of the command template, can be repeated or comma separated",
+	)
+	commandTemplateCmd.AddCommand(commandTemplateCreateCmd)
+
+	commandTemplateUpdateCmd.Flags().String(controllers.CommandTemplateNewName, "", "New name of the command template")
+	commandTemplateUpdateCmd.Flags().StringP(controllers.Command, "c", "", "New command with {{param}} placeholders")
+	commandTemplateUpdateCmd.Flags().StringArray(
+		controllers.CommandTemplateTag,
+		[]string{},
+		"Tag of the command template, can be repeated or comma separated, if provided, all existing tags are replaced",
+	)
+	commandTemplateCmd.AddCommand(commandTemplateUpdateCmd)
+
+	commandTemplateCmd.AddCommand(commandTemplateDeleteCmd)
+
+	config.DefineCommandInputs(commandTemplateRunCmd, getCommandTemplateRunRequirements())
+	commandTemplateCmd.AddCommand(commandTemplateRunCmd)
+
+	commandCmd.AddCommand(commandTemplateCmd)
+}
+
+var commandTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "stored commands with {{param}} placeholders, kept on the rport server or locally if the server doesn't support it",
+	Args:  cobra.ArbitraryArgs,
+}
+
+var commandTemplateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list stored command templates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createCommandTemplatesController(params, nil).List(ctx)
+	},
+}
+
+var commandTemplateGetCmd = &cobra.Command{
+	Use:   "get <ID|NAME>",
+	Short: "get details and params of a command template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createCommandTemplatesController(params, nil).Get(ctx, args[0])
+	},
+}
+
+var commandTemplateCreateCmd = &cobra.Command{
+	Use:   "create <NAME>",
+	Short: "store a command template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createCommandTemplatesController(params, nil).Create(ctx, args[0], params)
+	},
+}
+
+var commandTemplateUpdateCmd = &cobra.Command{
+	Use:   "update <ID|NAME>",
+	Short: "update a command template, only the provided options are changed",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createCommandTemplatesController(params, nil).Update(ctx, args[0], params)
+	},
+}
+
+var commandTemplateDeleteCmd = &cobra.Command{
+	Use:   "delete <ID|NAME>",
+	Short: "delete a command template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createCommandTemplatesController(params, nil).Delete(ctx, args[0])
+	},
+}
+
+var commandTemplateRunCmd = &cobra.Command{
+	Use:   "run <ID|NAME>",
+	Short: "executes a command template on rport client(s), values of params which are not provided are prompted",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := config.LoadParamsFromFileAndEnvAndFlagsAndPrompt(cmd, getCommandTemplateRunRequirements(), newPromptReader())
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		executionHelper, err := createCommandExecutionHelper(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		return createCommandTemplatesController(params, executionHelper).Run(ctx, args[0], params)
+	},
+}
+
+func createCommandTemplatesController(
+	params *options.ParameterBag,
+	executionHelper *controllers.ExecutionHelper,
+) *controllers.CommandTemplatesController {
+	return &controllers.CommandTemplatesController{
+		Rport:      buildRport(params),
+		LocalStore: config.NewLocalCommandTemplates(),
+		Renderer: &output.CommandTemplateRenderer{
+			ColCountCalculator: utils.CalcTerminalColumnsCount,
+			Writer:             os.Stdout,
+			Format:             getOutputFormat(),
+		},
+		CommandsController: &controllers.CommandsController{
+			ExecutionHelper: executionHelper,
+		},
+		PromptReader: newPromptReader(),
+	}
+}
+
+// getCommandTemplateRunRequirements are options of command execution except the command which comes from the template
+func getCommandTemplateRunRequirements() []config.ParameterRequirement {
+	reqs := getCommandRequirements()
+	runReqs := make([]config.ParameterRequirement, 0, len(reqs)+1)
+	for _, req := range reqs {
+		if req.Field == controllers.Command {
+			continue
+		}
+		runReqs = append(runReqs, req)
+	}
+
+	return append(runReqs, config.ParameterRequirement{
+		Field:       controllers.CommandTemplateParam,
+		Description: "Value of a param of the command template as key=value, can be repeated, missing values are prompted",
+		Type:        config.StringArrayRequirementType,
+	})
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/controllers"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestExecuteCommandWithAPITokenProfile(t *testing.T) {
+	submittedCommands := []models.WsScriptCommand{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(api.LoginURL, func(rw http.ResponseWriter, r *http.Request) {
+		login, pass, ok := r.BasicAuth()
+		if !ok || login != "admin" || pass != "some api token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		err := json.NewEncoder(rw).Encode(api.LoginResponse{Data: models.Token{Token: "session-token"}})
+		assert.NoError(t, err)
+	})
+	mux.HandleFunc(api.CommandsWSUri, func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "session-token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(rw, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		wsCmd := models.WsScriptCommand{}
+		err = conn.ReadJSON(&wsCmd)
+		require.NoError(t, err)
+		submittedCommands = append(submittedCommands, wsCmd)
+
+		err = conn.WriteJSON(models.Job{
+			Jid:      "1",
+			ClientID: "cl1",
+			Command:  wsCmd.Command,
+			Status:   models.JobStatusSuccessful,
+			Result:   models.JobResult{Stdout: "ok"},
+		})
+		require.NoError(t, err)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	params := config.FromValues(map[string]string{
+		config.ServerURL:       srv.URL,
+		config.Login:           "admin",
+		config.APIToken:        "some api token",
+		controllers.ClientIDs:  "cl1",
+		controllers.Command:    "date",
+		controllers.NoProgress: "1",
+	})
+
+	ctx := context.Background()
+	executionHelper, err := createCommandExecutionHelper(ctx, params)
+	require.NoError(t, err)
+
+	cmdExecutor := &controllers.CommandsController{
+		ExecutionHelper: executionHelper,
+	}
+	err = cmdExecutor.Start(ctx, params)
+	require.NoError(t, err)
+
+	require.Len(t, submittedCommands, 1)
+	assert.Equal(t, "date", submittedCommands[0].Command)
+	assert.Equal(t, []string{"cl1"}, submittedCommands[0].ClientIDs)
+}
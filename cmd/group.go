@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/controllers"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const groupParamHelp = "Param to match clients of the group in format key=value1,value2, values might contain wildcards, " +
+	"can be repeated, supported keys: client_id, name, os, os_arch, os_family, os_kernel, hostname, ipv4, ipv6, tag, " +
+	"version, address, client_auth_id"
+
+func init() {
+	groupCmd.AddCommand(groupListCmd)
+	groupCmd.AddCommand(groupGetCmd)
+
+	groupCreateCmd.Flags().StringP(controllers.GroupDescription, "d", "", "Description of the client group")
+	groupCreateCmd.Flags().StringArrayP(controllers.GroupParam, "p", []string{}, groupParamHelp)
+	groupCmd.AddCommand(groupCreateCmd)
+
+	groupUpdateCmd.Flags().StringP(controllers.GroupDescription, "d", "", "New description of the client group")
+	groupUpdateCmd.Flags().StringArrayP(
+		controllers.GroupParam,
+		"p",
+		[]string{},
+		groupParamHelp+", if provided, all existing params of the group are replaced",
+	)
+	groupCmd.AddCommand(groupUpdateCmd)
+
+	groupCmd.AddCommand(groupDeleteCmd)
+
+	rootCmd.AddCommand(groupCmd)
+}
+
+var groupCmd = &cobra.Command{
+	Use:   "group [command]",
+	Short: "manage client groups",
+	Args:  cobra.ArbitraryArgs,
+}
+
+var groupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list all client groups",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createClientGroupController(params).List(ctx)
+	},
+}
+
+var groupGetCmd = &cobra.Command{
+	Use:   "get <ID>",
+	Short: "get details about a client group including its member clients",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createClientGroupController(params).Get(ctx, args[0])
+	},
+}
+
+var groupCreateCmd = &cobra.Command{
+	Use:   "create <ID>",
+	Short: "create a client group, its members are all clients matching the provided params",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createClientGroupController(params).Create(ctx, args[0], params)
+	},
+}
+
+var groupUpdateCmd = &cobra.Command{
+	Use:   "update <ID>",
+	Short: "update description or params of a client group",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createClientGroupController(params).Update(ctx, args[0], params)
+	},
+}
+
+var groupDeleteCmd = &cobra.Command{
+	Use:   "delete <ID>",
+	Short: "delete a client group, the member clients are not affected",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createClientGroupController(params).Delete(ctx, args[0])
+	},
+}
+
+func createClientGroupController(params *options.ParameterBag) *controllers.ClientGroupController {
+	return &controllers.ClientGroupController{
+		Rport: buildRport(params),
+		ClientGroupRenderer: &output.ClientGroupRenderer{
+			ColCountCalculator: utils.CalcTerminalColumnsCount,
+			Writer:             os.Stdout,
+			Format:             getOutputFormat(),
+		},
+	}
+}
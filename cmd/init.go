@@ -3,10 +3,14 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
@@ -46,7 +50,9 @@ func manageLogout(ctx context.Context, cmd *cobra.Command) error {
 
 	rportAPI := buildRport(params)
 
-	logoutController := controllers.NewLogoutController(rportAPI, config.DeleteConfig)
+	logoutController := controllers.NewLogoutController(rportAPI, func() error {
+		return config.DeleteConfig(params)
+	})
 
 	return logoutController.Logout(ctx, params)
 }
@@ -74,6 +80,7 @@ func manageInit(ctx context.Context, cmd *cobra.Command) error {
 			Format:             getOutputFormat(),
 		},
 		QrImageWriterProvider: output.GetQrImageFsWriter,
+		QrImageDeleter:        output.DeleteQrImageFile,
 	}
 
 	return initController.InitConfig(ctx, params)
@@ -102,6 +109,57 @@ func getInitRequirements() []config.ParameterRequirement {
 			Description: "Password to the rport server",
 			ShortName:   "p",
 			IsSecure:    true,
+			IsEnabled: func(providedParams *options.ParameterBag) bool {
+				return providedParams.ReadString(config.APIToken, "") == ""
+			},
+		},
+		{
+			Field: config.APIToken,
+			Description: fmt.Sprintf(
+				"API token to use instead of the password, the token is used for basic auth and no session token is stored, "+
+					"can be also set by %s env variable",
+				config.APITokenEnvVar,
+			),
+			IsSecure: true,
+		},
+		{
+			Field: config.TwoFACode,
+			Description: fmt.Sprintf(
+				"Code for 2 factor auth, e.g. sent by email or generated by an Authenticator app, "+
+					"if not provided, it will be asked interactively, can be also set by %s env variable",
+				config.TwoFACodeEnvVar,
+			),
+		},
+		{
+			Field: config.TotPSecret,
+			Description: fmt.Sprintf(
+				"Authenticator app secret key to generate the 2 factor auth code for unattended logins, "+
+					"can be also set by %s env variable",
+				config.TotPSecretEnvVar,
+			),
+			IsSecure: true,
+		},
+		{
+			Field: controllers.SaveQrImage,
+			Description: "Save the qr code image of a new Authenticator app secret key to a temp file additionally " +
+				"to showing it in the terminal, the file is deleted after the login",
+			Type: config.BoolRequirementType,
+		},
+		{
+			Field:    config.SecretStoreFlag,
+			Help:     fmt.Sprintf("Enter secret store to keep the auth token in (%s)", strings.Join(config.SecretStoreNames(), ", ")),
+			Validate: config.ValidateSecretStore,
+			Description: fmt.Sprintf(
+				"Where to store the auth token: %s - in the config file, "+
+					"%s - in the Secret Service keyring (Linux, requires secret-tool), "+
+					"%s - in a file encrypted with a passphrase from %s env variable or prompt. "+
+					"If not set, the secret store of the profile or %s is used",
+				config.SecretStorePlain,
+				config.SecretStoreKeyring,
+				config.SecretStoreEncrypted,
+				config.SecretsPassphraseEnvVar,
+				config.SecretStorePlain,
+			),
 		},
 	}
 }
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/controllers"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	profileCmd.AddCommand(profileListCmd)
+
+	profileAddCmd.Flags().StringP(config.ServerURL, "s", "", "[required] Server address of rport to connect to")
+	profileAddCmd.Flags().BoolP(controllers.UseProfile, "u", false, "Switch to the new profile")
+	profileAddCmd.Flags().String(
+		config.SecretStoreFlag,
+		"",
+		fmt.Sprintf(
+			"Secret store to keep the auth token in: %s, the %s one requires secret-tool on Linux",
+			strings.Join(config.SecretStoreNames(), ", "),
+			config.SecretStoreKeyring,
+		),
+	)
+	profileCmd.AddCommand(profileAddCmd)
+
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileRemoveCmd)
+	profileCmd.AddCommand(profileRenameCmd)
+
+	rootCmd.AddCommand(profileCmd)
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile [command]",
+	Short: "manage config profiles for multiple rport servers",
+	Args:  cobra.ArbitraryArgs,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list all config profiles, the current one is marked with *",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createProfileController().List(ctx)
+	},
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add <NAME>",
+	Short: "add a new config profile, call init with --profile <NAME> afterwards to login",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		// values of the current profile must not leak into the new one
+		params := config.LoadParamsFromFlags(cmd.Flags())
+
+		return createProfileController().Add(ctx, args[0], params)
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <NAME>",
+	Short: "switch the current config profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createProfileController().Use(ctx, args[0])
+	},
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:   "remove <NAME>",
+	Short: "remove a config profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createProfileController().Remove(ctx, args[0])
+	},
+}
+
+var profileRenameCmd = &cobra.Command{
+	Use:   "rename <OLD_NAME> <NEW_NAME>",
+	Short: "rename a config profile",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createProfileController().Rename(ctx, args[0], args[1])
+	},
+}
+
+func createProfileController() *controllers.ProfileController {
+	return &controllers.ProfileController{
+		ProfilesReader: config.ReadProfiles,
+		ProfilesWriter: config.WriteProfiles,
+		SecretsDeleter: config.DeleteSecrets,
+		ProfileRenderer: &output.ProfileRenderer{
+			ColCountCalculator: utils.CalcTerminalColumnsCount,
+			Writer:             os.Stdout,
+			Format:             getOutputFormat(),
+		},
+	}
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	options "github.com/breathbath/go_utils/v2/pkg/config"
+	"github.com/breathbath/go_utils/v2/pkg/env"
+	"github.com/sirupsen/logrus"
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/applog"
@@ -18,6 +22,7 @@ var (
 	OutputFormat = output.FormatHuman
 	Timeout      = ""
 	IsJSONPretty = false
+	Columns      = ""
 	rootCmd      = &cobra.Command{
 		Use:           "rportcli",
 		Short:         "Rport cli",
@@ -25,12 +30,23 @@ var (
 		SilenceErrors: true,
 		SilenceUsage:  true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if OutputFormat != "" && OutputFormat != output.FormatHuman && OutputFormat != output.FormatYAML && OutputFormat != output.FormatJSON {
+			if output.IsTemplateFormat(OutputFormat) {
+				return output.ValidateTemplateFormat(OutputFormat)
+			}
+			if OutputFormat != "" &&
+				OutputFormat != output.FormatHuman &&
+				OutputFormat != output.FormatYAML &&
+				OutputFormat != output.FormatJSON &&
+				!output.IsDelimitedFormat(OutputFormat) {
 				return fmt.Errorf(
-					"unknown format '%s', supported formats are %s, %s",
+					"unknown format '%s', supported formats are %s, %s, %s, %s, %s<template> and %s<template>",
 					OutputFormat,
 					output.FormatJSON,
 					output.FormatYAML,
+					output.FormatCSV,
+					output.FormatTSV,
+					output.FormatGoTemplatePrefix,
+					output.FormatJSONPathPrefix,
 				)
 			}
 			return nil
@@ -49,6 +65,19 @@ func getOutputFormat() string {
 	return OutputFormat
 }
 
+func getColumns() []string {
+	if Columns == "" {
+		return nil
+	}
+
+	columns := strings.Split(Columns, ",")
+	for i := range columns {
+		columns[i] = strings.TrimSpace(columns[i])
+	}
+
+	return columns
+}
+
 func init() {
 	cobra.OnInitialize(initLog)
 	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "verbose output")
@@ -64,7 +93,25 @@ func init() {
 		"output",
 		"o",
 		output.FormatHuman,
-		fmt.Sprintf("Output format: %s, %s or %s", output.FormatJSON, output.FormatYAML, output.FormatHuman),
+		fmt.Sprintf(
+			"Output format: %s, %s, %s, %s, %s, %s<go template> e.g. %s'{{range .}}{{.id}}{{\"\\n\"}}{{end}}' "+
+				"or %s<jsonpath template> e.g. %s'{.[*].id}', both templates use the field names of the json output",
+			output.FormatJSON,
+			output.FormatYAML,
+			output.FormatCSV,
+			output.FormatTSV,
+			output.FormatHuman,
+			output.FormatGoTemplatePrefix,
+			output.FormatGoTemplatePrefix,
+			output.FormatJSONPathPrefix,
+			output.FormatJSONPathPrefix,
+		),
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&Columns,
+		"columns",
+		"",
+		"Comma separated columns to show in tables of clients, tunnels and jobs, e.g. id,name,os_full_name,ipv4,tags",
 	)
 	rootCmd.PersistentFlags().StringVarP(
 		&Timeout,
@@ -73,6 +120,11 @@ func init() {
 		"",
 		"Timeout value as seconds, e.g. 10s, minutes e.g. 1m or hours e.g. 2h, if not provided no timeout will be set",
 	)
+	rootCmd.PersistentFlags().String(
+		config.Profile,
+		"",
+		fmt.Sprintf("Name of the config profile to use, can be also set by %s env variable", config.ProfileEnvVar),
+	)
 }
 
 func initLog() {
@@ -88,25 +140,94 @@ func Execute() error {
 }
 
 func buildRport(params *options.ParameterBag) *api.Rport {
+	return buildRportWithBearerAuth(params, buildBearerAuth(params))
+}
+
+// buildRportWithBearerAuth uses the stored token, which is refreshed if the server rejects it, and basic auth
+// with login and password or api token if no token is stored
+func buildRportWithBearerAuth(params *options.ParameterBag, bearerAuth *utils.BearerAuth) *api.Rport {
+	if params.ReadString(config.Token, "") != "" {
+		return api.New(readServerURL(params), bearerAuth)
+	}
+
 	auth := &utils.FallbackAuth{
 		PrimaryAuth: &utils.StorageBasicAuth{
 			AuthProvider: func() (login, pass string, err error) {
 				login = params.ReadString(config.Login, "")
 				pass = params.ReadString(config.Password, "")
+				if pass == "" {
+					// profiles initialized with an api token use it as password for basic auth
+					pass = params.ReadString(config.APIToken, "")
+				}
 				return
 			},
 		},
-		FallbackAuth: &utils.BearerAuth{
-			TokenProvider: func() (string, error) {
-				return params.ReadString(config.Token, ""), nil
-			},
+		FallbackAuth: bearerAuth,
+	}
+
+	return api.New(readServerURL(params), auth)
+}
+
+// buildBearerAuth creates auth with the stored token, which is refreshed by logging in again
+// if the token expires and login and password are provided e.g. by env variables,
+// profiles initialized with an api token get a session token by logging in with it, e.g. to open websockets
+func buildBearerAuth(params *options.ParameterBag) *utils.BearerAuth {
+	bearerAuth := &utils.BearerAuth{
+		TokenProvider: func() (string, error) {
+			return params.ReadString(config.Token, ""), nil
 		},
 	}
+
+	login := params.ReadString(config.Login, "")
+	pass := params.ReadString(config.Password, "")
+	isAPITokenLogin := false
+	if pass == "" {
+		pass = params.ReadString(config.APIToken, "")
+		isAPITokenLogin = true
+	}
+	if login == "" || pass == "" {
+		return bearerAuth
+	}
+
+	bearerAuth.TokenRefresher = func(ctx context.Context) (string, error) {
+		loginAPI := api.New(readServerURL(params), &utils.StorageBasicAuth{
+			AuthProvider: func() (string, string, error) {
+				return login, pass, nil
+			},
+		})
+		tokenValidity := env.ReadEnvInt(config.SessionValiditySecondsEnvVar, api.DefaultTokenValiditySeconds)
+		token, err := loginAPI.RefreshToken(ctx, tokenValidity)
+		if err != nil {
+			return "", err
+		}
+
+		// no session token is stored for profiles initialized with an api token
+		if isAPITokenLogin {
+			return token, nil
+		}
+
+		refreshedParams := options.New(options.NewValuesProviderComposite(
+			options.NewMapValuesProvider(map[string]interface{}{
+				config.Token: token,
+			}),
+			params.BaseValuesProvider,
+		))
+		err = config.WriteConfig(refreshedParams)
+		if err != nil {
+			logrus.Warnf("failed to store the refreshed auth token: %v", err)
+		}
+
+		return token, nil
+	}
+
+	return bearerAuth
+}
+
+func readServerURL(params *options.ParameterBag) string {
 	serverURL := params.ReadString(config.ServerURL, config.DefaultServerURL)
 	if serverURL == "" {
 		serverURL = config.DefaultServerURL
 	}
-	rportAPI := api.New(serverURL, auth)
 
-	return rportAPI
+	return serverURL
 }
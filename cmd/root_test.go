@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+)
+
+func newRefreshingServer(t *testing.T) (srv *httptest.Server, loginsCount *int) {
+	loginsCount = new(int)
+	mux := http.NewServeMux()
+	mux.HandleFunc(api.LoginURL, func(rw http.ResponseWriter, r *http.Request) {
+		login, pass, ok := r.BasicAuth()
+		if !ok || login != "admin" || pass != "secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		*loginsCount++
+		err := json.NewEncoder(rw).Encode(api.LoginResponse{Data: models.Token{Token: "new-token"}})
+		assert.NoError(t, err)
+	})
+	mux.HandleFunc(api.MeURL, func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer new-token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			_, err := rw.Write([]byte(`{"errors":[{"code":"401","title":"unauthorized"}]}`))
+			assert.NoError(t, err)
+			return
+		}
+		err := json.NewEncoder(rw).Encode(api.UserResponse{Data: models.Me{Username: "admin"}})
+		assert.NoError(t, err)
+	})
+	mux.HandleFunc(api.CommandsWSUri, func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "new-token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(rw, r, nil)
+		require.NoError(t, err)
+		assert.NoError(t, conn.Close())
+	})
+
+	return httptest.NewServer(mux), loginsCount
+}
+
+func setTestConfigPath(t *testing.T) {
+	err := os.Setenv(config.PathForConfigEnvVar, filepath.Join(t.TempDir(), "config.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, os.Unsetenv(config.PathForConfigEnvVar))
+	})
+}
+
+func TestBuildRportRefreshesRejectedToken(t *testing.T) {
+	setTestConfigPath(t)
+	srv, loginsCount := newRefreshingServer(t)
+	defer srv.Close()
+
+	params := config.FromValues(map[string]string{
+		config.ServerURL: srv.URL,
+		config.Token:     "expired-token",
+		config.Login:     "admin",
+		config.Password:  "secret",
+	})
+
+	userResp, err := buildRport(params).Me(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "admin", userResp.Data.Username)
+	assert.Equal(t, 1, *loginsCount)
+
+	profiles, err := config.ReadProfiles()
+	require.NoError(t, err)
+	storedValues, ok := profiles.Get(profiles.Resolve(""))
+	require.True(t, ok)
+	assert.Equal(t, "new-token", storedValues[config.Token])
+}
+
+func TestWsClientRefreshesRejectedToken(t *testing.T) {
+	setTestConfigPath(t)
+	srv, loginsCount := newRefreshingServer(t)
+	defer srv.Close()
+
+	params := config.FromValues(map[string]string{
+		config.ServerURL: srv.URL,
+		config.Token:     "expired-token",
+		config.Login:     "admin",
+		config.Password:  "secret",
+	})
+	ctx := context.Background()
+
+	bearerAuth := buildBearerAuth(params)
+	wsURLBuilder := &api.WsCommandURLProvider{
+		WsURLProvider: &api.WsURLProvider{
+			TokenProvider: func() (string, error) {
+				return bearerAuth.Token(ctx)
+			},
+			BaseURL: srv.URL,
+		},
+	}
+
+	wsClient, err := utils.NewWsClient(ctx, wsURLBuilder.BuildWsURL, bearerAuth)
+	require.NoError(t, err)
+	assert.NoError(t, wsClient.Close())
+	assert.Equal(t, 1, *loginsCount)
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+	"github.com/spf13/cobra"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/controllers"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+)
+
+const (
+	scheduleCronHelp = "Cron expression with minute, hour, day of month, month and day of week in the timezone of the rport server, " +
+		"e.g. '*/15 8-18 * * mon-fri', or a macro like @hourly, @daily, @weekly, @monthly"
+	scheduleTimezoneHelp = "Timezone of the rport server in which the next runs are calculated and shown, e.g. Europe/Berlin"
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{scheduleListCmd, scheduleGetCmd, scheduleCreateCmd, scheduleUpdateCmd} {
+		cmd.Flags().String(controllers.ScheduleTimezone, "UTC", scheduleTimezoneHelp)
+	}
+
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleGetCmd)
+
+	scheduleCreateCmd.Flags().String(controllers.ScheduleCron, "", "[required] "+scheduleCronHelp)
+	defineScheduleDetailsFlags(scheduleCreateCmd)
+	scheduleCmd.AddCommand(scheduleCreateCmd)
+
+	scheduleUpdateCmd.Flags().String(controllers.ScheduleNewName, "", "New name of the schedule")
+	scheduleUpdateCmd.Flags().String(controllers.ScheduleCron, "", scheduleCronHelp)
+	defineScheduleDetailsFlags(scheduleUpdateCmd)
+	scheduleCmd.AddCommand(scheduleUpdateCmd)
+
+	scheduleCmd.AddCommand(scheduleDeleteCmd)
+	scheduleCmd.AddCommand(scheduleRunCopyCmd)
+
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+func defineScheduleDetailsFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(controllers.ClientIDs, "d", "", "Comma separated client ids on which the command or script should be executed")
+	cmd.Flags().StringP(controllers.GroupIDs, "g", "", "Comma separated client group IDs")
+	cmd.Flags().StringP(controllers.Command, "c", "", "Command which should be executed, either a command or a script is required")
+	cmd.Flags().StringP(controllers.Script, "s", "", "Path to the script file which should be executed")
+	cmd.Flags().StringP(
+		controllers.Interpreter,
+		"i",
+		"",
+		"interpreter/shell name for the execution, for scripts detected by the file extension if not provided",
+	)
+	cmd.Flags().StringP(controllers.Cwd, "w", "", "current working directory")
+	cmd.Flags().BoolP(controllers.IsSudo, "u", false, "execute as sudo")
+	cmd.Flags().Int(controllers.ScheduleTimeout, controllers.DefaultCmdTimeoutSeconds, "timeout in seconds of each execution")
+	cmd.Flags().BoolP(controllers.ExecConcurrently, "r", false, "execute concurrently on multiple clients")
+	cmd.Flags().BoolP(controllers.AbortOnError, "a", false, "if true and the execution fails on one client, it's not executed on others")
+}
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "commands and scripts executed periodically by the rport server",
+	Args:  cobra.ArbitraryArgs,
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list schedules with their next run",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		sc, err := createSchedulesController(params)
+		if err != nil {
+			return err
+		}
+
+		return sc.List(ctx)
+	},
+}
+
+var scheduleGetCmd = &cobra.Command{
+	Use:   "get <ID|NAME>",
+	Short: "get details of a schedule with its next 5 runs",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		sc, err := createSchedulesController(params)
+		if err != nil {
+			return err
+		}
+
+		return sc.Get(ctx, args[0])
+	},
+}
+
+var scheduleCreateCmd = &cobra.Command{
+	Use:   "create <NAME>",
+	Short: "create a schedule of a command or a script",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		sc, err := createSchedulesController(params)
+		if err != nil {
+			return err
+		}
+
+		return sc.Create(ctx, args[0], params)
+	},
+}
+
+var scheduleUpdateCmd = &cobra.Command{
+	Use:   "update <ID|NAME>",
+	Short: "update a schedule, only the provided options are changed",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		sc, err := createSchedulesController(params)
+		if err != nil {
+			return err
+		}
+
+		return sc.Update(ctx, args[0], params)
+	},
+}
+
+var scheduleDeleteCmd = &cobra.Command{
+	Use:   "delete <ID|NAME>",
+	Short: "delete a schedule",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		sc, err := createSchedulesController(params)
+		if err != nil {
+			return err
+		}
+
+		return sc.Delete(ctx, args[0])
+	},
+}
+
+var scheduleRunCopyCmd = &cobra.Command{
+	Use: "run-copy <ID|NAME>",
+	Short: "start an ad-hoc copy of the command or script of a schedule in background, " +
+		"it's a separate job which isn't recorded as a run of the schedule",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		sc, err := createSchedulesController(params)
+		if err != nil {
+			return err
+		}
+
+		return sc.RunCopy(ctx, args[0])
+	},
+}
+
+func createSchedulesController(params *options.ParameterBag) (*controllers.SchedulesController, error) {
+	timezone, err := time.LoadLocation(params.ReadString(controllers.ScheduleTimezone, "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", controllers.ScheduleTimezone, err)
+	}
+
+	rportAPI := buildRport(params)
+
+	return &controllers.SchedulesController{
+		ExecutionHelper: &controllers.ExecutionHelper{
+			JobStarter: rportAPI,
+			JobRenderer: &output.JobRenderer{
+				Writer: os.Stdout,
+				Format: getOutputFormat(),
+			},
+		},
+		Rport: rportAPI,
+		ScheduleRenderer: &output.ScheduleRenderer{
+			ColCountCalculator: utils.CalcTerminalColumnsCount,
+			Writer:             os.Stdout,
+			Format:             getOutputFormat(),
+		},
+		Timezone: timezone,
+	}, nil
+}
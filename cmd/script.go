@@ -26,6 +26,38 @@ import (
 func init() {
 	config.DefineCommandInputs(executeScript, getScriptRequirements())
 	scriptCmd.AddCommand(executeScript)
+
+	scriptCmd.AddCommand(scriptListCmd)
+	scriptCmd.AddCommand(scriptGetCmd)
+
+	scriptCreateCmd.Flags().StringP(controllers.Script, "s", "", "[required] Path to the script file")
+	scriptCreateCmd.Flags().StringP(
+		controllers.Interpreter,
+		"i",
+		"",
+		"interpreter/shell name for the script execution, detected by the file extension if not provided",
+	)
+	scriptCreateCmd.Flags().BoolP(controllers.IsSudo, "u", false, "execute script as sudo")
+	scriptCreateCmd.Flags().StringP(controllers.Cwd, "w", "", "current working directory")
+	scriptCreateCmd.Flags().StringArray(controllers.ScriptTag, []string{}, "Tag of the script, can be repeated or comma separated")
+	scriptCmd.AddCommand(scriptCreateCmd)
+
+	scriptUpdateCmd.Flags().String(controllers.ScriptNewName, "", "New name of the script")
+	scriptUpdateCmd.Flags().StringP(controllers.Script, "s", "", "Path to the file with the new content of the script")
+	scriptUpdateCmd.Flags().StringP(controllers.Interpreter, "i", "", "interpreter/shell name for the script execution")
+	scriptUpdateCmd.Flags().BoolP(controllers.IsSudo, "u", false, "execute script as sudo")
+	scriptUpdateCmd.Flags().StringP(controllers.Cwd, "w", "", "current working directory")
+	scriptUpdateCmd.Flags().StringArray(
+		controllers.ScriptTag,
+		[]string{},
+		"Tag of the script, can be repeated or comma separated, if provided, all existing tags are replaced",
+	)
+	scriptCmd.AddCommand(scriptUpdateCmd)
+
+	scriptCmd.AddCommand(scriptDeleteCmd)
+
+	config.DefineCommandInputs(scriptRunCmd, getScriptRunRequirements())
+	scriptCmd.AddCommand(scriptRunCmd)
 	rootCmd.AddCommand(scriptCmd)
 }
 
@@ -40,62 +72,214 @@ var executeScript = &cobra.Command{
 	Short: "executes a remote script on rport client(s)",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		sigs := make(chan os.Signal, 1)
-		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
-		promptReader := &utils.PromptReader{
-			Sc:              bufio.NewScanner(os.Stdin),
-			SigChan:         sigs,
-			PasswordScanner: utils.ReadPassword,
+		params, err := loadScriptExecutionParams(cmd, getScriptRequirements())
+		if err != nil {
+			return err
 		}
 
-		params, err := config.LoadParamsFromFileAndEnvAndFlagsAndPrompt(cmd, getScriptRequirements(), promptReader)
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		executionHelper, err := createScriptExecutionHelper(ctx, params)
 		if err != nil {
 			return err
 		}
 
-		baseRportURL := params.ReadString(config.ServerURL, config.DefaultServerURL)
-		tokenValidity := env.ReadEnvInt(config.SessionValiditySecondsEnvVar, api.DefaultTokenValiditySeconds)
-		wsURLBuilder := &api.WsScriptsURLProvider{
-			WsURLProvider: &api.WsURLProvider{
-				BaseURL: baseRportURL,
-				TokenProvider: func() (token string, err error) {
-					token = params.ReadString(config.Token, "")
-					return
-				},
-				TokenValiditySeconds: tokenValidity,
-			},
+		cmdExecutor := &controllers.ScriptsController{
+			ExecutionHelper: executionHelper,
 		}
 
+		return cmdExecutor.Start(ctx, params)
+	},
+}
+
+var scriptListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list scripts of the script library",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createScriptsLibraryController(params, nil).List(ctx)
+	},
+}
+
+var scriptGetCmd = &cobra.Command{
+	Use:   "get <ID|NAME>",
+	Short: "get details and content of a library script",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createScriptsLibraryController(params, nil).Get(ctx, args[0])
+	},
+}
+
+var scriptCreateCmd = &cobra.Command{
+	Use:   "create <NAME>",
+	Short: "add a script file to the script library",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createScriptsLibraryController(params, nil).Create(ctx, args[0], params)
+	},
+}
+
+var scriptUpdateCmd = &cobra.Command{
+	Use:   "update <ID|NAME>",
+	Short: "update a library script, only the provided options are changed",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
 		ctx, cancel := buildContext(context.Background())
 		defer cancel()
 
-		wsClient, err := utils.NewWsClient(ctx, wsURLBuilder.BuildWsURL)
+		return createScriptsLibraryController(params, nil).Update(ctx, args[0], params)
+	},
+}
+
+var scriptDeleteCmd = &cobra.Command{
+	Use:   "delete <ID|NAME>",
+	Short: "delete a script from the script library",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createScriptsLibraryController(params, nil).Delete(ctx, args[0])
+	},
+}
+
+var scriptRunCmd = &cobra.Command{
+	Use:   "run <ID|NAME>",
+	Short: "executes a library script on rport client(s) with its interpreter, sudo and cwd unless provided",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params, err := loadScriptExecutionParams(cmd, getScriptRunRequirements())
 		if err != nil {
 			return err
 		}
 
-		rportAPI := buildRport(params)
-		clientSearch := &client.Search{
-			DataProvider: rportAPI,
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		executionHelper, err := createScriptExecutionHelper(ctx, params)
+		if err != nil {
+			return err
 		}
 
-		isFullJobOutput := params.ReadBool(controllers.IsFullOutput, false)
-		cmdExecutor := &controllers.ScriptsController{
-			ExecutionHelper: &controllers.ExecutionHelper{
-				ReadWriter: wsClient,
-				JobRenderer: &output.JobRenderer{
-					Writer:       os.Stdout,
-					Format:       getOutputFormat(),
-					IsFullOutput: isFullJobOutput,
-				},
-				ClientSearch: clientSearch,
+		return createScriptsLibraryController(params, executionHelper).Run(ctx, args[0], params)
+	},
+}
+
+func loadScriptExecutionParams(cmd *cobra.Command, reqs []config.ParameterRequirement) (*options.ParameterBag, error) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	promptReader := &utils.PromptReader{
+		Sc:              bufio.NewScanner(os.Stdin),
+		SigChan:         sigs,
+		PasswordScanner: utils.ReadPassword,
+	}
+
+	return config.LoadParamsFromFileAndEnvAndFlagsAndPrompt(cmd, reqs, promptReader)
+}
+
+func createScriptExecutionHelper(ctx context.Context, params *options.ParameterBag) (*controllers.ExecutionHelper, error) {
+	baseRportURL := params.ReadString(config.ServerURL, config.DefaultServerURL)
+	tokenValidity := env.ReadEnvInt(config.SessionValiditySecondsEnvVar, api.DefaultTokenValiditySeconds)
+	bearerAuth := buildBearerAuth(params)
+	wsURLBuilder := &api.WsScriptsURLProvider{
+		WsURLProvider: &api.WsURLProvider{
+			BaseURL: baseRportURL,
+			TokenProvider: func() (string, error) {
+				return bearerAuth.Token(ctx)
 			},
+			TokenValiditySeconds: tokenValidity,
+		},
+	}
+
+	var readWriter controllers.ReadWriter
+	var err error
+	if !params.ReadBool(controllers.Detach, false) {
+		readWriter, err = utils.NewWsClient(ctx, wsURLBuilder.BuildWsURL, bearerAuth)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		err = cmdExecutor.Start(ctx, params)
+	rportAPI := buildRportWithBearerAuth(params, bearerAuth)
+	clientSearch := &client.Search{
+		DataProvider: rportAPI,
+	}
 
-		return err
-	},
+	jobSaver, err := buildJobSaver(params)
+	if err != nil {
+		return nil, err
+	}
+
+	spinner, jobWriter := buildJobSpinner(params)
+	jobRenderer, err := buildExecJobRenderer(params, jobWriter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &controllers.ExecutionHelper{
+		ReadWriter: readWriter,
+		ReadWriterProvider: func(ctx context.Context) (controllers.ReadWriter, error) {
+			return utils.NewWsClient(ctx, wsURLBuilder.BuildWsURL, bearerAuth)
+		},
+		JobRenderer:  jobRenderer,
+		ClientSearch: clientSearch,
+		JobStarter:   rportAPI,
+		Spinner:      spinner,
+		SummaryRenderer: &output.JobsSummaryRenderer{
+			ColCountCalculator: utils.CalcTerminalColumnsCount,
+			Writer:             os.Stderr,
+		},
+		JobSaver: jobSaver,
+	}, nil
+}
+
+func createScriptsLibraryController(
+	params *options.ParameterBag,
+	executionHelper *controllers.ExecutionHelper,
+) *controllers.ScriptsLibraryController {
+	return &controllers.ScriptsLibraryController{
+		ExecutionHelper: executionHelper,
+		Rport:           buildRport(params),
+		ScriptRenderer: &output.ScriptRenderer{
+			ColCountCalculator: utils.CalcTerminalColumnsCount,
+			Writer:             os.Stdout,
+			Format:             getOutputFormat(),
+		},
+	}
+}
+
+// getScriptRunRequirements are options of script execution except the script file which comes from the library
+func getScriptRunRequirements() []config.ParameterRequirement {
+	reqs := getScriptRequirements()
+	runReqs := make([]config.ParameterRequirement, 0, len(reqs))
+	for _, req := range reqs {
+		if req.Field == controllers.Script {
+			continue
+		}
+		runReqs = append(runReqs, req)
+	}
+
+	return runReqs
 }
 
 func getScriptRequirements() []config.ParameterRequirement {
@@ -105,10 +289,10 @@ func getScriptRequirements() []config.ParameterRequirement {
 			Help:     "Enter comma separated client IDs",
 			Validate: config.RequiredValidate,
 			Description: "[required] Comma separated client ids on which the script should be executed. " +
-				"Alternatively use -n to execute a script by client name(s)",
+				"Alternatively use -n or --filter to execute a script on clients selected by name(s) or filters",
 			ShortName: "d",
 			IsEnabled: func(providedParams *options.ParameterBag) bool {
-				return providedParams.ReadString(controllers.ClientNameFlag, "") == ""
+				return providedParams.ReadString(controllers.ClientNameFlag, "") == "" && !client.HasFilters(providedParams)
 			},
 			IsRequired: true,
 		},
@@ -117,6 +301,11 @@ func getScriptRequirements() []config.ParameterRequirement {
 			Description: "Comma separated client names on which the script should be executed",
 			ShortName:   "n",
 		},
+		{
+			Field:       client.FilterFlag,
+			Description: clientFilterHelp,
+			Type:        config.StringArrayRequirementType,
+		},
 		{
 			Field:       controllers.Script,
 			Help:        "Enter script path",
@@ -177,5 +366,53 @@ func getScriptRequirements() []config.ParameterRequirement {
 			Type:        config.StringRequirementType,
 			Default:     "",
 		},
+		{
+			Field:       controllers.ScriptArg,
+			Description: "positional argument of the script, can be repeated, supported for sh, bash, powershell and cmd",
+			Type:        config.StringArrayRequirementType,
+		},
+		{
+			Field:       controllers.ScriptEnv,
+			Description: "environment variable of the script in format KEY=VALUE, can be repeated",
+			Type:        config.StringArrayRequirementType,
+		},
+		{
+			Field: controllers.ScriptTemplate,
+			Description: "render the script as a go template for each client, fields are e.g. " +
+				".ID, .Name, .Os, .OsFamily, .Hostname and .Tags",
+			Type:    config.BoolRequirementType,
+			Default: false,
+		},
+		{
+			Field:       controllers.Detach,
+			Description: "start the script in background and print the job id without waiting for the results",
+			Type:        config.BoolRequirementType,
+			Default:     false,
+		},
+		{
+			Field:       controllers.OutputDir,
+			Description: "save stdout, stderr and metadata json of each client to files in the directory",
+			Type:        config.StringRequirementType,
+		},
+		{
+			Field: controllers.OutputFileTemplate,
+			Description: "go template of the file names in the output directory without extension, fields are " +
+				".Client (name or id), .ClientID, .ClientName, .Jid, .MultiJobID, .Status, .StartedAt and .FinishedAt",
+			Type:    config.StringRequirementType,
+			Default: output.DefaultJobFileNameTemplate,
+		},
+		{
+			Field: controllers.GroupByOutput,
+			Description: "render each distinct output once with the clients which produced it " +
+				"and a diff against the output of the majority of clients",
+			Type:    config.BoolRequirementType,
+			Default: false,
+		},
+		{
+			Field:       controllers.NoProgress,
+			Description: "don't show the progress of each client on stderr while the script runs",
+			Type:        config.BoolRequirementType,
+			Default:     false,
+		},
 	}
 }
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/controllers"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "show rport server status, the server url, the current user and the token expiry",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		statusController := &controllers.StatusController{
+			Rport: buildRport(params),
+			StatusRenderer: &output.StatusRenderer{
+				Writer: os.Stdout,
+				Format: getOutputFormat(),
+			},
+			CLIVersion: Version,
+		}
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return statusController.Status(ctx, params)
+	},
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/controllers"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	tokenCmd.AddCommand(tokenListCmd)
+
+	tokenCreateCmd.Flags().StringP(controllers.APITokenName, "n", "", "Name of the API token to recognize it later")
+	tokenCreateCmd.Flags().String(
+		controllers.APITokenScope,
+		models.APITokenScopeReadWrite,
+		"Scope of the API token: "+models.APITokenScopeRead+" or "+models.APITokenScopeReadWrite,
+	)
+	tokenCreateCmd.Flags().StringP(
+		controllers.APITokenExpiresAt,
+		"e",
+		"",
+		"Expiry of the API token as date e.g. 2030-01-02T15:04:05Z or as duration from now e.g. 720h, "+
+			"if not provided the token doesn't expire",
+	)
+	tokenCmd.AddCommand(tokenCreateCmd)
+
+	tokenCmd.AddCommand(tokenRevokeCmd)
+
+	rootCmd.AddCommand(tokenCmd)
+}
+
+var tokenCmd = &cobra.Command{
+	Use:   "token [command]",
+	Short: "manage API tokens of the current user",
+	Args:  cobra.ArbitraryArgs,
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list API tokens of the current user",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createAPITokenController(params).List(ctx)
+	},
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "create an API token, which can be used as password e.g. with 'rportcli init --api-token'",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createAPITokenController(params).Create(ctx, params)
+	},
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:   "revoke <PREFIX>",
+	Short: "revoke an API token by its prefix",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := config.LoadParamsFromFileAndEnv(cmd.Flags())
+
+		ctx, cancel := buildContext(context.Background())
+		defer cancel()
+
+		return createAPITokenController(params).Revoke(ctx, args[0])
+	},
+}
+
+func createAPITokenController(params *options.ParameterBag) *controllers.APITokenController {
+	return &controllers.APITokenController{
+		Rport: buildRport(params),
+		APITokenRenderer: &output.APITokenRenderer{
+			ColCountCalculator: utils.CalcTerminalColumnsCount,
+			Writer:             os.Stdout,
+			Format:             getOutputFormat(),
+		},
+	}
+}
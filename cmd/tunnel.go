@@ -56,6 +56,7 @@ var tunnelListCmd = &cobra.Command{
 			ColCountCalculator: utils.CalcTerminalColumnsCount,
 			Writer:             os.Stdout,
 			Format:             getOutputFormat(),
+			Columns:            getColumns(),
 		}
 
 		clientSearch := &client.Search{
@@ -158,12 +159,12 @@ func getCreateTunnelRequirements() []config.ParameterRequirement {
 	return []config.ParameterRequirement{
 		{
 			Field:       controllers.ClientID,
-			Description: "[conditionally required] client id, if not provided, client name should be given",
+			Description: "[conditionally required] client id, if not provided, client name or filters should be given",
 			Validate:    config.RequiredValidate,
 			ShortName:   "c",
 			IsRequired:  true,
 			IsEnabled: func(providedParams *options.ParameterBag) bool {
-				return providedParams.ReadString(controllers.ClientNameFlag, "") == ""
+				return providedParams.ReadString(controllers.ClientNameFlag, "") == "" && !client.HasFilters(providedParams)
 			},
 			Help: "Enter a client ID",
 		},
@@ -172,6 +173,11 @@ func getCreateTunnelRequirements() []config.ParameterRequirement {
 			Description: `client name, if no client id is provided`,
 			ShortName:   "n",
 		},
+		{
+			Field:       client.FilterFlag,
+			Description: clientFilterHelp + ", the filters should match exactly one client",
+			Type:        config.StringArrayRequirementType,
+		},
 		{
 			Field:       controllers.Local,
 			Description: createTunnelLocalDescr,
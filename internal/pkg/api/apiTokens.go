@@ -0,0 +1,71 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/breathbath/go_utils/v2/pkg/url"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+const (
+	APITokensURL = "/api/v1/me/tokens"
+	APITokenURL  = "/api/v1/me/tokens/{prefix}"
+)
+
+type APITokensResponse struct {
+	Data []*models.APIToken
+}
+
+type APITokenResponse struct {
+	Data *models.APIToken
+}
+
+func (rp *Rport) APITokens(ctx context.Context) (tokensResp *APITokensResponse, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, APITokensURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tokensResp = &APITokensResponse{}
+	_, err = rp.CallBaseClient(req, tokensResp)
+
+	return tokensResp, err
+}
+
+// CreateAPIToken creates a token of the current user, the token value is only known in the response
+func (rp *Rport) CreateAPIToken(ctx context.Context, token *models.APIToken) (tokenResp *APITokenResponse, err error) {
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, url.JoinURL(rp.BaseURL, APITokensURL), buf)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenResp = &APITokenResponse{}
+	_, err = rp.CallBaseClient(req, tokenResp)
+
+	return tokenResp, err
+}
+
+func (rp *Rport) RevokeAPIToken(ctx context.Context, prefix string) error {
+	tokenURL := strings.Replace(APITokenURL, "{prefix}", prefix, 1)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url.JoinURL(rp.BaseURL, tokenURL), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = rp.CallBaseClient(req, nil)
+
+	return err
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestAPITokens(t *testing.T) {
+	createdAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, APITokensURL, r.URL.String())
+		e := json.NewEncoder(rw).Encode(APITokensResponse{Data: []*models.APIToken{
+			{Prefix: "ab12cd34", Scope: models.APITokenScopeRead, CreatedAt: &createdAt},
+		}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	tokensResp, err := buildJobsTestAPI(srv.URL).APITokens(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*models.APIToken{{Prefix: "ab12cd34", Scope: models.APITokenScopeRead, CreatedAt: &createdAt}}, tokensResp.Data)
+}
+
+func TestCreateAPIToken(t *testing.T) {
+	var rawBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, APITokensURL, r.URL.String())
+
+		var e error
+		rawBody, e = ioutil.ReadAll(r.Body)
+		assert.NoError(t, e)
+
+		e = json.NewEncoder(rw).Encode(APITokenResponse{Data: &models.APIToken{
+			Prefix: "ab12cd34",
+			Name:   "ci",
+			Scope:  models.APITokenScopeReadWrite,
+			Token:  "ab12cd34secret",
+		}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	tokenResp, err := buildJobsTestAPI(srv.URL).CreateAPIToken(context.Background(), &models.APIToken{
+		Name:      "ci",
+		Scope:     models.APITokenScopeReadWrite,
+		ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"name":"ci","scope":"read+write","expires_at":"2030-01-02T03:04:05Z"}`, string(rawBody))
+	assert.Equal(t, "ab12cd34secret", tokenResp.Data.Token)
+}
+
+func TestRevokeAPIToken(t *testing.T) {
+	isCalled := false
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		isCalled = true
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/v1/me/tokens/ab12cd34", r.URL.String())
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	err := buildJobsTestAPI(srv.URL).RevokeAPIToken(context.Background(), "ab12cd34")
+	require.NoError(t, err)
+	assert.True(t, isCalled)
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"net/http"
 	url2 "net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
 
@@ -14,18 +18,192 @@ const (
 	ClientsURL = "/api/v1/clients"
 )
 
+var clientFields = []string{
+	"id",
+	"name",
+	"timezone",
+	"tunnels",
+	"address",
+	"hostname",
+	"os_kernel",
+	"connection_state",
+	"disconnected_at",
+	"os_version",
+	"os_family",
+	"os",
+	"os_arch",
+	"ipv4",
+	"tags",
+	"os_full_name",
+	"version",
+	"cpu_model",
+	"cpu_model_name",
+	"cpu_vendor",
+}
+
+// serverClientFilters are client fields which can be filtered by rport server, other filters are applied locally
+var serverClientFilters = map[string]bool{
+	"id":               true,
+	"name":             true,
+	"os":               true,
+	"os_arch":          true,
+	"os_family":        true,
+	"os_kernel":        true,
+	"hostname":         true,
+	"ipv4":             true,
+	"ipv6":             true,
+	"tags":             true,
+	"version":          true,
+	"address":          true,
+	"client_auth_id":   true,
+	"connection_state": true,
+}
+
+const DefaultClientsPageSize = 500
+
+type ListMeta struct {
+	Count int `json:"count"`
+}
+
 type ClientsResponse struct {
 	Data []*models.Client
+	Meta *ListMeta
+}
+
+// ClientsListOptions defines which clients should be listed and in which order, sort contains field names,
+// a name with '-' prefix means descending order, zero limit means all clients starting from the offset,
+// fields are the client fields to fetch, the default fields are fetched if none are given
+type ClientsListOptions struct {
+	Filters []*models.ClientFilter
+	Sort    []string
+	Limit   int
+	Offset  int
+	Fields  []string
+}
+
+// Clients gives all clients matching the filters, the clients are fetched page by page
+func (rp *Rport) Clients(ctx context.Context, filters ...*models.ClientFilter) (cr *ClientsResponse, err error) {
+	return rp.ListClients(ctx, &ClientsListOptions{Filters: filters})
+}
+
+// ListClients gives a single page of clients, the page is calculated locally if the server cannot do it,
+// e.g. if some filters are applied locally
+func (rp *Rport) ListClients(ctx context.Context, opts *ClientsListOptions) (cr *ClientsResponse, err error) {
+	_, localFilters := splitClientFilters(opts.Filters)
+	if opts.Limit > 0 && len(localFilters) == 0 {
+		cr, err = rp.fetchClientsPage(ctx, opts, opts.Limit, opts.Offset)
+		if err != nil {
+			return nil, err
+		}
+
+		// the count is sent only by servers supporting pagination, others give all clients ignoring the offset
+		if cr.Meta != nil && len(cr.Data) <= opts.Limit {
+			return cr, nil
+		}
+
+		logrus.Debugf("server ignored pagination params, will paginate %d clients locally", len(cr.Data))
+		return paginateClients(cr.Data, opts.Limit, opts.Offset), nil
+	}
+
+	allClients := make([]*models.Client, 0)
+	it := rp.NewClientsIterator(
+		&ClientsListOptions{Filters: opts.Filters, Sort: opts.Sort, Fields: opts.Fields},
+		DefaultClientsPageSize,
+	)
+	for it.HasNext() {
+		var clients []*models.Client
+		clients, err = it.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		allClients = append(allClients, clients...)
+	}
+
+	return paginateClients(allClients, opts.Limit, opts.Offset), nil
+}
+
+// ClientsIterator fetches clients page by page, filters which cannot be applied by the server are applied to each page
+type ClientsIterator struct {
+	rp           *Rport
+	opts         *ClientsListOptions
+	localFilters []*models.ClientFilter
+	pageSize     int
+	offset       int
+	isDone       bool
+}
+
+func (rp *Rport) NewClientsIterator(opts *ClientsListOptions, pageSize int) *ClientsIterator {
+	_, localFilters := splitClientFilters(opts.Filters)
+
+	return &ClientsIterator{
+		rp:           rp,
+		opts:         opts,
+		localFilters: localFilters,
+		pageSize:     pageSize,
+		offset:       opts.Offset,
+	}
+}
+
+func (ci *ClientsIterator) HasNext() bool {
+	return !ci.isDone
+}
+
+// Next gives clients of the next page
+func (ci *ClientsIterator) Next(ctx context.Context) ([]*models.Client, error) {
+	if ci.isDone {
+		return []*models.Client{}, nil
+	}
+
+	cr, err := ci.rp.fetchClientsPage(ctx, ci.opts, ci.pageSize, ci.offset)
+	if err != nil {
+		return nil, err
+	}
+
+	receivedCount := len(cr.Data)
+	ci.offset += receivedCount
+
+	// a page without count or with another size than requested means that the server doesn't support pagination
+	// and has given all clients
+	if cr.Meta == nil || receivedCount != ci.pageSize || ci.offset >= cr.Meta.Count {
+		ci.isDone = true
+	}
+
+	return models.FilterClients(cr.Data, ci.localFilters), nil
 }
 
-func (rp *Rport) Clients(ctx context.Context) (cr *ClientsResponse, err error) {
+func (rp *Rport) fetchClientsPage(ctx context.Context, opts *ClientsListOptions, limit, offset int) (cr *ClientsResponse, err error) {
 	var req *http.Request
 	u, err := url2.Parse(url.JoinURL(rp.BaseURL, ClientsURL))
 	if err != nil {
 		return nil, err
 	}
+
+	fields := append([]string{}, opts.Fields...)
+	if len(fields) == 0 {
+		fields = append(fields, clientFields...)
+	}
+
 	q := u.Query()
-	q.Set("fields[clients]", "id,name,timezone,tunnels,address,hostname,os_kernel,connection_state,disconnected_at,os_version,os_family,os,os_arch,ipv4,tags,os_full_name,version,cpu_model,cpu_model_name,cpu_vendor")
+	for _, filter := range opts.Filters {
+		if serverClientFilters[filter.Field] {
+			q.Set("filter["+filter.Field+"]", strings.Join(filter.Values, ","))
+			continue
+		}
+
+		if !containsString(fields, filter.Field) {
+			fields = append(fields, filter.Field)
+		}
+	}
+	q.Set("fields[clients]", strings.Join(fields, ","))
+
+	for _, sortField := range opts.Sort {
+		q.Add("sort", sortField)
+	}
+
+	if limit > 0 {
+		q.Set("page[limit]", strconv.Itoa(limit))
+		q.Set("page[offset]", strconv.Itoa(offset))
+	}
 	u.RawQuery = q.Encode()
 
 	req, err = http.NewRequestWithContext(
@@ -44,9 +222,40 @@ func (rp *Rport) Clients(ctx context.Context) (cr *ClientsResponse, err error) {
 	return
 }
 
-func (rp *Rport) GetClients(ctx context.Context) (cls []*models.Client, err error) {
+func splitClientFilters(filters []*models.ClientFilter) (serverFilters, localFilters []*models.ClientFilter) {
+	serverFilters = make([]*models.ClientFilter, 0, len(filters))
+	localFilters = make([]*models.ClientFilter, 0, len(filters))
+	for _, filter := range filters {
+		if serverClientFilters[filter.Field] {
+			serverFilters = append(serverFilters, filter)
+		} else {
+			localFilters = append(localFilters, filter)
+		}
+	}
+
+	return serverFilters, localFilters
+}
+
+func paginateClients(clients []*models.Client, limit, offset int) *ClientsResponse {
+	count := len(clients)
+	if offset > count {
+		offset = count
+	}
+
+	end := count
+	if limit > 0 && offset+limit < count {
+		end = offset + limit
+	}
+
+	return &ClientsResponse{
+		Data: clients[offset:end],
+		Meta: &ListMeta{Count: count},
+	}
+}
+
+func (rp *Rport) GetClients(ctx context.Context, filters ...*models.ClientFilter) (cls []*models.Client, err error) {
 	var cr *ClientsResponse
-	cr, err = rp.Clients(ctx)
+	cr, err = rp.Clients(ctx, filters...)
 
 	if err != nil {
 		return
@@ -54,3 +263,13 @@ func (rp *Rport) GetClients(ctx context.Context) (cls []*models.Client, err erro
 
 	return cr.Data, nil
 }
+
+func containsString(items []string, item string) bool {
+	for _, it := range items {
+		if it == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var clientsStub = []*models.Client{
@@ -83,7 +85,13 @@ func TestClientsList(t *testing.T) {
 		authHeader := r.Header.Get("Authorization")
 		assert.Equal(t, "Basic bG9nMTE2Njo1NjQzMjI=", authHeader)
 
-		assert.Equal(t, ClientsURL+"?fields%5Bclients%5D=id%2Cname%2Ctimezone%2Ctunnels%2Caddress%2Chostname%2Cos_kernel%2Cconnection_state", r.URL.String())
+		assert.Equal(
+			t,
+			ClientsURL+"?fields%5Bclients%5D=id%2Cname%2Ctimezone%2Ctunnels%2Caddress%2Chostname%2Cos_kernel%2Cconnection_state"+
+				"%2Cdisconnected_at%2Cos_version%2Cos_family%2Cos%2Cos_arch%2Cipv4%2Ctags%2Cos_full_name%2Cversion"+
+				"%2Ccpu_model%2Ccpu_model_name%2Ccpu_vendor&page%5Blimit%5D=500&page%5Boffset%5D=0",
+			r.URL.String(),
+		)
 		jsonEnc := json.NewEncoder(rw)
 		e := jsonEnc.Encode(ClientsResponse{Data: clientsStub})
 		assert.NoError(t, e)
@@ -131,3 +139,179 @@ func TestGetClientsList(t *testing.T) {
 
 	assert.Equal(t, expectedClients, actualClients)
 }
+
+func TestClientsListWithFilters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "linux", r.URL.Query().Get("filter[os_family]"))
+		assert.Equal(t, "prod,web*", r.URL.Query().Get("filter[tags]"))
+		assert.Equal(t, "", r.URL.Query().Get("filter[timezone]"))
+		assert.Contains(t, r.URL.Query().Get("fields[clients]"), ",timezone,")
+
+		e := json.NewEncoder(rw).Encode(ClientsResponse{Data: []*models.Client{
+			{ID: "1", Timezone: "CET (UTC+01:00)"},
+			{ID: "2", Timezone: "UTC (UTC+00:00)"},
+			{ID: "3", Timezone: "cet (UTC+01:00)"},
+		}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	cl := New(srv.URL, nil)
+	clientsResp, err := cl.Clients(
+		context.Background(),
+		&models.ClientFilter{Field: "os_family", Values: []string{"linux"}},
+		&models.ClientFilter{Field: "tags", Values: []string{"prod", "web*"}},
+		&models.ClientFilter{Field: "timezone", Values: []string{"CET*"}},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*models.Client{
+		{ID: "1", Timezone: "CET (UTC+01:00)"},
+		{ID: "3", Timezone: "cet (UTC+01:00)"},
+	}, clientsResp.Data)
+}
+
+func buildPagedClientsServer(t *testing.T, allClients []*models.Client, requestedURLs *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		*requestedURLs = append(*requestedURLs, r.URL.Query().Encode())
+
+		limit, err := strconv.Atoi(r.URL.Query().Get("page[limit]"))
+		assert.NoError(t, err)
+		offset, err := strconv.Atoi(r.URL.Query().Get("page[offset]"))
+		assert.NoError(t, err)
+
+		end := offset + limit
+		if end > len(allClients) {
+			end = len(allClients)
+		}
+
+		e := json.NewEncoder(rw).Encode(ClientsResponse{
+			Data: allClients[offset:end],
+			Meta: &ListMeta{Count: len(allClients)},
+		})
+		assert.NoError(t, e)
+	}))
+}
+
+func TestClientsIterator(t *testing.T) {
+	allClients := []*models.Client{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}}
+	requestedURLs := []string{}
+	srv := buildPagedClientsServer(t, allClients, &requestedURLs)
+	defer srv.Close()
+
+	it := New(srv.URL, nil).NewClientsIterator(&ClientsListOptions{Sort: []string{"-name"}}, 2)
+
+	pages := [][]*models.Client{}
+	for it.HasNext() {
+		clients, err := it.Next(context.Background())
+		require.NoError(t, err)
+		pages = append(pages, clients)
+	}
+
+	assert.Equal(t, [][]*models.Client{
+		{{ID: "1"}, {ID: "2"}},
+		{{ID: "3"}, {ID: "4"}},
+		{{ID: "5"}},
+	}, pages)
+	require.Len(t, requestedURLs, 3)
+	assert.Contains(t, requestedURLs[2], "page%5Blimit%5D=2&page%5Boffset%5D=4&sort=-name")
+}
+
+func TestListClientsPage(t *testing.T) {
+	allClients := []*models.Client{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}}
+	requestedURLs := []string{}
+	srv := buildPagedClientsServer(t, allClients, &requestedURLs)
+	defer srv.Close()
+
+	clientsResp, err := New(srv.URL, nil).ListClients(context.Background(), &ClientsListOptions{Limit: 2, Offset: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*models.Client{{ID: "3"}, {ID: "4"}}, clientsResp.Data)
+	assert.Equal(t, &ListMeta{Count: 5}, clientsResp.Meta)
+	assert.Len(t, requestedURLs, 1)
+}
+
+func TestListClientsPageWithLocalFilters(t *testing.T) {
+	allClients := []*models.Client{
+		{ID: "1", Timezone: "UTC"},
+		{ID: "2", Timezone: "CET"},
+		{ID: "3", Timezone: "CET"},
+		{ID: "4", Timezone: "UTC"},
+		{ID: "5", Timezone: "CET"},
+	}
+	requestedURLs := []string{}
+	srv := buildPagedClientsServer(t, allClients, &requestedURLs)
+	defer srv.Close()
+
+	clientsResp, err := New(srv.URL, nil).ListClients(context.Background(), &ClientsListOptions{
+		Filters: []*models.ClientFilter{{Field: "timezone", Values: []string{"CET"}}},
+		Limit:   1,
+		Offset:  1,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*models.Client{{ID: "3", Timezone: "CET"}}, clientsResp.Data)
+	assert.Equal(t, &ListMeta{Count: 3}, clientsResp.Meta)
+}
+
+func TestListClientsPageWithoutServerPagination(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		e := json.NewEncoder(rw).Encode(ClientsResponse{Data: clientsStub})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	clientsResp, err := New(srv.URL, nil).ListClients(context.Background(), &ClientsListOptions{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*models.Client{clientsStub[1]}, clientsResp.Data)
+	assert.Equal(t, &ListMeta{Count: 2}, clientsResp.Meta)
+}
+
+func TestListClientsWithFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "id,name,tags,timezone", r.URL.Query().Get("fields[clients]"))
+		e := json.NewEncoder(rw).Encode(ClientsResponse{Data: clientsStub})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, nil).ListClients(context.Background(), &ClientsListOptions{
+		Fields:  []string{"id", "name", "tags"},
+		Filters: []*models.ClientFilter{{Field: "timezone", Values: []string{"UTC"}}},
+	})
+	require.NoError(t, err)
+}
+
+func TestListClientsPageWithoutServerPaginationBeyondLimit(t *testing.T) {
+	allClients := []*models.Client{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}}
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		e := json.NewEncoder(rw).Encode(ClientsResponse{Data: allClients})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	clientsResp, err := New(srv.URL, nil).ListClients(context.Background(), &ClientsListOptions{Limit: 10, Offset: 5})
+	require.NoError(t, err)
+
+	assert.Empty(t, clientsResp.Data)
+	assert.Equal(t, &ListMeta{Count: 5}, clientsResp.Meta)
+}
+
+func TestClientsIteratorWithoutServerPagination(t *testing.T) {
+	requestsCount := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requestsCount++
+		e := json.NewEncoder(rw).Encode(ClientsResponse{Data: clientsStub})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	it := New(srv.URL, nil).NewClientsIterator(&ClientsListOptions{}, len(clientsStub))
+	clients, err := it.Next(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, clientsStub, clients)
+	assert.False(t, it.HasNext(), "all clients are given by a server without pagination")
+	assert.Equal(t, 1, requestsCount)
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/breathbath/go_utils/v2/pkg/url"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+const (
+	CommandTemplatesURL = "/api/v1/library/commands"
+	CommandTemplateURL  = "/api/v1/library/commands/{command_id}"
+)
+
+// ErrCommandLibraryNotSupported is returned if the server has no command library, e.g. older rport versions
+var ErrCommandLibraryNotSupported = errors.New("the rport server doesn't support the command library")
+
+type CommandTemplatesResponse struct {
+	Data []*models.CommandTemplate
+}
+
+type CommandTemplateResponse struct {
+	Data *models.CommandTemplate
+}
+
+func (rp *Rport) CommandTemplates(ctx context.Context) ([]*models.CommandTemplate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, CommandTemplatesURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	templatesResp := &CommandTemplatesResponse{}
+	resp, err := rp.CallBaseClient(req, templatesResp)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, ErrCommandLibraryNotSupported
+		}
+		return nil, err
+	}
+
+	return templatesResp.Data, nil
+}
+
+func (rp *Rport) CommandTemplate(ctx context.Context, templateID string) (*models.CommandTemplate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, buildCommandTemplateURL(templateID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	templateResp := &CommandTemplateResponse{}
+	_, err = rp.CallBaseClient(req, templateResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return templateResp.Data, nil
+}
+
+// CreateCommandTemplate stores a command in the library, the returned template has the id given by the server
+func (rp *Rport) CreateCommandTemplate(ctx context.Context, template *models.CommandTemplate) (*models.CommandTemplate, error) {
+	templateResp := &CommandTemplateResponse{}
+	err := rp.sendCommandTemplate(ctx, http.MethodPost, url.JoinURL(rp.BaseURL, CommandTemplatesURL), template, templateResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return templateResp.Data, nil
+}
+
+func (rp *Rport) UpdateCommandTemplate(ctx context.Context, template *models.CommandTemplate) error {
+	templateURL := url.JoinURL(rp.BaseURL, buildCommandTemplateURL(template.ID))
+
+	return rp.sendCommandTemplate(ctx, http.MethodPut, templateURL, template, nil)
+}
+
+func (rp *Rport) DeleteCommandTemplate(ctx context.Context, templateID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url.JoinURL(rp.BaseURL, buildCommandTemplateURL(templateID)), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = rp.CallBaseClient(req, nil)
+
+	return err
+}
+
+func (rp *Rport) sendCommandTemplate(
+	ctx context.Context,
+	method, templateURL string,
+	template *models.CommandTemplate,
+	target interface{},
+) error {
+	templateToSend := &models.CommandTemplate{
+		Name: template.Name,
+		Cmd:  template.Cmd,
+		Tags: template.Tags,
+	}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(templateToSend)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, templateURL, buf)
+	if err != nil {
+		return err
+	}
+
+	_, err = rp.CallBaseClient(req, target)
+
+	return err
+}
+
+func buildCommandTemplateURL(templateID string) string {
+	return strings.Replace(CommandTemplateURL, "{command_id}", templateID, 1)
+}
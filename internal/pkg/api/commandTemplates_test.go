@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestCommandTemplates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, CommandTemplatesURL, r.URL.String())
+		e := json.NewEncoder(rw).Encode(CommandTemplatesResponse{Data: []*models.CommandTemplate{
+			{ID: "123", Name: "restart", Cmd: "systemctl restart {{service}}"},
+		}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	templates, err := buildJobsTestAPI(srv.URL).CommandTemplates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*models.CommandTemplate{{ID: "123", Name: "restart", Cmd: "systemctl restart {{service}}"}}, templates)
+}
+
+func TestCommandTemplatesNotSupported(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		_, e := rw.Write([]byte(`{"errors":[{"code":"","title":"Not Found","detail":""}]}`))
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	_, err := buildJobsTestAPI(srv.URL).CommandTemplates(context.Background())
+	assert.ErrorIs(t, err, ErrCommandLibraryNotSupported)
+}
+
+func TestCommandTemplatesServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+		_, e := rw.Write([]byte(`{"errors":[{"code":"","title":"database is locked","detail":""}]}`))
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	_, err := buildJobsTestAPI(srv.URL).CommandTemplates(context.Background())
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCommandLibraryNotSupported)
+}
+
+func TestCommandTemplate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/library/commands/123", r.URL.String())
+		e := json.NewEncoder(rw).Encode(CommandTemplateResponse{Data: &models.CommandTemplate{ID: "123", Name: "restart"}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	template, err := buildJobsTestAPI(srv.URL).CommandTemplate(context.Background(), "123")
+	require.NoError(t, err)
+	assert.Equal(t, &models.CommandTemplate{ID: "123", Name: "restart"}, template)
+}
+
+func TestCreateCommandTemplate(t *testing.T) {
+	var rawBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, CommandTemplatesURL, r.URL.String())
+
+		var e error
+		rawBody, e = ioutil.ReadAll(r.Body)
+		assert.NoError(t, e)
+
+		e = json.NewEncoder(rw).Encode(CommandTemplateResponse{Data: &models.CommandTemplate{ID: "123", Name: "restart"}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	createdAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	template, err := buildJobsTestAPI(srv.URL).CreateCommandTemplate(context.Background(), &models.CommandTemplate{
+		ID:        "old",
+		Name:      "restart",
+		Cmd:       "systemctl restart {{service}}",
+		Tags:      []string{"linux"},
+		CreatedAt: &createdAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "123", template.ID)
+	assert.JSONEq(t, `{"name":"restart","cmd":"systemctl restart {{service}}","tags":["linux"]}`, string(rawBody))
+}
+
+func TestUpdateAndDeleteCommandTemplate(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.String())
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	rp := buildJobsTestAPI(srv.URL)
+	require.NoError(t, rp.UpdateCommandTemplate(context.Background(), &models.CommandTemplate{ID: "123", Name: "restart"}))
+	require.NoError(t, rp.DeleteCommandTemplate(context.Background(), "123"))
+
+	assert.Equal(t, []string{
+		"PUT /api/v1/library/commands/123",
+		"DELETE /api/v1/library/commands/123",
+	}, requests)
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	url2 "net/url"
+	"strings"
+
+	"github.com/breathbath/go_utils/v2/pkg/url"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+const (
+	ClientCommandsURL      = "/api/v1/clients/{client_id}/commands"
+	ClientCommandURL       = "/api/v1/clients/{client_id}/commands/{job_id}"
+	MultiClientCommandsURL = "/api/v1/commands"
+	MultiClientCommandURL  = "/api/v1/commands/{job_id}"
+)
+
+type JobsResponse struct {
+	Data []*models.Job
+}
+
+type JobResponse struct {
+	Data *models.Job
+}
+
+type MultiJobsResponse struct {
+	Data []*models.MultiJob
+}
+
+type MultiJobResponse struct {
+	Data *models.MultiJob
+}
+
+// ClientJobs lists jobs executed on a client, status filter is applied if not empty
+func (rp *Rport) ClientJobs(ctx context.Context, clientID, status string) (jobsResp *JobsResponse, err error) {
+	u, err := url2.Parse(url.JoinURL(rp.BaseURL, strings.Replace(ClientCommandsURL, "{client_id}", clientID, 1)))
+	if err != nil {
+		return nil, err
+	}
+
+	if status != "" {
+		q := u.Query()
+		q.Set("filter[status]", status)
+		u.RawQuery = q.Encode()
+	}
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	jobsResp = &JobsResponse{}
+	_, err = rp.CallBaseClient(req, jobsResp)
+
+	return jobsResp, err
+}
+
+func (rp *Rport) ClientJob(ctx context.Context, clientID, jid string) (jobResp *JobResponse, err error) {
+	u := strings.Replace(ClientCommandURL, "{client_id}", clientID, 1)
+	u = strings.Replace(u, "{job_id}", jid, 1)
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, u), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	jobResp = &JobResponse{}
+	_, err = rp.CallBaseClient(req, jobResp)
+
+	return jobResp, err
+}
+
+func (rp *Rport) MultiClientJobs(ctx context.Context) (jobsResp *MultiJobsResponse, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, MultiClientCommandsURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	jobsResp = &MultiJobsResponse{}
+	_, err = rp.CallBaseClient(req, jobsResp)
+
+	return jobsResp, err
+}
+
+func (rp *Rport) MultiClientJob(ctx context.Context, jid string) (jobResp *MultiJobResponse, err error) {
+	u := strings.Replace(MultiClientCommandURL, "{job_id}", jid, 1)
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, u), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	jobResp = &MultiJobResponse{}
+	_, err = rp.CallBaseClient(req, jobResp)
+
+	return jobResp, err
+}
+
+const (
+	MultiClientScriptsURL = "/api/v1/scripts"
+)
+
+type JobStartedResponse struct {
+	Data *models.JobStarted
+}
+
+// StartMultiClientCommand starts a command on clients without waiting for its results
+func (rp *Rport) StartMultiClientCommand(ctx context.Context, wsCmd *models.WsScriptCommand) (*JobStartedResponse, error) {
+	return rp.startMultiClientJob(ctx, MultiClientCommandsURL, wsCmd)
+}
+
+// StartMultiClientScript starts a script on clients without waiting for its results
+func (rp *Rport) StartMultiClientScript(ctx context.Context, wsCmd *models.WsScriptCommand) (*JobStartedResponse, error) {
+	return rp.startMultiClientJob(ctx, MultiClientScriptsURL, wsCmd)
+}
+
+func (rp *Rport) startMultiClientJob(ctx context.Context, uriPath string, wsCmd *models.WsScriptCommand) (jobResp *JobStartedResponse, err error) {
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(wsCmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, url.JoinURL(rp.BaseURL, uriPath), buf)
+	if err != nil {
+		return nil, err
+	}
+
+	jobResp = &JobStartedResponse{}
+	_, err = rp.CallBaseClient(req, jobResp)
+
+	return jobResp, err
+}
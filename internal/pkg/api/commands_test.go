@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+)
+
+func buildJobsTestAPI(srvURL string) *Rport {
+	return New(srvURL, &utils.StorageBasicAuth{
+		AuthProvider: func() (login, pass string, err error) {
+			login = "log1"
+			pass = "pass1"
+			return
+		},
+	})
+}
+
+func TestClientJobs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v1/clients/cl1/commands?filter%5Bstatus%5D=failed", r.URL.String())
+		e := json.NewEncoder(rw).Encode(JobsResponse{Data: []*models.Job{{Jid: "j1", Status: "failed"}}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	jobsResp, err := buildJobsTestAPI(srv.URL).ClientJobs(context.Background(), "cl1", "failed")
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Job{{Jid: "j1", Status: "failed"}}, jobsResp.Data)
+}
+
+func TestClientJob(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/clients/cl1/commands/j1", r.URL.String())
+		e := json.NewEncoder(rw).Encode(JobResponse{Data: &models.Job{Jid: "j1", ClientID: "cl1"}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	jobResp, err := buildJobsTestAPI(srv.URL).ClientJob(context.Background(), "cl1", "j1")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Job{Jid: "j1", ClientID: "cl1"}, jobResp.Data)
+}
+
+func TestMultiClientJobs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, MultiClientCommandsURL, r.URL.String())
+		e := json.NewEncoder(rw).Encode(MultiJobsResponse{Data: []*models.MultiJob{{Jid: "mj1", ClientIDs: []string{"cl1", "cl2"}}}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	jobsResp, err := buildJobsTestAPI(srv.URL).MultiClientJobs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*models.MultiJob{{Jid: "mj1", ClientIDs: []string{"cl1", "cl2"}}}, jobsResp.Data)
+}
+
+func TestMultiClientJob(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/commands/mj1", r.URL.String())
+		e := json.NewEncoder(rw).Encode(MultiJobResponse{Data: &models.MultiJob{
+			Jid:  "mj1",
+			Jobs: []*models.Job{{Jid: "j1", MultiJobID: "mj1"}},
+		}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	jobResp, err := buildJobsTestAPI(srv.URL).MultiClientJob(context.Background(), "mj1")
+	require.NoError(t, err)
+	assert.Equal(t, "mj1", jobResp.Data.Jid)
+	assert.Len(t, jobResp.Data.Jobs, 1)
+}
+
+func TestStartMultiClientCommand(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, MultiClientCommandsURL, r.URL.String())
+
+		var wsCmd models.WsScriptCommand
+		e := json.NewDecoder(r.Body).Decode(&wsCmd)
+		assert.NoError(t, e)
+		assert.Equal(t, "ls", wsCmd.Command)
+
+		e = json.NewEncoder(rw).Encode(JobStartedResponse{Data: &models.JobStarted{Jid: "mj1"}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	jobResp, err := buildJobsTestAPI(srv.URL).StartMultiClientCommand(context.Background(), &models.WsScriptCommand{
+		ClientIDs: []string{"cl1"},
+		Command:   "ls",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "mj1", jobResp.Data.Jid)
+}
@@ -48,6 +48,25 @@ func (rp *Rport) GetToken(ctx context.Context, tokenLifetime int) (li LoginRespo
 	return
 }
 
+// RefreshToken logs in with the credentials of the client auth to get a new token,
+// logins requiring 2 factor auth cannot be done without user interaction
+func (rp *Rport) RefreshToken(ctx context.Context, tokenLifetime int) (string, error) {
+	loginResp, err := rp.GetToken(ctx, tokenLifetime)
+	if err != nil {
+		return "", err
+	}
+
+	if loginResp.Data.TwoFA.DeliveryMethod != "" || loginResp.Data.TwoFA.SentTo != "" {
+		return "", fmt.Errorf("2 factor auth is required to login, please run 'rportcli init' to login again")
+	}
+
+	if loginResp.Data.Token == "" {
+		return "", fmt.Errorf("no auth token received from rport")
+	}
+
+	return loginResp.Data.Token, nil
+}
+
 type TwoFaLogin struct {
 	Username string `json:"username"`
 	Token    string `json:"token"`
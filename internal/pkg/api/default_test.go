@@ -248,3 +248,57 @@ func TestLogoutServerError(t *testing.T) {
 		})
 	}
 }
+
+func TestRefreshToken(t *testing.T) {
+	testCases := []struct {
+		name          string
+		tokenToGive   models.Token
+		expectedToken string
+		expectedError string
+	}{
+		{
+			name:          "success",
+			tokenToGive:   models.Token{Token: "token123"},
+			expectedToken: "token123",
+		},
+		{
+			name: "2fa required",
+			tokenToGive: models.Token{
+				Token: "token123",
+				TwoFA: models.TwoFA{SentTo: "admin@mail.me", DeliveryMethod: "email"},
+			},
+			expectedError: "2 factor auth is required to login, please run 'rportcli init' to login again",
+		},
+		{
+			name:          "no token",
+			expectedError: "no auth token received from rport",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/v1/login?token-lifetime=10", r.URL.String())
+				e := json.NewEncoder(rw).Encode(LoginResponse{Data: tc.tokenToGive})
+				assert.NoError(t, e)
+			}))
+			defer srv.Close()
+
+			cl := New(srv.URL, &utils.StorageBasicAuth{
+				AuthProvider: func() (login, pass string, err error) {
+					return "admin", "pass", nil
+				},
+			})
+
+			token, err := cl.RefreshToken(context.Background(), 10)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedToken, token)
+		})
+	}
+}
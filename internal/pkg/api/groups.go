@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/breathbath/go_utils/v2/pkg/url"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+const (
+	ClientGroupsURL = "/api/v1/client-groups"
+	ClientGroupURL  = "/api/v1/client-groups/{group_id}"
+)
+
+type ClientGroupsResponse struct {
+	Data []*models.ClientGroup
+}
+
+type ClientGroupResponse struct {
+	Data *models.ClientGroup
+}
+
+func (rp *Rport) ClientGroups(ctx context.Context) (groupsResp *ClientGroupsResponse, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, ClientGroupsURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	groupsResp = &ClientGroupsResponse{}
+	_, err = rp.CallBaseClient(req, groupsResp)
+
+	return groupsResp, err
+}
+
+func (rp *Rport) ClientGroup(ctx context.Context, groupID string) (groupResp *ClientGroupResponse, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, buildClientGroupURL(groupID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	groupResp = &ClientGroupResponse{}
+	_, err = rp.CallBaseClient(req, groupResp)
+
+	return groupResp, err
+}
+
+func (rp *Rport) CreateClientGroup(ctx context.Context, group *models.ClientGroup) error {
+	return rp.sendClientGroup(ctx, http.MethodPost, url.JoinURL(rp.BaseURL, ClientGroupsURL), group)
+}
+
+func (rp *Rport) UpdateClientGroup(ctx context.Context, group *models.ClientGroup) error {
+	return rp.sendClientGroup(ctx, http.MethodPut, url.JoinURL(rp.BaseURL, buildClientGroupURL(group.ID)), group)
+}
+
+func (rp *Rport) DeleteClientGroup(ctx context.Context, groupID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url.JoinURL(rp.BaseURL, buildClientGroupURL(groupID)), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = rp.CallBaseClient(req, nil)
+
+	return err
+}
+
+func (rp *Rport) sendClientGroup(ctx context.Context, method, groupURL string, group *models.ClientGroup) error {
+	groupToSend := *group
+	// client ids are resolved by the server and cannot be changed
+	groupToSend.ClientIDs = nil
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(groupToSend)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, groupURL, buf)
+	if err != nil {
+		return err
+	}
+
+	_, err = rp.CallBaseClient(req, nil)
+
+	return err
+}
+
+func buildClientGroupURL(groupID string) string {
+	return strings.Replace(ClientGroupURL, "{group_id}", groupID, 1)
+}
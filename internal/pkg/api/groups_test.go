@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestClientGroups(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, ClientGroupsURL, r.URL.String())
+		e := json.NewEncoder(rw).Encode(ClientGroupsResponse{Data: []*models.ClientGroup{
+			{ID: "web", Description: "Web servers", ClientIDs: []string{"cl1"}},
+		}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	groupsResp, err := buildJobsTestAPI(srv.URL).ClientGroups(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*models.ClientGroup{{ID: "web", Description: "Web servers", ClientIDs: []string{"cl1"}}}, groupsResp.Data)
+}
+
+func TestClientGroup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/client-groups/web", r.URL.String())
+		e := json.NewEncoder(rw).Encode(ClientGroupResponse{Data: &models.ClientGroup{
+			ID:     "web",
+			Params: &models.ClientGroupParams{Name: []string{"web*"}},
+		}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	groupResp, err := buildJobsTestAPI(srv.URL).ClientGroup(context.Background(), "web")
+	require.NoError(t, err)
+	assert.Equal(t, &models.ClientGroup{ID: "web", Params: &models.ClientGroupParams{Name: []string{"web*"}}}, groupResp.Data)
+}
+
+func TestCreateAndUpdateClientGroup(t *testing.T) {
+	testCases := []struct {
+		name           string
+		expectedMethod string
+		expectedURL    string
+		send           func(rp *Rport, group *models.ClientGroup) error
+	}{
+		{
+			name:           "create",
+			expectedMethod: http.MethodPost,
+			expectedURL:    ClientGroupsURL,
+			send: func(rp *Rport, group *models.ClientGroup) error {
+				return rp.CreateClientGroup(context.Background(), group)
+			},
+		},
+		{
+			name:           "update",
+			expectedMethod: http.MethodPut,
+			expectedURL:    "/api/v1/client-groups/web",
+			send: func(rp *Rport, group *models.ClientGroup) error {
+				return rp.UpdateClientGroup(context.Background(), group)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			var rawBody []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.expectedMethod, r.Method)
+				assert.Equal(t, tc.expectedURL, r.URL.String())
+
+				var e error
+				rawBody, e = ioutil.ReadAll(r.Body)
+				assert.NoError(t, e)
+				rw.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			err := tc.send(buildJobsTestAPI(srv.URL), &models.ClientGroup{
+				ID:          "web",
+				Description: "Web servers",
+				Params:      &models.ClientGroupParams{Name: []string{"web*"}, OSFamily: []string{"linux"}},
+				ClientIDs:   []string{"cl1"},
+			})
+			require.NoError(t, err)
+			assert.JSONEq(
+				t,
+				`{"id":"web","description":"Web servers","params":{"name":["web*"],"os_family":["linux"]}}`,
+				string(rawBody),
+			)
+		})
+	}
+}
+
+func TestDeleteClientGroup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/v1/client-groups/web", r.URL.String())
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	err := buildJobsTestAPI(srv.URL).DeleteClientGroup(context.Background(), "web")
+	require.NoError(t, err)
+}
@@ -22,6 +22,8 @@ func New(baseURL string, a Auth) *Rport {
 	return &Rport{BaseURL: baseURL, Auth: a}
 }
 
+// CallBaseClient sends the request and decodes the response to the target, the response is returned also
+// on errors, so callers can check the status code of failed requests
 func (rp *Rport) CallBaseClient(req *http.Request, target interface{}) (resp *http.Response, err error) {
 	cl := &utils.BaseClient{}
 	cl.WithAuth(rp.Auth)
@@ -29,7 +31,7 @@ func (rp *Rport) CallBaseClient(req *http.Request, target interface{}) (resp *ht
 	var errResp models.ErrorResp
 
 	resp, err = cl.Call(req, target, &errResp)
-	if err != nil {
+	if resp == nil {
 		return nil, err
 	}
 
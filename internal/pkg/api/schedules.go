@@ -0,0 +1,102 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/breathbath/go_utils/v2/pkg/url"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+const (
+	SchedulesURL = "/api/v1/schedules"
+	ScheduleURL  = "/api/v1/schedules/{schedule_id}"
+)
+
+type SchedulesResponse struct {
+	Data []*models.Schedule
+}
+
+type ScheduleResponse struct {
+	Data *models.Schedule
+}
+
+func (rp *Rport) Schedules(ctx context.Context) (schedulesResp *SchedulesResponse, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, SchedulesURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	schedulesResp = &SchedulesResponse{}
+	_, err = rp.CallBaseClient(req, schedulesResp)
+
+	return schedulesResp, err
+}
+
+func (rp *Rport) Schedule(ctx context.Context, scheduleID string) (scheduleResp *ScheduleResponse, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, buildScheduleURL(scheduleID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleResp = &ScheduleResponse{}
+	_, err = rp.CallBaseClient(req, scheduleResp)
+
+	return scheduleResp, err
+}
+
+// CreateSchedule adds a schedule, the response contains the id given by the server
+func (rp *Rport) CreateSchedule(ctx context.Context, schedule *models.Schedule) (*ScheduleResponse, error) {
+	return rp.sendSchedule(ctx, http.MethodPost, url.JoinURL(rp.BaseURL, SchedulesURL), schedule)
+}
+
+func (rp *Rport) UpdateSchedule(ctx context.Context, schedule *models.Schedule) (*ScheduleResponse, error) {
+	return rp.sendSchedule(ctx, http.MethodPut, url.JoinURL(rp.BaseURL, buildScheduleURL(schedule.ID)), schedule)
+}
+
+func (rp *Rport) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url.JoinURL(rp.BaseURL, buildScheduleURL(scheduleID)), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = rp.CallBaseClient(req, nil)
+
+	return err
+}
+
+func (rp *Rport) sendSchedule(ctx context.Context, method, scheduleURL string, schedule *models.Schedule) (*ScheduleResponse, error) {
+	// id and metadata are given by the server and next runs are calculated by the cli
+	scheduleToSend := &models.Schedule{
+		Name:     schedule.Name,
+		Schedule: schedule.Schedule,
+		Type:     schedule.Type,
+		Details:  schedule.Details,
+	}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(scheduleToSend)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, scheduleURL, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleResp := &ScheduleResponse{}
+	_, err = rp.CallBaseClient(req, scheduleResp)
+
+	return scheduleResp, err
+}
+
+func buildScheduleURL(scheduleID string) string {
+	return strings.Replace(ScheduleURL, "{schedule_id}", scheduleID, 1)
+}
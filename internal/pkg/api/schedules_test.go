@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestSchedules(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, SchedulesURL, r.URL.String())
+		_, e := rw.Write([]byte(`{"data":[{"id":"5","name":"cleanup","schedule":"0 3 * * *","type":"command",` +
+			`"details":{"client_ids":["cl1"],"command":"rm -rf /tmp/*","timeout_sec":60}}]}`))
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	schedulesResp, err := buildJobsTestAPI(srv.URL).Schedules(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Schedule{
+		{
+			ID:       "5",
+			Name:     "cleanup",
+			Schedule: "0 3 * * *",
+			Type:     models.ScheduleTypeCommand,
+			Details: models.WsScriptCommand{
+				ClientIDs:  []string{"cl1"},
+				Command:    "rm -rf /tmp/*",
+				TimeoutSec: 60,
+			},
+		},
+	}, schedulesResp.Data)
+}
+
+func TestSchedule(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/schedules/5", r.URL.String())
+		e := json.NewEncoder(rw).Encode(ScheduleResponse{Data: &models.Schedule{ID: "5", Name: "cleanup"}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	scheduleResp, err := buildJobsTestAPI(srv.URL).Schedule(context.Background(), "5")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Schedule{ID: "5", Name: "cleanup"}, scheduleResp.Data)
+}
+
+func TestCreateAndUpdateSchedule(t *testing.T) {
+	testCases := []struct {
+		name           string
+		expectedMethod string
+		expectedURL    string
+		send           func(rp *Rport, schedule *models.Schedule) (*ScheduleResponse, error)
+	}{
+		{
+			name:           "create",
+			expectedMethod: http.MethodPost,
+			expectedURL:    SchedulesURL,
+			send: func(rp *Rport, schedule *models.Schedule) (*ScheduleResponse, error) {
+				return rp.CreateSchedule(context.Background(), schedule)
+			},
+		},
+		{
+			name:           "update",
+			expectedMethod: http.MethodPut,
+			expectedURL:    "/api/v1/schedules/5",
+			send: func(rp *Rport, schedule *models.Schedule) (*ScheduleResponse, error) {
+				return rp.UpdateSchedule(context.Background(), schedule)
+			},
+		},
+	}
+
+	createdAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			var rawBody []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.expectedMethod, r.Method)
+				assert.Equal(t, tc.expectedURL, r.URL.String())
+
+				var e error
+				rawBody, e = ioutil.ReadAll(r.Body)
+				assert.NoError(t, e)
+
+				e = json.NewEncoder(rw).Encode(ScheduleResponse{Data: &models.Schedule{ID: "5", Name: "cleanup"}})
+				assert.NoError(t, e)
+			}))
+			defer srv.Close()
+
+			scheduleResp, err := tc.send(buildJobsTestAPI(srv.URL), &models.Schedule{
+				ID:        "5",
+				Name:      "cleanup",
+				Schedule:  "0 3 * * *",
+				Type:      models.ScheduleTypeCommand,
+				Details:   models.WsScriptCommand{ClientIDs: []string{"cl1"}, Command: "rm -rf /tmp/*", TimeoutSec: 60},
+				CreatedBy: "admin",
+				CreatedAt: &createdAt,
+				NextRuns:  []time.Time{createdAt},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "5", scheduleResp.Data.ID)
+
+			assert.JSONEq(t, `{
+				"name":"cleanup",
+				"schedule":"0 3 * * *",
+				"type":"command",
+				"details":{
+					"client_ids":["cl1"],
+					"is_sudo":false,
+					"execute_concurrently":false,
+					"abort_on_error":false,
+					"timeout_sec":60,
+					"command":"rm -rf /tmp/*",
+					"script":"",
+					"cwd":"",
+					"interpreter":""
+				}
+			}`, string(rawBody))
+		})
+	}
+}
+
+func TestDeleteSchedule(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/v1/schedules/5", r.URL.String())
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	err := buildJobsTestAPI(srv.URL).DeleteSchedule(context.Background(), "5")
+	require.NoError(t, err)
+}
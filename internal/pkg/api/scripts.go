@@ -0,0 +1,102 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/breathbath/go_utils/v2/pkg/url"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+const (
+	ScriptsURL = "/api/v1/library/scripts"
+	ScriptURL  = "/api/v1/library/scripts/{script_id}"
+)
+
+type ScriptsResponse struct {
+	Data []*models.Script
+}
+
+type ScriptResponse struct {
+	Data *models.Script
+}
+
+// Scripts lists the script library, the content of scripts might be omitted by the server
+func (rp *Rport) Scripts(ctx context.Context) (scriptsResp *ScriptsResponse, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, ScriptsURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scriptsResp = &ScriptsResponse{}
+	_, err = rp.CallBaseClient(req, scriptsResp)
+
+	return scriptsResp, err
+}
+
+func (rp *Rport) Script(ctx context.Context, scriptID string) (scriptResp *ScriptResponse, err error) {
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url.JoinURL(rp.BaseURL, buildScriptURL(scriptID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scriptResp = &ScriptResponse{}
+	_, err = rp.CallBaseClient(req, scriptResp)
+
+	return scriptResp, err
+}
+
+// CreateScript adds a script to the library, the response contains the id given by the server
+func (rp *Rport) CreateScript(ctx context.Context, script *models.Script) (*ScriptResponse, error) {
+	return rp.sendScript(ctx, http.MethodPost, url.JoinURL(rp.BaseURL, ScriptsURL), script)
+}
+
+func (rp *Rport) UpdateScript(ctx context.Context, script *models.Script) (*ScriptResponse, error) {
+	return rp.sendScript(ctx, http.MethodPut, url.JoinURL(rp.BaseURL, buildScriptURL(script.ID)), script)
+}
+
+func (rp *Rport) DeleteScript(ctx context.Context, scriptID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url.JoinURL(rp.BaseURL, buildScriptURL(scriptID)), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = rp.CallBaseClient(req, nil)
+
+	return err
+}
+
+func (rp *Rport) sendScript(ctx context.Context, method, scriptURL string, script *models.Script) (*ScriptResponse, error) {
+	scriptToSend := *script
+	// id and metadata are given by the server and cannot be changed
+	scriptToSend.ID = ""
+	scriptToSend.CreatedBy = ""
+	scriptToSend.CreatedAt = nil
+	scriptToSend.UpdatedAt = nil
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(scriptToSend)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, scriptURL, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	scriptResp := &ScriptResponse{}
+	_, err = rp.CallBaseClient(req, scriptResp)
+
+	return scriptResp, err
+}
+
+func buildScriptURL(scriptID string) string {
+	return strings.Replace(ScriptURL, "{script_id}", scriptID, 1)
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestScripts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, ScriptsURL, r.URL.String())
+		e := json.NewEncoder(rw).Encode(ScriptsResponse{Data: []*models.Script{
+			{ID: "123", Name: "uptime", Interpreter: "/bin/sh", Tags: []string{"linux"}},
+		}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	scriptsResp, err := buildJobsTestAPI(srv.URL).Scripts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Script{{ID: "123", Name: "uptime", Interpreter: "/bin/sh", Tags: []string{"linux"}}}, scriptsResp.Data)
+}
+
+func TestScript(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/library/scripts/123", r.URL.String())
+		e := json.NewEncoder(rw).Encode(ScriptResponse{Data: &models.Script{ID: "123", Name: "uptime", Content: "uptime"}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	scriptResp, err := buildJobsTestAPI(srv.URL).Script(context.Background(), "123")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Script{ID: "123", Name: "uptime", Content: "uptime"}, scriptResp.Data)
+}
+
+func TestCreateAndUpdateScript(t *testing.T) {
+	testCases := []struct {
+		name           string
+		expectedMethod string
+		expectedURL    string
+		send           func(rp *Rport, script *models.Script) (*ScriptResponse, error)
+	}{
+		{
+			name:           "create",
+			expectedMethod: http.MethodPost,
+			expectedURL:    ScriptsURL,
+			send: func(rp *Rport, script *models.Script) (*ScriptResponse, error) {
+				return rp.CreateScript(context.Background(), script)
+			},
+		},
+		{
+			name:           "update",
+			expectedMethod: http.MethodPut,
+			expectedURL:    "/api/v1/library/scripts/123",
+			send: func(rp *Rport, script *models.Script) (*ScriptResponse, error) {
+				return rp.UpdateScript(context.Background(), script)
+			},
+		},
+	}
+
+	updatedAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			var rawBody []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.expectedMethod, r.Method)
+				assert.Equal(t, tc.expectedURL, r.URL.String())
+
+				var e error
+				rawBody, e = ioutil.ReadAll(r.Body)
+				assert.NoError(t, e)
+
+				e = json.NewEncoder(rw).Encode(ScriptResponse{Data: &models.Script{ID: "123", Name: "uptime"}})
+				assert.NoError(t, e)
+			}))
+			defer srv.Close()
+
+			scriptResp, err := tc.send(buildJobsTestAPI(srv.URL), &models.Script{
+				ID:          "123",
+				Name:        "uptime",
+				Interpreter: "/bin/bash",
+				IsSudo:      true,
+				Cwd:         "/tmp",
+				Tags:        []string{"linux", "monitoring"},
+				Content:     "uptime\n",
+				CreatedBy:   "admin",
+				UpdatedAt:   &updatedAt,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "123", scriptResp.Data.ID)
+			assert.JSONEq(
+				t,
+				`{"name":"uptime","interpreter":"/bin/bash","is_sudo":true,"cwd":"/tmp","tags":["linux","monitoring"],"script":"uptime\n"}`,
+				string(rawBody),
+			)
+		})
+	}
+}
+
+func TestDeleteScript(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/v1/library/scripts/123", r.URL.String())
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	err := buildJobsTestAPI(srv.URL).DeleteScript(context.Background(), "123")
+	require.NoError(t, err)
+}
@@ -35,3 +35,14 @@ func ParseToken(tokenStr, signature string) (tokCtx *Token, err error) {
 
 	return token, err
 }
+
+// ParseUnverifiedClaims reads claims of the token without checking the signature which is only known to the server
+func ParseUnverifiedClaims(tokenStr string) (*Claims, error) {
+	claims := &Claims{}
+	_, _, err := new(jwt.Parser).ParseUnverified(tokenStr, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	TotPPeriod = 30 * time.Second
+	TotPDigits = 6
+)
+
+// GenerateTotPCode generates a time based one-time password as defined in RFC 6238 from a base32 encoded secret,
+// as it's done by authenticator apps
+func GenerateTotPCode(secret string, t time.Time) (string, error) {
+	key, err := decodeTotPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return generateHOTPCode(key, uint64(t.Unix()/int64(TotPPeriod/time.Second)), TotPDigits), nil
+}
+
+// decodeTotPSecret decodes base32 secrets ignoring case, spaces and missing padding
+func decodeTotPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	if secret == "" {
+		return nil, fmt.Errorf("empty TOTP secret")
+	}
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TOTP secret, a base32 encoded value is expected: %v", err)
+	}
+
+	return key, nil
+}
+
+// generateHOTPCode gives the counter based one-time password as defined in RFC 4226
+func generateHOTPCode(key []byte, counter uint64, digits int) string {
+	counterBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(counterBytes, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counterBytes)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	binCode := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, binCode%modulo)
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// base32 encoded secret "12345678901234567890" of the RFC 6238 test vectors
+const rfcTotPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTotPCode(t *testing.T) {
+	testCases := []struct {
+		unixTime     int64
+		expectedCode string
+	}{
+		{
+			unixTime:     59,
+			expectedCode: "287082",
+		},
+		{
+			unixTime:     1111111109,
+			expectedCode: "081804",
+		},
+		{
+			unixTime:     1111111111,
+			expectedCode: "050471",
+		},
+		{
+			unixTime:     1234567890,
+			expectedCode: "005924",
+		},
+		{
+			unixTime:     2000000000,
+			expectedCode: "279037",
+		},
+	}
+
+	for _, tc := range testCases {
+		code, err := GenerateTotPCode(rfcTotPSecret, time.Unix(tc.unixTime, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tc.expectedCode, code, tc.unixTime)
+	}
+}
+
+func TestGenerateTotPCodeNormalizesSecret(t *testing.T) {
+	code, err := GenerateTotPCode("gezd gnbv gy3t qojq gezd gnbv gy3t qojq", time.Unix(59, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", code)
+}
+
+func TestGenerateTotPCodeInvalidSecret(t *testing.T) {
+	_, err := GenerateTotPCode("not base32!", time.Now())
+	assert.EqualError(t, err, "invalid TOTP secret, a base32 encoded value is expected: illegal base32 data at input byte 9")
+
+	_, err = GenerateTotPCode("", time.Now())
+	assert.EqualError(t, err, "empty TOTP secret")
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -10,16 +11,25 @@ import (
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
 )
 
+// FilterFlag is the name of a repeatable param with client filter expressions like 'os_family=linux'
+const FilterFlag = "filter"
+
 type DataProvider interface {
-	GetClients(ctx context.Context) (cls []*models.Client, err error)
+	GetClients(ctx context.Context, filters ...*models.ClientFilter) (cls []*models.Client, err error)
 }
 
 type Search struct {
 	DataProvider DataProvider
 }
 
+// Search finds clients by comma separated name or id prefixes which also match the filters from params
 func (s *Search) Search(ctx context.Context, term string, params *options.ParameterBag) (foundCls []*models.Client, err error) {
-	cls, err := s.DataProvider.GetClients(ctx)
+	filters, err := ReadFilters(params)
+	if err != nil {
+		return foundCls, err
+	}
+
+	cls, err := s.DataProvider.GetClients(ctx, filters...)
 	if err != nil {
 		return foundCls, err
 	}
@@ -28,6 +38,20 @@ func (s *Search) Search(ctx context.Context, term string, params *options.Parame
 	return
 }
 
+// ReadFilters parses client filters from params
+func ReadFilters(params *options.ParameterBag) ([]*models.ClientFilter, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	return models.ParseClientFilters(params.ReadStrings(FilterFlag))
+}
+
+// HasFilters tells if any client filter is provided in params
+func HasFilters(params *options.ParameterBag) bool {
+	return params != nil && len(params.ReadStrings(FilterFlag)) > 0
+}
+
 func (s *Search) FindOne(ctx context.Context, searchTerm string, params *options.ParameterBag) (*models.Client, error) {
 	clients, err := s.Search(ctx, searchTerm, params)
 	if err != nil {
@@ -35,6 +59,9 @@ func (s *Search) FindOne(ctx context.Context, searchTerm string, params *options
 	}
 
 	if len(clients) == 0 {
+		if searchTerm == "" {
+			return &models.Client{}, errors.New("no client matches the provided filters")
+		}
 		return &models.Client{}, fmt.Errorf("unknown client '%s'", searchTerm)
 	}
 
@@ -42,6 +69,10 @@ func (s *Search) FindOne(ctx context.Context, searchTerm string, params *options
 		return clients[0], nil
 	}
 
+	if searchTerm == "" {
+		return &models.Client{}, errors.New("the provided filters match multiple clients, use more precise filters or use the client id")
+	}
+
 	return &models.Client{}, fmt.Errorf("client identified by '%s' is ambiguous, use a more precise name or use the client id", searchTerm)
 }
 
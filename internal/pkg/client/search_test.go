@@ -29,9 +29,11 @@ var clientsList = []*models.Client{
 type DataProviderMock struct {
 	clientsToGive []*models.Client
 	errToGive     error
+	filtersGiven  []*models.ClientFilter
 }
 
-func (dpm *DataProviderMock) GetClients(ctx context.Context) (cls []*models.Client, err error) {
+func (dpm *DataProviderMock) GetClients(ctx context.Context, filters ...*models.ClientFilter) (cls []*models.Client, err error) {
+	dpm.filtersGiven = filters
 	return dpm.clientsToGive, dpm.errToGive
 }
 
@@ -92,3 +94,129 @@ func TestFindByAmbiguousClientName(t *testing.T) {
 	_, err := search.FindOne(context.Background(), "my tiny", &options.ParameterBag{})
 	assert.EqualError(t, err, `client identified by 'my tiny' is ambiguous, use a more precise name or use the client id`)
 }
+
+func TestSearchWithFilters(t *testing.T) {
+	dataProvider := &DataProviderMock{
+		clientsToGive: clientsList,
+	}
+	search := Search{
+		DataProvider: dataProvider,
+	}
+
+	params := options.New(options.NewMapValuesProvider(map[string]interface{}{
+		FilterFlag: []string{"os_family=linux", "tags=prod,web*", "tags=db"},
+	}))
+
+	foundCls, err := search.Search(context.Background(), "my tiny client", params)
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.Client{clientsList[0]}, foundCls)
+	assert.Equal(t, []*models.ClientFilter{
+		{Field: "os_family", Values: []string{"linux"}},
+		{Field: "tags", Values: []string{"prod", "web*", "db"}},
+	}, dataProvider.filtersGiven)
+}
+
+func TestSearchWithInvalidFilters(t *testing.T) {
+	testCases := []struct {
+		filter        string
+		expectedError string
+	}{
+		{
+			filter:        "os_family",
+			expectedError: "invalid filter 'os_family', expected format is field=value1,value2",
+		},
+		{
+			filter:        "color=red",
+			expectedError: "unknown client filter field 'color', supported fields are",
+		},
+	}
+
+	for _, tc := range testCases {
+		search := Search{
+			DataProvider: &DataProviderMock{},
+		}
+
+		params := options.New(options.NewMapValuesProvider(map[string]interface{}{
+			FilterFlag: []string{tc.filter},
+		}))
+
+		_, err := search.Search(context.Background(), "", params)
+		assert.Error(t, err)
+		if err != nil {
+			assert.Contains(t, err.Error(), tc.expectedError)
+		}
+	}
+}
+
+func TestFindOneByFilters(t *testing.T) {
+	search := Search{
+		DataProvider: &DataProviderMock{
+			clientsToGive: clientsList,
+		},
+	}
+
+	params := options.New(options.NewMapValuesProvider(map[string]interface{}{
+		FilterFlag: []string{"name=*tiny*"},
+	}))
+
+	_, err := search.FindOne(context.Background(), "", params)
+	assert.EqualError(t, err, "the provided filters match multiple clients, use more precise filters or use the client id")
+}
+
+func TestFilterClients(t *testing.T) {
+	clients := []*models.Client{
+		{ID: "1", Name: "web-1", OsFamily: "Linux", Tags: []string{"prod", "web"}, ConnState: "connected"},
+		{ID: "2", Name: "web-2", OsFamily: "linux", Tags: []string{"staging"}, ConnState: "connected"},
+		{ID: "3", Name: "db-1", OsFamily: "windows", Tags: []string{"prod"}, ConnState: "disconnected"},
+	}
+
+	testCases := []struct {
+		name        string
+		filters     []string
+		expectedIDs []string
+	}{
+		{
+			name:        "no filters",
+			expectedIDs: []string{"1", "2", "3"},
+		},
+		{
+			name:        "case insensitive",
+			filters:     []string{"os_family=LINUX"},
+			expectedIDs: []string{"1", "2"},
+		},
+		{
+			name:        "wildcard",
+			filters:     []string{"name=*-1"},
+			expectedIDs: []string{"1", "3"},
+		},
+		{
+			name:        "multiple values",
+			filters:     []string{"tags=staging,web"},
+			expectedIDs: []string{"1", "2"},
+		},
+		{
+			name:        "multiple fields",
+			filters:     []string{"tags=prod", "connection_state=connected"},
+			expectedIDs: []string{"1"},
+		},
+		{
+			name:        "no match",
+			filters:     []string{"tags=qa"},
+			expectedIDs: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			filters, err := models.ParseClientFilters(tc.filters)
+			assert.NoError(t, err)
+
+			actualIDs := make([]string, 0)
+			for _, cl := range models.FilterClients(clients, filters) {
+				actualIDs = append(actualIDs, cl.ID)
+			}
+			assert.Equal(t, tc.expectedIDs, actualIDs)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+const (
+	commandTemplatesFileName = "commands.yaml"
+	commandTemplateIDLength  = 4
+)
+
+// LocalCommandTemplates keeps command templates in a yaml file, it's used if the rport server has no command library
+type LocalCommandTemplates struct {
+	Path string
+}
+
+// NewLocalCommandTemplates gives command templates stored next to the config file
+func NewLocalCommandTemplates() *LocalCommandTemplates {
+	return &LocalCommandTemplates{
+		Path: filepath.Join(filepath.Dir(getConfigLocation()), commandTemplatesFileName),
+	}
+}
+
+func (lct *LocalCommandTemplates) CommandTemplates(ctx context.Context) ([]*models.CommandTemplate, error) {
+	return lct.read()
+}
+
+func (lct *LocalCommandTemplates) CommandTemplate(ctx context.Context, templateID string) (*models.CommandTemplate, error) {
+	templates, err := lct.read()
+	if err != nil {
+		return nil, err
+	}
+
+	i := findCommandTemplate(templates, templateID)
+	if i < 0 {
+		return nil, fmt.Errorf("command template '%s' not found in %s", templateID, lct.Path)
+	}
+
+	return templates[i], nil
+}
+
+func (lct *LocalCommandTemplates) CreateCommandTemplate(
+	ctx context.Context,
+	template *models.CommandTemplate,
+) (*models.CommandTemplate, error) {
+	templates, err := lct.read()
+	if err != nil {
+		return nil, err
+	}
+
+	templateToSave := *template
+	templateToSave.ID, err = generateCommandTemplateID(templates)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lct.write(append(templates, &templateToSave))
+	if err != nil {
+		return nil, err
+	}
+
+	return &templateToSave, nil
+}
+
+func (lct *LocalCommandTemplates) UpdateCommandTemplate(ctx context.Context, template *models.CommandTemplate) error {
+	templates, err := lct.read()
+	if err != nil {
+		return err
+	}
+
+	i := findCommandTemplate(templates, template.ID)
+	if i < 0 {
+		return fmt.Errorf("command template '%s' not found in %s", template.ID, lct.Path)
+	}
+	templates[i] = template
+
+	return lct.write(templates)
+}
+
+func (lct *LocalCommandTemplates) DeleteCommandTemplate(ctx context.Context, templateID string) error {
+	templates, err := lct.read()
+	if err != nil {
+		return err
+	}
+
+	i := findCommandTemplate(templates, templateID)
+	if i < 0 {
+		return fmt.Errorf("command template '%s' not found in %s", templateID, lct.Path)
+	}
+
+	return lct.write(append(templates[:i], templates[i+1:]...))
+}
+
+func (lct *LocalCommandTemplates) read() ([]*models.CommandTemplate, error) {
+	data, err := ioutil.ReadFile(lct.Path)
+	if os.IsNotExist(err) {
+		return []*models.CommandTemplate{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	templates := []*models.CommandTemplate{}
+	err = yaml.Unmarshal(data, &templates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", lct.Path, err)
+	}
+
+	return templates, nil
+}
+
+func (lct *LocalCommandTemplates) write(templates []*models.CommandTemplate) error {
+	err := os.MkdirAll(filepath.Dir(lct.Path), 0755)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(templates)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(lct.Path, data, 0600)
+}
+
+func findCommandTemplate(templates []*models.CommandTemplate, templateID string) int {
+	for i, template := range templates {
+		if template.ID == templateID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// generateCommandTemplateID gives a random id which is not used by any of the templates
+func generateCommandTemplateID(templates []*models.CommandTemplate) (string, error) {
+	randomBytes := make([]byte, commandTemplateIDLength)
+	for {
+		_, err := rand.Read(randomBytes)
+		if err != nil {
+			return "", err
+		}
+
+		templateID := hex.EncodeToString(randomBytes)
+		if findCommandTemplate(templates, templateID) < 0 {
+			return templateID, nil
+		}
+	}
+}
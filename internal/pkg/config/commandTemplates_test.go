@@ -0,0 +1,63 @@
+package config
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestLocalCommandTemplates(t *testing.T) {
+	ctx := context.Background()
+	lct := &LocalCommandTemplates{Path: filepath.Join(t.TempDir(), "rportcli", "commands.yaml")}
+
+	templates, err := lct.CommandTemplates(ctx)
+	require.NoError(t, err)
+	assert.Len(t, templates, 0, "missing file should give no templates")
+
+	created, err := lct.CreateCommandTemplate(ctx, &models.CommandTemplate{
+		Name: "restart",
+		Cmd:  "systemctl restart {{service}}",
+		Tags: []string{"linux"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+
+	_, err = lct.CreateCommandTemplate(ctx, &models.CommandTemplate{Name: "uptime", Cmd: "uptime"})
+	require.NoError(t, err)
+
+	created.Cmd = "systemctl reload {{service}}"
+	require.NoError(t, lct.UpdateCommandTemplate(ctx, created))
+
+	template, err := lct.CommandTemplate(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &models.CommandTemplate{
+		ID:   created.ID,
+		Name: "restart",
+		Cmd:  "systemctl reload {{service}}",
+		Tags: []string{"linux"},
+	}, template)
+
+	require.NoError(t, lct.DeleteCommandTemplate(ctx, created.ID))
+
+	templates, err = lct.CommandTemplates(ctx)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "uptime", templates[0].Name)
+
+	_, err = lct.CommandTemplate(ctx, created.ID)
+	assert.EqualError(t, err, "command template '"+created.ID+"' not found in "+lct.Path)
+}
+
+func TestLocalCommandTemplatesInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commands.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("name: restart"), 0600))
+
+	_, err := (&LocalCommandTemplates{Path: path}).CommandTemplates(context.Background())
+	assert.Error(t, err)
+}
@@ -4,6 +4,11 @@ const (
 	PathForConfigEnvVar          = "CONFIG_PATH"
 	LoginEnvVar                  = "RPORT_USER"
 	PasswordEnvVar               = "RPORT_PASSWORD"
+	APITokenEnvVar               = "RPORT_API_TOKEN"
+	TwoFACodeEnvVar              = "RPORT_2FA_CODE"
+	TotPSecretEnvVar             = "RPORT_TOTP_SECRET"
 	ServerURLEnvVar              = "RPORT_SERVER_URL"
 	SessionValiditySecondsEnvVar = "SESSION_VALIDITY_SECONDS"
+	ProfileEnvVar                = "RPORT_PROFILE"
+	SecretsPassphraseEnvVar      = "RPORT_SECRETS_PASSPHRASE"
 )
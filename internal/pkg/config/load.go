@@ -10,8 +10,6 @@ import (
 	"strconv"
 	"strings"
 
-	io2 "github.com/breathbath/go_utils/v2/pkg/io"
-
 	"github.com/spf13/pflag"
 
 	"github.com/spf13/cobra"
@@ -29,12 +27,17 @@ const (
 	Login            = "login"
 	Token            = "token"
 	Password         = "password"
+	APIToken         = "api-token"
+	TwoFACode        = "2fa-code"
+	TotPSecret       = "totp-secret"
+	Profile          = "profile"
 	DefaultServerURL = "http://localhost:3000"
 )
 
 func LoadParamsFromFileAndEnv(flags *pflag.FlagSet) (params *options.ParameterBag) {
+	profileName := ResolveProfileName(flags)
 	envValuesProvider := CreateEnvValuesProvider()
-	fileValuesProvider, err := CreateFileValuesProvider()
+	fileValuesProvider, err := CreateFileValuesProvider(profileName)
 	if err != nil {
 		logrus.Warn(err)
 		return options.New(envValuesProvider)
@@ -42,13 +45,24 @@ func LoadParamsFromFileAndEnv(flags *pflag.FlagSet) (params *options.ParameterBa
 
 	flagValuesProvider := CreateFlagValuesProvider(flags)
 
-	valuesProvider := options.NewValuesProviderComposite(envValuesProvider, flagValuesProvider, fileValuesProvider)
+	valuesProvider := options.NewValuesProviderComposite(
+		createProfileNameProvider(profileName),
+		envValuesProvider,
+		flagValuesProvider,
+		fileValuesProvider,
+	)
 
 	paramsToReturn := options.New(valuesProvider)
 
 	return paramsToReturn
 }
 
+// LoadParamsFromFlags gives only the provided flags, it's used by commands which must not fall back
+// to values of the current profile or env variables, e.g. when a new profile is created
+func LoadParamsFromFlags(flags *pflag.FlagSet) *options.ParameterBag {
+	return options.New(CreateFlagValuesProvider(flags))
+}
+
 type FlagValuesProvider struct {
 	flags *pflag.FlagSet
 }
@@ -78,6 +92,10 @@ func (fvp *FlagValuesProvider) Read(name string) (val interface{}, found bool) {
 		return nil, false
 	}
 
+	if sliceVal, ok := fl.Value.(pflag.SliceValue); ok {
+		return sliceVal.GetSlice(), true
+	}
+
 	return fl.Value.String(), true
 }
 
@@ -85,6 +103,9 @@ func CreateEnvValuesProvider() options.ValuesProvider {
 	envsToRead := map[string]string{
 		Password:            PasswordEnvVar,
 		Login:               LoginEnvVar,
+		APIToken:            APITokenEnvVar,
+		TwoFACode:           TwoFACodeEnvVar,
+		TotPSecret:          TotPSecretEnvVar,
 		ServerURL:           ServerURLEnvVar,
 		PathForConfigEnvVar: PathForConfigEnvVar,
 	}
@@ -100,28 +121,79 @@ func CreateEnvValuesProvider() options.ValuesProvider {
 	return options.NewMapValuesProvider(envMapValues)
 }
 
-func CreateFileValuesProvider() (options.ValuesProvider, error) {
+func createProfileNameProvider(profileName string) options.ValuesProvider {
+	profileValues := map[string]interface{}{}
+	if profileName != "" {
+		profileValues[Profile] = profileName
+	}
+
+	return options.NewMapValuesProvider(profileValues)
+}
+
+// CreateFileValuesProvider reads values of the given profile from the config file,
+// if profile name is empty, the current profile is used
+func CreateFileValuesProvider(profileName string) (options.ValuesProvider, error) {
 	configFilePath := getConfigLocation()
 	if !fs.FileExists(configFilePath) {
 		return nil, fmt.Errorf("config file %s doesn't exist", configFilePath)
 	}
 
-	f, err := os.Open(configFilePath)
+	profiles, err := ReadProfiles()
 	if err != nil {
-		err = fmt.Errorf("failed to open the file %s: %v", configFilePath, err)
 		return nil, err
 	}
-	defer io2.CloseResourceSecure("config file", f)
 
-	jvp, err := options.NewJSONValuesProvider(f)
+	resolvedProfileName := profiles.Resolve(profileName)
+	profileValues, ok := profiles.Get(resolvedProfileName)
+	if !ok {
+		return nil, fmt.Errorf("profile '%s' doesn't exist in the config file %s", resolvedProfileName, configFilePath)
+	}
+
+	err = loadProfileSecrets(profileValues)
 	if err != nil {
-		return nil, err
+		logrus.Warnf("failed to load secrets of profile '%s': %v", resolvedProfileName, err)
+	}
+
+	return createProfileValuesProvider(profileValues)
+}
+
+// loadProfileSecrets adds secrets from the secret store of the profile to the profile values
+func loadProfileSecrets(values map[string]interface{}) error {
+	store, err := NewProfileSecretStore(values)
+	if err != nil {
+		return err
+	}
+
+	return store.Load(values)
+}
+
+// DeleteConfig removes the profile given in params or the current one from the config file
+func DeleteConfig(params *options.ParameterBag) (err error) {
+	profiles, err := ReadProfiles()
+	if err != nil {
+		return err
+	}
+
+	profileName := profiles.Resolve(params.ReadString(Profile, ""))
+	profileValues, ok := profiles.Get(profileName)
+	if !ok {
+		return nil
+	}
+
+	err = DeleteSecrets(profileValues)
+	if err != nil {
+		logrus.Warnf("failed to delete secrets of profile '%s': %v", profileName, err)
+	}
+
+	err = profiles.Remove(profileName)
+	if err != nil {
+		return err
 	}
 
-	return jvp, nil
+	return WriteProfiles(profiles)
 }
 
-func DeleteConfig() (err error) {
+func deleteConfigFile() (err error) {
 	configLocation := getConfigLocation()
 
 	if _, e := os.Stat(configLocation); e == nil {
@@ -134,43 +206,73 @@ func DeleteConfig() (err error) {
 	return nil
 }
 
-// WriteConfig will write config values to file system
+// WriteConfig will write config values of the profile given in params or of the current one to file system
 func WriteConfig(params *options.ParameterBag) (err error) {
-	configLocation := getConfigLocation()
+	profiles, err := ReadProfiles()
+	if err != nil {
+		return err
+	}
 
-	configDir := filepath.Dir(configLocation)
-	if _, e := os.Stat(configDir); os.IsNotExist(e) {
-		err = os.MkdirAll(configDir, 0755)
-		if err != nil {
-			return err
-		}
+	profileName := profiles.Resolve(params.ReadString(Profile, ""))
+	previousValues, _ := profiles.Get(profileName)
+	previousStoreName, _ := previousValues[SecretStoreKey].(string)
+
+	storeName := params.ReadString(SecretStoreFlag, "")
+	if storeName == "" {
+		storeName = previousStoreName
 	}
 
-	configToWrite := map[string]interface{}{
+	values := map[string]interface{}{
 		ServerURL: params.ReadString(ServerURL, ""),
 		Token:     params.ReadString(Token, ""),
 	}
+	// login and api token are kept to use basic auth when the profile is initialized with an api token
+	if apiToken := params.ReadString(APIToken, ""); apiToken != "" {
+		values[Login] = params.ReadString(Login, "")
+		values[APIToken] = apiToken
+	}
+	if storeName != "" && storeName != SecretStorePlain {
+		values[SecretStoreKey] = storeName
+		values[SecretID] = readSecretID(previousValues)
+	}
 
-	err = DeleteConfig()
+	err = saveProfileSecrets(values, previousValues, storeName != previousStoreName)
 	if err != nil {
 		return err
 	}
 
-	fileToWrite, err := os.OpenFile(configLocation, os.O_CREATE|os.O_RDWR, 0600)
+	profiles.Set(profileName, values)
+
+	err = WriteProfiles(profiles)
 	if err != nil {
 		return err
 	}
-	defer io2.CloseResourceSecure("config file", fileToWrite)
 
-	encoder := json.NewEncoder(fileToWrite)
-	err = encoder.Encode(configToWrite)
+	logrus.Infof("created config for profile '%s' at %s", profileName, getConfigLocation())
+
+	return nil
+}
+
+// saveProfileSecrets moves secrets from the profile values to the selected secret store,
+// secrets in the previously used store are deleted if the store is changed
+func saveProfileSecrets(values, previousValues map[string]interface{}, isStoreChanged bool) error {
+	if isStoreChanged && previousValues != nil {
+		err := DeleteSecrets(previousValues)
+		if err != nil {
+			logrus.Warnf("failed to delete secrets from the previous secret store: %v", err)
+		}
+	}
+
+	store, err := NewProfileSecretStore(values)
 	if err != nil {
 		return err
 	}
 
-	logrus.Infof("created config at %s", configLocation)
+	if values[SecretID] == "" {
+		delete(values, SecretID)
+	}
 
-	return nil
+	return store.Save(values)
 }
 
 func getConfigLocation() (configPath string) {
@@ -213,6 +315,8 @@ func DefineCommandInputs(c *cobra.Command, reqs []ParameterRequirement) {
 			} else {
 				c.Flags().IntP(req.Field, req.ShortName, 0, req.Description)
 			}
+		case StringArrayRequirementType:
+			c.Flags().StringArrayP(req.Field, req.ShortName, []string{}, req.Description)
 		default:
 			c.Flags().StringP(req.Field, req.ShortName, defaultStr, req.Description)
 		}
@@ -224,8 +328,10 @@ func LoadParamsFromFileAndEnvAndFlagsAndPrompt(
 	reqs []ParameterRequirement,
 	promptReader PromptReader,
 ) (params *options.ParameterBag, err error) {
+	profileName := ResolveProfileName(c.Flags())
 	envValuesProvider := CreateEnvValuesProvider()
 	valueProviders := []options.ValuesProvider{
+		createProfileNameProvider(profileName),
 		envValuesProvider,
 	}
 
@@ -235,7 +341,7 @@ func LoadParamsFromFileAndEnvAndFlagsAndPrompt(
 	}
 	valueProviders = append(valueProviders, valuesProviderFromCommandAndPrompt)
 
-	jvp, err := CreateFileValuesProvider()
+	jvp, err := CreateFileValuesProvider(profileName)
 	if err != nil {
 		logrus.Warn(err)
 	} else {
@@ -274,6 +380,12 @@ func CollectParamsFromCommandAndPromptAndEnv(
 				return nil, e
 			}
 			paramsRaw[req.Field] = intVal
+		case StringArrayRequirementType:
+			strsVal, e := c.Flags().GetStringArray(req.Field)
+			if e != nil {
+				return nil, e
+			}
+			paramsRaw[req.Field] = strsVal
 		default:
 			strVal, e := c.Flags().GetString(req.Field)
 			if e != nil {
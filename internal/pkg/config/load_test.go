@@ -134,6 +134,27 @@ func TestLoadConfigFromEnvOrFile(t *testing.T) {
 	assert.Equal(t, "https://10.10.10.11:3000", cfg.ReadString(ServerURL, ""))
 }
 
+func TestLoadParamsFromFlagsIgnoresEnv(t *testing.T) {
+	err := os.Setenv(ServerURLEnvVar, "https://prod.example.com")
+	require.NoError(t, err)
+	defer func() {
+		e := os.Unsetenv(ServerURLEnvVar)
+		if e != nil {
+			logrus.Error(e)
+		}
+	}()
+
+	fl := &pflag.FlagSet{}
+	fl.String(ServerURL, "", "")
+
+	params := LoadParamsFromFlags(fl)
+	_, found := params.Read(ServerURL, "")
+	assert.False(t, found)
+
+	require.NoError(t, fl.Parse([]string{"--server", "https://staging.example.com"}))
+	assert.Equal(t, "https://staging.example.com", params.ReadString(ServerURL, ""))
+}
+
 func TestLoadConfigFromFileError(t *testing.T) {
 	err := os.Setenv(PathForConfigEnvVar, "configNotExisting.json")
 	assert.NoError(t, err)
@@ -197,7 +218,11 @@ func TestWriteConfig(t *testing.T) {
 	if err != nil {
 		return
 	}
-	assert.Equal(t, `{"server":"http://localhost:3000","token":"123"}`+"\n", string(fileContents))
+	assert.Equal(
+		t,
+		`{"current_profile":"default","profiles":{"default":{"server":"http://localhost:3000","token":"123"}}}`+"\n",
+		string(fileContents),
+	)
 }
 
 func TestCommandPopulation(t *testing.T) {
@@ -353,3 +378,14 @@ func TestFlagValuesProvider(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, `{"somekey":"someval"}`+"\n", buf.String())
 }
+
+func TestFlagValuesProviderWithSliceFlag(t *testing.T) {
+	fl := &pflag.FlagSet{}
+	fl.StringArray("param", []string{}, "")
+
+	err := fl.Parse([]string{"--param", "name=one,two", "--param", "os=linux"})
+	require.NoError(t, err)
+
+	params := options.New(CreateFlagValuesProvider(fl))
+	assert.Equal(t, []string{"name=one,two", "os=linux"}, params.ReadStrings("param"))
+}
@@ -12,6 +12,8 @@ const (
 	BoolRequirementType   = "bool"
 	StringRequirementType = "string"
 	IntRequirementType    = "int"
+	// StringArrayRequirementType is a repeatable string parameter, its value is read as []string
+	StringArrayRequirementType = "string_array"
 )
 
 // Validate validation callback
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/breathbath/go_utils/v2/pkg/env"
+	"github.com/breathbath/go_utils/v2/pkg/fs"
+	io2 "github.com/breathbath/go_utils/v2/pkg/io"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+)
+
+const (
+	DefaultProfile = "default"
+	profilesKey    = "profiles"
+)
+
+// Profiles is the content of the config file, it keeps config values of each named profile
+type Profiles struct {
+	Current  string                            `json:"current_profile"`
+	Profiles map[string]map[string]interface{} `json:"profiles"`
+}
+
+func NewProfiles() *Profiles {
+	return &Profiles{
+		Profiles: map[string]map[string]interface{}{},
+	}
+}
+
+// Resolve gives the name of the profile which should be used, the explicitly provided name wins over the current one
+func (p *Profiles) Resolve(name string) string {
+	if name != "" {
+		return name
+	}
+
+	if p.Current != "" {
+		return p.Current
+	}
+
+	return DefaultProfile
+}
+
+// Names returns sorted profile names
+func (p *Profiles) Names() []string {
+	names := make([]string, 0, len(p.Profiles))
+	for name := range p.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (p *Profiles) Get(name string) (values map[string]interface{}, ok bool) {
+	values, ok = p.Profiles[name]
+	return
+}
+
+func (p *Profiles) Set(name string, values map[string]interface{}) {
+	p.Profiles[name] = values
+	if p.Current == "" {
+		p.Current = name
+	}
+}
+
+func (p *Profiles) Use(name string) error {
+	if _, ok := p.Profiles[name]; !ok {
+		return fmt.Errorf("unknown profile '%s'", name)
+	}
+
+	p.Current = name
+
+	return nil
+}
+
+func (p *Profiles) Remove(name string) error {
+	if _, ok := p.Profiles[name]; !ok {
+		return fmt.Errorf("unknown profile '%s'", name)
+	}
+
+	delete(p.Profiles, name)
+
+	if p.Current == name {
+		p.Current = ""
+		names := p.Names()
+		if len(names) > 0 {
+			p.Current = names[0]
+		}
+	}
+
+	return nil
+}
+
+func (p *Profiles) Rename(oldName, newName string) error {
+	values, ok := p.Profiles[oldName]
+	if !ok {
+		return fmt.Errorf("unknown profile '%s'", oldName)
+	}
+
+	if _, exists := p.Profiles[newName]; exists {
+		return fmt.Errorf("profile '%s' already exists", newName)
+	}
+
+	delete(p.Profiles, oldName)
+	p.Profiles[newName] = values
+
+	if p.Current == oldName {
+		p.Current = newName
+	}
+
+	return nil
+}
+
+// ResolveProfileName reads profile name from the command flags or from the env variable,
+// empty value means that the current profile from the config file should be used
+func ResolveProfileName(flags *pflag.FlagSet) string {
+	if flags != nil {
+		fl := flags.Lookup(Profile)
+		if fl != nil && fl.Changed {
+			return fl.Value.String()
+		}
+	}
+
+	return env.ReadEnv(ProfileEnvVar, "")
+}
+
+// ReadProfiles reads profiles from the config file, a config file in the old single profile format
+// is converted to the default profile
+func ReadProfiles() (*Profiles, error) {
+	configFilePath := getConfigLocation()
+	if !fs.FileExists(configFilePath) {
+		return NewProfiles(), nil
+	}
+
+	f, err := os.Open(configFilePath)
+	if err != nil {
+		err = fmt.Errorf("failed to open the file %s: %v", configFilePath, err)
+		return nil, err
+	}
+	defer io2.CloseResourceSecure("config file", f)
+
+	return parseProfiles(f)
+}
+
+func parseProfiles(r io.Reader) (*Profiles, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawConfig map[string]json.RawMessage
+	err = json.Unmarshal(data, &rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := NewProfiles()
+	if _, ok := rawConfig[profilesKey]; ok {
+		err = json.Unmarshal(data, profiles)
+		if err != nil {
+			return nil, err
+		}
+		if profiles.Profiles == nil {
+			profiles.Profiles = map[string]map[string]interface{}{}
+		}
+		return profiles, nil
+	}
+
+	logrus.Debugf("config file has a single profile format, will read it as '%s' profile", DefaultProfile)
+	legacyValues := map[string]interface{}{}
+	err = json.Unmarshal(data, &legacyValues)
+	if err != nil {
+		return nil, err
+	}
+	profiles.Set(DefaultProfile, legacyValues)
+
+	return profiles, nil
+}
+
+// WriteProfiles writes profiles to the config file
+func WriteProfiles(profiles *Profiles) (err error) {
+	configLocation := getConfigLocation()
+
+	configDir := filepath.Dir(configLocation)
+	if _, e := os.Stat(configDir); os.IsNotExist(e) {
+		err = os.MkdirAll(configDir, 0755)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(profiles.Profiles) == 0 {
+		return deleteConfigFile()
+	}
+
+	err = deleteConfigFile()
+	if err != nil {
+		return err
+	}
+
+	fileToWrite, err := os.OpenFile(configLocation, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer io2.CloseResourceSecure("config file", fileToWrite)
+
+	encoder := json.NewEncoder(fileToWrite)
+	return encoder.Encode(profiles)
+}
+
+func createProfileValuesProvider(values map[string]interface{}) (options.ValuesProvider, error) {
+	rawValues, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return options.NewJSONValuesProvider(bytes.NewBuffer(rawValues))
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLegacyConfigToDefaultProfile(t *testing.T) {
+	profiles, err := parseProfiles(bytes.NewBufferString(`{"server":"http://localhost:3000","token":"123"}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultProfile, profiles.Current)
+	assert.Equal(t, []string{DefaultProfile}, profiles.Names())
+
+	values, ok := profiles.Get(DefaultProfile)
+	require.True(t, ok)
+	assert.Equal(t, "http://localhost:3000", values[ServerURL])
+	assert.Equal(t, "123", values[Token])
+}
+
+func TestParseProfiles(t *testing.T) {
+	rawConfig := `{"current_profile":"prod","profiles":{"prod":{"server":"https://prod:3000"},"staging":{"server":"https://staging:3000"}}}`
+	profiles, err := parseProfiles(bytes.NewBufferString(rawConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, "prod", profiles.Resolve(""))
+	assert.Equal(t, "staging", profiles.Resolve("staging"))
+	assert.Equal(t, []string{"prod", "staging"}, profiles.Names())
+}
+
+func TestProfilesManagement(t *testing.T) {
+	profiles := NewProfiles()
+	assert.Equal(t, DefaultProfile, profiles.Resolve(""))
+
+	profiles.Set("prod", map[string]interface{}{ServerURL: "https://prod:3000"})
+	profiles.Set("staging", map[string]interface{}{ServerURL: "https://staging:3000"})
+	assert.Equal(t, "prod", profiles.Current)
+
+	require.NoError(t, profiles.Use("staging"))
+	assert.Equal(t, "staging", profiles.Current)
+	assert.EqualError(t, profiles.Use("unknown"), "unknown profile 'unknown'")
+
+	require.NoError(t, profiles.Rename("staging", "stage"))
+	assert.Equal(t, "stage", profiles.Current)
+	assert.Equal(t, []string{"prod", "stage"}, profiles.Names())
+	assert.EqualError(t, profiles.Rename("stage", "prod"), "profile 'prod' already exists")
+
+	require.NoError(t, profiles.Remove("stage"))
+	assert.Equal(t, "prod", profiles.Current)
+	assert.EqualError(t, profiles.Remove("stage"), "unknown profile 'stage'")
+}
+
+func TestLoadParamsFromProfile(t *testing.T) {
+	const filePath = "configProfiles.json"
+	rawConfig := `{"current_profile":"prod","profiles":{"prod":{"server":"https://prod:3000"},"staging":{"server":"https://staging:3000"}}}`
+	err := ioutil.WriteFile(filePath, []byte(rawConfig), 0600)
+	require.NoError(t, err)
+	defer func() {
+		e := os.Remove(filePath)
+		if e != nil {
+			logrus.Error(e)
+		}
+	}()
+
+	err = os.Setenv(PathForConfigEnvVar, filePath)
+	require.NoError(t, err)
+	defer func() {
+		e := os.Unsetenv(PathForConfigEnvVar)
+		if e != nil {
+			logrus.Error(e)
+		}
+	}()
+
+	params := LoadParamsFromFileAndEnv(&pflag.FlagSet{})
+	assert.Equal(t, "https://prod:3000", params.ReadString(ServerURL, ""))
+
+	fl := &pflag.FlagSet{}
+	fl.String(Profile, "", "")
+	err = fl.Parse([]string{"--profile", "staging"})
+	require.NoError(t, err)
+
+	params = LoadParamsFromFileAndEnv(fl)
+	assert.Equal(t, "https://staging:3000", params.ReadString(ServerURL, ""))
+	assert.Equal(t, "staging", params.ReadString(Profile, ""))
+
+	err = os.Setenv(ProfileEnvVar, "staging")
+	require.NoError(t, err)
+	defer func() {
+		e := os.Unsetenv(ProfileEnvVar)
+		if e != nil {
+			logrus.Error(e)
+		}
+	}()
+
+	params = LoadParamsFromFileAndEnv(&pflag.FlagSet{})
+	assert.Equal(t, "https://staging:3000", params.ReadString(ServerURL, ""))
+}
+
+func TestWriteAndDeleteProfileConfig(t *testing.T) {
+	const filePath = "configProfilesToWrite.json"
+	err := ioutil.WriteFile(filePath, []byte(`{"server":"https://old:3000","token":"old"}`), 0600)
+	require.NoError(t, err)
+	defer func() {
+		if _, e := os.Stat(filePath); e == nil {
+			e = os.Remove(filePath)
+			if e != nil {
+				logrus.Error(e)
+			}
+		}
+	}()
+
+	err = os.Setenv(PathForConfigEnvVar, filePath)
+	require.NoError(t, err)
+	defer func() {
+		e := os.Unsetenv(PathForConfigEnvVar)
+		if e != nil {
+			logrus.Error(e)
+		}
+	}()
+
+	err = WriteConfig(FromValues(map[string]string{
+		ServerURL: "https://staging:3000",
+		Token:     "456",
+		Profile:   "staging",
+	}))
+	require.NoError(t, err)
+
+	fileContents, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`{"current_profile":"default","profiles":{"default":{"server":"https://old:3000","token":"old"},"staging":{"server":"https://staging:3000","token":"456"}}}`+"\n",
+		string(fileContents),
+	)
+
+	err = DeleteConfig(FromValues(map[string]string{}))
+	require.NoError(t, err)
+
+	fileContents, err = ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`{"current_profile":"staging","profiles":{"staging":{"server":"https://staging:3000","token":"456"}}}`+"\n",
+		string(fileContents),
+	)
+
+	err = DeleteConfig(FromValues(map[string]string{Profile: "staging"}))
+	require.NoError(t, err)
+	assert.NoFileExists(t, filePath)
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	// SecretStoreFlag is the parameter to select the secret store, the selected one is kept in the profile by SecretStoreKey
+	SecretStoreFlag      = "secret-store"
+	SecretStoreKey       = "secret_store"
+	SecretID             = "secret_id"
+	SecretStorePlain     = "plain"
+	SecretStoreKeyring   = "keyring"
+	SecretStoreEncrypted = "encrypted"
+	secretIDLength       = 16
+)
+
+// secretKeys are config values which are kept in the secret store of the profile
+var secretKeys = []string{Token, APIToken}
+
+// SecretStore keeps secret config values of a profile, e.g. the auth token, outside of the config file,
+// profile secrets are identified by the secret id stored in the profile values
+type SecretStore interface {
+	// Load adds the stored secrets to the profile values
+	Load(values map[string]interface{}) error
+	// Save moves secrets from the profile values to the store
+	Save(values map[string]interface{}) error
+	// Delete removes all stored secrets of the profile
+	Delete(values map[string]interface{}) error
+}
+
+// SecretStoreNames gives names of the supported secret stores
+func SecretStoreNames() []string {
+	return []string{SecretStorePlain, SecretStoreKeyring, SecretStoreEncrypted}
+}
+
+// ValidateSecretStore can be used as a validation function of the secret store parameter,
+// it fails if the store is not available, e.g. secret-tool of the keyring is not installed
+func ValidateSecretStore(fieldName string, val interface{}) error {
+	name := fmt.Sprint(val)
+	if name == "" {
+		return nil
+	}
+
+	if name == SecretStoreKeyring {
+		_, err := newKeyringSecretStore()
+		return err
+	}
+
+	for _, knownName := range SecretStoreNames() {
+		if name == knownName {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"unknown %s '%s', supported values are %s",
+		fieldName,
+		name,
+		strings.Join(SecretStoreNames(), ", "),
+	)
+}
+
+// NewSecretStore creates the secret store by its name, the plain store is used if the name is empty
+func NewSecretStore(name string) (SecretStore, error) {
+	switch name {
+	case "", SecretStorePlain:
+		return &plainSecretStore{}, nil
+	case SecretStoreKeyring:
+		return newKeyringSecretStore()
+	case SecretStoreEncrypted:
+		return newEncryptedFileSecretStore(), nil
+	}
+
+	return nil, ValidateSecretStore(SecretStoreFlag, name)
+}
+
+// NewProfileSecretStore creates the secret store which is selected in the profile values
+func NewProfileSecretStore(values map[string]interface{}) (SecretStore, error) {
+	name, _ := values[SecretStoreKey].(string)
+	return NewSecretStore(name)
+}
+
+// DeleteSecrets removes stored secrets of the profile from its secret store
+func DeleteSecrets(values map[string]interface{}) error {
+	store, err := NewProfileSecretStore(values)
+	if err != nil {
+		return err
+	}
+
+	return store.Delete(values)
+}
+
+// plainSecretStore keeps secrets in the config file together with other profile values
+type plainSecretStore struct{}
+
+func (pss *plainSecretStore) Load(values map[string]interface{}) error {
+	return nil
+}
+
+func (pss *plainSecretStore) Save(values map[string]interface{}) error {
+	return nil
+}
+
+func (pss *plainSecretStore) Delete(values map[string]interface{}) error {
+	return nil
+}
+
+func readSecretID(values map[string]interface{}) string {
+	secretID, _ := values[SecretID].(string)
+	return secretID
+}
+
+// ensureSecretID gives the secret id of the profile, a random one is generated if the profile has no secret id
+func ensureSecretID(values map[string]interface{}) (string, error) {
+	secretID := readSecretID(values)
+	if secretID != "" {
+		return secretID, nil
+	}
+
+	randomBytes := make([]byte, secretIDLength)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+
+	secretID = hex.EncodeToString(randomBytes)
+	values[SecretID] = secretID
+
+	return secretID, nil
+}
@@ -0,0 +1,260 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/breathbath/go_utils/v2/pkg/env"
+	"github.com/breathbath/go_utils/v2/pkg/fs"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/term"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+)
+
+const (
+	secretsFileName        = "secrets.enc"
+	secretsKeyIterations   = 100000
+	secretsKeyLength       = 32
+	secretsSaltLength      = 16
+	secretsFilePermissions = 0600
+)
+
+// PassphraseProvider gives the passphrase to encrypt and decrypt the secrets file,
+// isNew tells that the secrets file is about to be created with the passphrase
+type PassphraseProvider func(isNew bool) (string, error)
+
+type encryptedSecretsFile struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+	Data  []byte `json:"data"`
+}
+
+// encryptedFileSecretStore keeps secrets of all profiles in a file next to the config file,
+// the file is encrypted with AES-GCM and a key derived from the passphrase
+type encryptedFileSecretStore struct {
+	Path               string
+	PassphraseProvider PassphraseProvider
+}
+
+func newEncryptedFileSecretStore() *encryptedFileSecretStore {
+	return &encryptedFileSecretStore{
+		Path:               filepath.Join(filepath.Dir(getConfigLocation()), secretsFileName),
+		PassphraseProvider: readSecretsPassphrase,
+	}
+}
+
+func (efs *encryptedFileSecretStore) Load(values map[string]interface{}) error {
+	secretID := readSecretID(values)
+	if secretID == "" {
+		return nil
+	}
+
+	secrets, err := efs.read()
+	if err != nil {
+		return err
+	}
+
+	for key, secret := range secrets[secretID] {
+		values[key] = secret
+	}
+
+	return nil
+}
+
+func (efs *encryptedFileSecretStore) Save(values map[string]interface{}) error {
+	secretID, err := ensureSecretID(values)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := efs.read()
+	if err != nil {
+		return err
+	}
+
+	profileSecrets := map[string]string{}
+	for _, key := range secretKeys {
+		secret, _ := values[key].(string)
+		delete(values, key)
+		if secret != "" {
+			profileSecrets[key] = secret
+		}
+	}
+	secrets[secretID] = profileSecrets
+
+	return efs.write(secrets)
+}
+
+func (efs *encryptedFileSecretStore) Delete(values map[string]interface{}) error {
+	secretID := readSecretID(values)
+	if secretID == "" || !fs.FileExists(efs.Path) {
+		return nil
+	}
+
+	secrets, err := efs.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := secrets[secretID]; !ok {
+		return nil
+	}
+	delete(secrets, secretID)
+
+	return efs.write(secrets)
+}
+
+// read gives secrets of all profiles by their secret ids
+func (efs *encryptedFileSecretStore) read() (map[string]map[string]string, error) {
+	secrets := map[string]map[string]string{}
+	if !fs.FileExists(efs.Path) {
+		return secrets, nil
+	}
+
+	rawFile, err := ioutil.ReadFile(efs.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	secretsFile := &encryptedSecretsFile{}
+	err = json.Unmarshal(rawFile, secretsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the secrets file %s: %v", efs.Path, err)
+	}
+
+	gcm, err := efs.buildCipher(secretsFile.Salt, false)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := gcm.Open(nil, secretsFile.Nonce, secretsFile.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the secrets file %s, the passphrase might be wrong", efs.Path)
+	}
+
+	err = json.Unmarshal(data, &secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the secrets file %s: %v", efs.Path, err)
+	}
+
+	return secrets, nil
+}
+
+func (efs *encryptedFileSecretStore) write(secrets map[string]map[string]string) error {
+	data, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	secretsFile := &encryptedSecretsFile{
+		Salt: make([]byte, secretsSaltLength),
+	}
+	_, err = rand.Read(secretsFile.Salt)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := efs.buildCipher(secretsFile.Salt, !fs.FileExists(efs.Path))
+	if err != nil {
+		return err
+	}
+
+	secretsFile.Nonce = make([]byte, gcm.NonceSize())
+	_, err = rand.Read(secretsFile.Nonce)
+	if err != nil {
+		return err
+	}
+	secretsFile.Data = gcm.Seal(nil, secretsFile.Nonce, data, nil)
+
+	rawFile, err := json.Marshal(secretsFile)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(efs.Path), 0755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(efs.Path, rawFile, secretsFilePermissions)
+}
+
+func (efs *encryptedFileSecretStore) buildCipher(salt []byte, isNew bool) (cipher.AEAD, error) {
+	passphrase, err := efs.PassphraseProvider(isNew)
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("empty passphrase of the secrets file is not allowed")
+	}
+
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, secretsKeyIterations, secretsKeyLength, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+var passphraseCache = struct {
+	sync.Mutex
+	passphrase string
+}{}
+
+// readSecretsPassphrase reads the passphrase from env or asks for it once if the cli runs in a terminal,
+// the passphrase of a new secrets file is asked twice to avoid typos
+func readSecretsPassphrase(isNew bool) (string, error) {
+	passphrase := env.ReadEnv(SecretsPassphraseEnvVar, "")
+	if passphrase != "" {
+		return passphrase, nil
+	}
+
+	passphraseCache.Lock()
+	defer passphraseCache.Unlock()
+	if passphraseCache.passphrase != "" {
+		return passphraseCache.passphrase, nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("no passphrase provided for the encrypted secret store, please set %s env variable", SecretsPassphraseEnvVar)
+	}
+
+	passphrase, err := promptPassphrase("Enter the passphrase of the secrets file: ")
+	if err != nil {
+		return "", err
+	}
+
+	if isNew {
+		confirmedPassphrase, err := promptPassphrase("Repeat the passphrase of the secrets file: ")
+		if err != nil {
+			return "", err
+		}
+		if confirmedPassphrase != passphrase {
+			return "", fmt.Errorf("the passphrases don't match")
+		}
+	}
+
+	passphraseCache.passphrase = passphrase
+
+	return passphraseCache.passphrase, nil
+}
+
+func promptPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	passphraseBytes, err := utils.ReadPassword()
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(passphraseBytes), nil
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	secretToolCommand     = "secret-tool"
+	secretToolApplication = "rportcli"
+)
+
+// errSecretNotFound is returned by the command runner if the looked up secret doesn't exist
+var errSecretNotFound = errors.New("secret not found")
+
+// secretCommandRunner runs a command with the given input and returns its output
+type secretCommandRunner func(input string, args ...string) (output string, err error)
+
+// secretToolSecretStore keeps secrets in the Secret Service (e.g. GNOME Keyring or KWallet) over D-Bus
+// with the secret-tool command of libsecret
+type secretToolSecretStore struct {
+	run secretCommandRunner
+}
+
+func (sts *secretToolSecretStore) Load(values map[string]interface{}) error {
+	secretID := readSecretID(values)
+	if secretID == "" {
+		return nil
+	}
+
+	for _, key := range secretKeys {
+		secret, err := sts.run("", sts.args("lookup", secretID, key)...)
+		if errors.Is(err, errSecretNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s from the keyring: %v", key, err)
+		}
+		if secret != "" {
+			values[key] = secret
+		}
+	}
+
+	return nil
+}
+
+func (sts *secretToolSecretStore) Save(values map[string]interface{}) error {
+	secretID, err := ensureSecretID(values)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range secretKeys {
+		secret, _ := values[key].(string)
+		delete(values, key)
+		if secret == "" {
+			err = sts.clear(secretID, key)
+		} else {
+			args := append([]string{"store", "--label", fmt.Sprintf("rportcli %s %s", key, secretID)}, sts.args("", secretID, key)...)
+			_, err = sts.run(secret, args...)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to store %s in the keyring: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+func (sts *secretToolSecretStore) Delete(values map[string]interface{}) error {
+	secretID := readSecretID(values)
+	if secretID == "" {
+		return nil
+	}
+
+	for _, key := range secretKeys {
+		err := sts.clear(secretID, key)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s from the keyring: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+// clear removes the secret if it exists, as secret-tool clear fails without error output if nothing is removed
+func (sts *secretToolSecretStore) clear(secretID, key string) error {
+	_, err := sts.run("", sts.args("lookup", secretID, key)...)
+	if errors.Is(err, errSecretNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = sts.run("", sts.args("clear", secretID, key)...)
+
+	return err
+}
+
+// args builds the command arguments with the attributes identifying the secret
+func (sts *secretToolSecretStore) args(action, secretID, key string) []string {
+	args := []string{}
+	if action != "" {
+		args = append(args, action)
+	}
+
+	return append(args, "application", secretToolApplication, SecretID, secretID, "key", key)
+}
+
+func runSecretTool(input string, args ...string) (string, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command(secretToolCommand, args...)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	// secret-tool lookup exits with a non zero code and without error output if the secret is not found,
+	// failures of other actions are always errors
+	if errors.As(err, &exitErr) && stderr.Len() == 0 && len(args) > 0 && args[0] == "lookup" {
+		return "", errSecretNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+func newKeyringSecretStore() (SecretStore, error) {
+	_, err := exec.LookPath(secretToolCommand)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%s is not found, please install it (e.g. libsecret-tools package) to use the %s secret store "+
+				"or use --%s %s instead",
+			secretToolCommand,
+			SecretStoreKeyring,
+			SecretStoreFlag,
+			SecretStoreEncrypted,
+		)
+	}
+
+	return &secretToolSecretStore{run: runSecretTool}, nil
+}
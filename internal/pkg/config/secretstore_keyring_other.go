@@ -0,0 +1,19 @@
+//go:build !linux
+// +build !linux
+
+package config
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func newKeyringSecretStore() (SecretStore, error) {
+	return nil, fmt.Errorf(
+		"%s secret store is not supported on %s, please use --%s %s instead",
+		SecretStoreKeyring,
+		runtime.GOOS,
+		SecretStoreFlag,
+		SecretStoreEncrypted,
+	)
+}
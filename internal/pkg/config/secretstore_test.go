@@ -0,0 +1,212 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedFileSecretStore(t *testing.T) {
+	passphrase := "some passphrase"
+	isNewGiven := []bool{}
+	store := &encryptedFileSecretStore{
+		Path: filepath.Join(t.TempDir(), "secrets.enc"),
+		PassphraseProvider: func(isNew bool) (string, error) {
+			isNewGiven = append(isNewGiven, isNew)
+			return passphrase, nil
+		},
+	}
+
+	values := map[string]interface{}{
+		ServerURL: "https://prod:3000",
+		Token:     "secret token",
+	}
+	err := store.Save(values)
+	require.NoError(t, err)
+
+	assert.NotContains(t, values, Token)
+	assert.NotEmpty(t, values[SecretID])
+	assert.Equal(t, []bool{true}, isNewGiven)
+
+	rawFile, err := ioutil.ReadFile(store.Path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(rawFile), "secret token")
+
+	loadedValues := map[string]interface{}{SecretID: values[SecretID]}
+	err = store.Load(loadedValues)
+	require.NoError(t, err)
+	assert.Equal(t, "secret token", loadedValues[Token])
+
+	passphrase = "wrong passphrase"
+	err = store.Load(map[string]interface{}{SecretID: values[SecretID]})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the passphrase might be wrong")
+
+	passphrase = "some passphrase"
+	err = store.Delete(values)
+	require.NoError(t, err)
+
+	loadedValues = map[string]interface{}{SecretID: values[SecretID]}
+	err = store.Load(loadedValues)
+	require.NoError(t, err)
+	assert.NotContains(t, loadedValues, Token)
+}
+
+func TestSecretToolSecretStore(t *testing.T) {
+	keyring := map[string]string{}
+	store := &secretToolSecretStore{
+		run: func(input string, args ...string) (string, error) {
+			attributes := strings.Join(args[len(args)-6:], " ")
+			switch args[0] {
+			case "store":
+				keyring[attributes] = input
+			case "lookup":
+				secret, ok := keyring[attributes]
+				if !ok {
+					return "", errSecretNotFound
+				}
+				return secret, nil
+			case "clear":
+				delete(keyring, attributes)
+			default:
+				return "", fmt.Errorf("unexpected args %v", args)
+			}
+			return "", nil
+		},
+	}
+
+	values := map[string]interface{}{
+		ServerURL: "https://prod:3000",
+		Token:     "secret token",
+		SecretID:  "id1",
+	}
+	err := store.Save(values)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{ServerURL: "https://prod:3000", SecretID: "id1"}, values)
+	assert.Equal(t, map[string]string{"application rportcli secret_id id1 key token": "secret token"}, keyring)
+
+	err = store.Load(values)
+	require.NoError(t, err)
+	assert.Equal(t, "secret token", values[Token])
+
+	err = store.Delete(values)
+	require.NoError(t, err)
+	assert.Empty(t, keyring)
+
+	values = map[string]interface{}{SecretID: "id1"}
+	err = store.Load(values)
+	require.NoError(t, err)
+	assert.NotContains(t, values, Token)
+}
+
+func TestSecretToolSecretStoreErrors(t *testing.T) {
+	store := &secretToolSecretStore{
+		run: func(input string, args ...string) (string, error) {
+			return "", errors.New("exit status 1")
+		},
+	}
+
+	err := store.Save(map[string]interface{}{Token: "secret token", SecretID: "id1"})
+	assert.EqualError(t, err, "failed to store token in the keyring: exit status 1")
+
+	err = store.Delete(map[string]interface{}{SecretID: "id1"})
+	assert.EqualError(t, err, "failed to delete token from the keyring: exit status 1")
+
+	err = store.Load(map[string]interface{}{SecretID: "id1"})
+	assert.EqualError(t, err, "failed to read token from the keyring: exit status 1")
+}
+
+func TestNewSecretStore(t *testing.T) {
+	store, err := NewSecretStore("")
+	require.NoError(t, err)
+	assert.IsType(t, &plainSecretStore{}, store)
+
+	_, err = NewSecretStore("vault")
+	assert.EqualError(t, err, "unknown secret-store 'vault', supported values are plain, keyring, encrypted")
+}
+
+func TestWriteConfigWithEncryptedSecretStore(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	for key, value := range map[string]string{
+		PathForConfigEnvVar:     configPath,
+		SecretsPassphraseEnvVar: "some passphrase",
+	} {
+		err := os.Setenv(key, value)
+		require.NoError(t, err)
+		envKey := key
+		defer func() {
+			e := os.Unsetenv(envKey)
+			if e != nil {
+				logrus.Error(e)
+			}
+		}()
+	}
+
+	err := WriteConfig(FromValues(map[string]string{
+		ServerURL:       "https://prod:3000",
+		Token:           "secret token",
+		SecretStoreFlag: SecretStoreEncrypted,
+	}))
+	require.NoError(t, err)
+
+	rawConfig, err := ioutil.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(rawConfig), "secret token")
+	assert.Contains(t, string(rawConfig), `"secret_store":"encrypted"`)
+
+	params := LoadParamsFromFileAndEnv(&pflag.FlagSet{})
+	assert.Equal(t, "secret token", params.ReadString(Token, ""))
+
+	err = WriteConfig(FromValues(map[string]string{
+		ServerURL: "https://prod:3000",
+		Token:     "new token",
+	}))
+	require.NoError(t, err)
+
+	params = LoadParamsFromFileAndEnv(&pflag.FlagSet{})
+	assert.Equal(t, "new token", params.ReadString(Token, ""))
+	assert.Equal(t, SecretStoreEncrypted, params.ReadString(SecretStoreKey, ""))
+
+	err = WriteConfig(FromValues(map[string]string{
+		ServerURL:       "https://prod:3000",
+		Token:           "plain token",
+		SecretStoreFlag: SecretStorePlain,
+	}))
+	require.NoError(t, err)
+
+	rawConfig, err = ioutil.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`{"current_profile":"default","profiles":{"default":{"server":"https://prod:3000","token":"plain token"}}}`+"\n",
+		string(rawConfig),
+	)
+}
+
+func TestValidateSecretStoreWithoutSecretTool(t *testing.T) {
+	path := os.Getenv("PATH")
+	err := os.Setenv("PATH", t.TempDir())
+	require.NoError(t, err)
+	defer func() {
+		e := os.Setenv("PATH", path)
+		if e != nil {
+			logrus.Error(e)
+		}
+	}()
+
+	err = ValidateSecretStore(SecretStoreFlag, SecretStoreKeyring)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use --secret-store encrypted instead")
+
+	assert.NoError(t, ValidateSecretStore(SecretStoreFlag, SecretStoreEncrypted))
+}
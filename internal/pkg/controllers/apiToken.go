@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+const (
+	APITokenName      = "name"
+	APITokenScope     = "scope"
+	APITokenExpiresAt = "expires-at"
+)
+
+type APITokenAPI interface {
+	APITokens(ctx context.Context) (*api.APITokensResponse, error)
+	CreateAPIToken(ctx context.Context, token *models.APIToken) (*api.APITokenResponse, error)
+	RevokeAPIToken(ctx context.Context, prefix string) error
+}
+
+type APITokenRenderer interface {
+	RenderAPITokens(tokens []*models.APIToken) error
+	RenderAPIToken(token *models.APIToken) error
+	RenderStatus(s output.KvProvider) error
+}
+
+type APITokenController struct {
+	Rport            APITokenAPI
+	APITokenRenderer APITokenRenderer
+	// Now gives the time to calculate the expiry date when it's provided as duration
+	Now func() time.Time
+}
+
+func (atc *APITokenController) List(ctx context.Context) error {
+	tokensResp, err := atc.Rport.APITokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	return atc.APITokenRenderer.RenderAPITokens(tokensResp.Data)
+}
+
+// Create creates an api token and renders it, since the server doesn't give the token value later
+func (atc *APITokenController) Create(ctx context.Context, params *options.ParameterBag) error {
+	scope := params.ReadString(APITokenScope, models.APITokenScopeReadWrite)
+	if scope != models.APITokenScopeRead && scope != models.APITokenScopeReadWrite {
+		return fmt.Errorf(
+			"invalid %s '%s', supported values are %s, %s",
+			APITokenScope,
+			scope,
+			models.APITokenScopeRead,
+			models.APITokenScopeReadWrite,
+		)
+	}
+
+	expiresAt, err := atc.parseExpiresAt(params.ReadString(APITokenExpiresAt, ""))
+	if err != nil {
+		return err
+	}
+
+	tokenResp, err := atc.Rport.CreateAPIToken(ctx, &models.APIToken{
+		Name:      params.ReadString(APITokenName, ""),
+		Scope:     scope,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if tokenResp.Data == nil {
+		return fmt.Errorf("no api token received from rport")
+	}
+
+	return atc.APITokenRenderer.RenderAPIToken(tokenResp.Data)
+}
+
+func (atc *APITokenController) Revoke(ctx context.Context, prefix string) error {
+	err := atc.Rport.RevokeAPIToken(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	return atc.APITokenRenderer.RenderStatus(&models.OperationStatus{
+		Status: fmt.Sprintf("API token '%s' revoked", prefix),
+	})
+}
+
+// parseExpiresAt accepts a date in RFC3339 format or a duration from now, e.g. 720h
+func (atc *APITokenController) parseExpiresAt(expiresAtStr string) (*time.Time, error) {
+	if expiresAtStr == "" {
+		return nil, nil
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, expiresAtStr)
+	if err == nil {
+		return &expiresAt, nil
+	}
+
+	validity, err := time.ParseDuration(expiresAtStr)
+	if err != nil || validity <= 0 {
+		return nil, fmt.Errorf(
+			"invalid %s '%s', expected a date like 2006-01-02T15:04:05Z07:00 or a positive duration like 720h",
+			APITokenExpiresAt,
+			expiresAtStr,
+		)
+	}
+
+	now := time.Now
+	if atc.Now != nil {
+		now = atc.Now
+	}
+	expiresAt = now().Add(validity).UTC().Truncate(time.Second)
+
+	return &expiresAt, nil
+}
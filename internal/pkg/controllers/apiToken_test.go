@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+type APITokenAPIMock struct {
+	mock.Mock
+}
+
+func (atam *APITokenAPIMock) APITokens(ctx context.Context) (*api.APITokensResponse, error) {
+	args := atam.Called(ctx)
+
+	return args.Get(0).(*api.APITokensResponse), args.Error(1)
+}
+
+func (atam *APITokenAPIMock) CreateAPIToken(ctx context.Context, token *models.APIToken) (*api.APITokenResponse, error) {
+	args := atam.Called(ctx, token)
+
+	return args.Get(0).(*api.APITokenResponse), args.Error(1)
+}
+
+func (atam *APITokenAPIMock) RevokeAPIToken(ctx context.Context, prefix string) error {
+	args := atam.Called(ctx, prefix)
+
+	return args.Error(0)
+}
+
+type APITokenRendererMock struct {
+	mock.Mock
+}
+
+func (atrm *APITokenRendererMock) RenderAPITokens(tokens []*models.APIToken) error {
+	args := atrm.Called(tokens)
+
+	return args.Error(0)
+}
+
+func (atrm *APITokenRendererMock) RenderAPIToken(token *models.APIToken) error {
+	args := atrm.Called(token)
+
+	return args.Error(0)
+}
+
+func (atrm *APITokenRendererMock) RenderStatus(s output.KvProvider) error {
+	args := atrm.Called(s)
+
+	return args.Error(0)
+}
+
+func TestListAPITokens(t *testing.T) {
+	tokens := []*models.APIToken{{Prefix: "ab12cd34"}, {Prefix: "ef56gh78"}}
+
+	apiMock := &APITokenAPIMock{}
+	apiMock.On("APITokens", mock.Anything).Return(&api.APITokensResponse{Data: tokens}, nil)
+
+	renderMock := &APITokenRendererMock{}
+	renderMock.On("RenderAPITokens", tokens).Return(nil)
+
+	atc := &APITokenController{Rport: apiMock, APITokenRenderer: renderMock}
+
+	err := atc.List(context.Background())
+	require.NoError(t, err)
+	renderMock.AssertExpectations(t)
+}
+
+func TestCreateAPIToken(t *testing.T) {
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	expiresAt := time.Date(2022, 1, 3, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		params        map[string]string
+		expectedToken *models.APIToken
+		expectedError string
+	}{
+		{
+			name:          "defaults",
+			params:        map[string]string{},
+			expectedToken: &models.APIToken{Scope: models.APITokenScopeReadWrite},
+		},
+		{
+			name: "expiry as duration",
+			params: map[string]string{
+				APITokenName:      "ci",
+				APITokenScope:     models.APITokenScopeRead,
+				APITokenExpiresAt: "24h",
+			},
+			expectedToken: &models.APIToken{Name: "ci", Scope: models.APITokenScopeRead, ExpiresAt: &expiresAt},
+		},
+		{
+			name: "expiry as date",
+			params: map[string]string{
+				APITokenExpiresAt: "2022-01-03T03:04:05Z",
+			},
+			expectedToken: &models.APIToken{Scope: models.APITokenScopeReadWrite, ExpiresAt: &expiresAt},
+		},
+		{
+			name: "invalid scope",
+			params: map[string]string{
+				APITokenScope: "admin",
+			},
+			expectedError: "invalid scope 'admin', supported values are read, read+write",
+		},
+		{
+			name: "invalid expiry",
+			params: map[string]string{
+				APITokenExpiresAt: "tomorrow",
+			},
+			expectedError: "invalid expires-at 'tomorrow', expected a date like 2006-01-02T15:04:05Z07:00 " +
+				"or a positive duration like 720h",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			createdToken := &models.APIToken{Prefix: "ab12cd34", Token: "ab12cd34secret"}
+
+			apiMock := &APITokenAPIMock{}
+			apiMock.On("CreateAPIToken", mock.Anything, tc.expectedToken).Return(&api.APITokenResponse{Data: createdToken}, nil)
+
+			renderMock := &APITokenRendererMock{}
+			renderMock.On("RenderAPIToken", createdToken).Return(nil)
+
+			atc := &APITokenController{
+				Rport:            apiMock,
+				APITokenRenderer: renderMock,
+				Now: func() time.Time {
+					return now
+				},
+			}
+
+			err := atc.Create(context.Background(), config.FromValues(tc.params))
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			apiMock.AssertExpectations(t)
+			renderMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeAPIToken(t *testing.T) {
+	apiMock := &APITokenAPIMock{}
+	apiMock.On("RevokeAPIToken", mock.Anything, "ab12cd34").Return(nil)
+
+	renderMock := &APITokenRendererMock{}
+	renderMock.On("RenderStatus", &models.OperationStatus{Status: "API token 'ab12cd34' revoked"}).Return(nil)
+
+	atc := &APITokenController{Rport: apiMock, APITokenRenderer: renderMock}
+
+	err := atc.Revoke(context.Background(), "ab12cd34")
+	require.NoError(t, err)
+	apiMock.AssertExpectations(t)
+	renderMock.AssertExpectations(t)
+}
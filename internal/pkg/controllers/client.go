@@ -2,20 +2,27 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	options "github.com/breathbath/go_utils/v2/pkg/config"
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/client"
 )
 
 const (
 	ClientNameFlag = "name"
+	ClientsSort    = "sort"
+	ClientsLimit   = "limit"
+	ClientsOffset  = "offset"
 )
 
 type ClientRenderer interface {
-	RenderClients(clients []*models.Client) error
+	RenderClients(clients []*models.Client, pagination *models.Pagination) error
+	ClientListFields() ([]string, error)
 	RenderClient(client *models.Client, renderDetails bool) error
 }
 
@@ -25,13 +32,50 @@ type ClientController struct {
 	ClientRenderer ClientRenderer
 }
 
-func (cc *ClientController) Clients(ctx context.Context) error {
-	clResp, err := cc.Rport.Clients(ctx)
+// Clients renders clients matching the filters from params, the clients are sorted and paginated if requested
+func (cc *ClientController) Clients(ctx context.Context, params *options.ParameterBag) error {
+	filters, err := client.ReadFilters(params)
 	if err != nil {
 		return err
 	}
 
-	return cc.ClientRenderer.RenderClients(clResp.Data)
+	listOptions := &api.ClientsListOptions{
+		Filters: filters,
+		Limit:   params.ReadInt(ClientsLimit, 0),
+		Offset:  params.ReadInt(ClientsOffset, 0),
+	}
+	if listOptions.Limit < 0 || listOptions.Offset < 0 {
+		return errors.New("limit and offset cannot be negative")
+	}
+
+	listOptions.Fields, err = cc.ClientRenderer.ClientListFields()
+	if err != nil {
+		return err
+	}
+
+	sortStr := params.ReadString(ClientsSort, "")
+	if sortStr != "" {
+		listOptions.Sort = strings.Split(sortStr, ",")
+	}
+
+	clResp, err := cc.Rport.ListClients(ctx, listOptions)
+	if err != nil {
+		return err
+	}
+
+	var pagination *models.Pagination
+	if listOptions.Limit > 0 || listOptions.Offset > 0 {
+		pagination = &models.Pagination{
+			Offset: listOptions.Offset,
+			Limit:  listOptions.Limit,
+			Total:  listOptions.Offset + len(clResp.Data),
+		}
+		if clResp.Meta != nil {
+			pagination.Total = clResp.Meta.Count
+		}
+	}
+
+	return cc.ClientRenderer.RenderClients(clResp.Data, pagination)
 }
 
 func (cc *ClientController) Client(ctx context.Context, params *options.ParameterBag, id, name string) error {
@@ -20,6 +20,8 @@ import (
 type ClientRendererMock struct {
 	Writer             io.Writer
 	renderDetailsGiven bool
+	paginationGiven    *models.Pagination
+	listFieldsToGive   []string
 }
 
 var clientStub = &models.Client{
@@ -41,7 +43,9 @@ var clientStub = &models.Client{
 	ConnState: "connected",
 }
 
-func (crm *ClientRendererMock) RenderClients(clients []*models.Client) error {
+func (crm *ClientRendererMock) RenderClients(clients []*models.Client, pagination *models.Pagination) error {
+	crm.paginationGiven = pagination
+
 	jsonBytes, err := json.Marshal(clients)
 	if err != nil {
 		return err
@@ -55,6 +59,10 @@ func (crm *ClientRendererMock) RenderClients(clients []*models.Client) error {
 	return nil
 }
 
+func (crm *ClientRendererMock) ClientListFields() ([]string, error) {
+	return crm.listFieldsToGive, nil
+}
+
 func (crm *ClientRendererMock) RenderClient(client *models.Client, renderDetails bool) error {
 	crm.renderDetailsGiven = renderDetails
 
@@ -82,7 +90,7 @@ func TestClientsController(t *testing.T) {
 		ClientRenderer: &ClientRendererMock{Writer: &buf},
 	}
 
-	err := clController.Clients(context.Background())
+	err := clController.Clients(context.Background(), &options.ParameterBag{})
 	assert.NoError(t, err)
 	if err != nil {
 		return
@@ -95,6 +103,34 @@ func TestClientsController(t *testing.T) {
 	)
 }
 
+func TestClientsControllerWithPagination(t *testing.T) {
+	srv := startClientsServer()
+	defer srv.Close()
+
+	cl := api.New(srv.URL, nil)
+	renderer := &ClientRendererMock{Writer: &bytes.Buffer{}}
+	clController := ClientController{
+		Rport:          cl,
+		ClientRenderer: renderer,
+	}
+
+	params := options.New(options.NewMapValuesProvider(map[string]interface{}{
+		ClientsLimit:  "10",
+		ClientsOffset: "20",
+		ClientsSort:   "-name",
+	}))
+	err := clController.Clients(context.Background(), params)
+	assert.NoError(t, err)
+	// the server ignores pagination and gives its only client, so the page is calculated locally
+	assert.Equal(t, &models.Pagination{Offset: 20, Limit: 10, Total: 1}, renderer.paginationGiven)
+
+	params = options.New(options.NewMapValuesProvider(map[string]interface{}{
+		ClientsLimit: "-1",
+	}))
+	err = clController.Clients(context.Background(), params)
+	assert.EqualError(t, err, "limit and offset cannot be negative")
+}
+
 func TestClientFoundByIDController(t *testing.T) {
 	srv := startClientsServer()
 	defer srv.Close()
@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+	io2 "github.com/breathbath/go_utils/v2/pkg/io"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+const (
+	CommandTemplateParam   = "param"
+	CommandTemplateNewName = "new-name"
+	CommandTemplateTag     = "tag"
+)
+
+// CommandTemplatesStore keeps command templates either on the rport server or locally
+type CommandTemplatesStore interface {
+	CommandTemplates(ctx context.Context) ([]*models.CommandTemplate, error)
+	CommandTemplate(ctx context.Context, templateID string) (*models.CommandTemplate, error)
+	CreateCommandTemplate(ctx context.Context, template *models.CommandTemplate) (*models.CommandTemplate, error)
+	UpdateCommandTemplate(ctx context.Context, template *models.CommandTemplate) error
+	DeleteCommandTemplate(ctx context.Context, templateID string) error
+}
+
+type CommandTemplateRenderer interface {
+	RenderCommandTemplates(templates []*models.CommandTemplate) error
+	RenderCommandTemplate(template *models.CommandTemplate) error
+	RenderStatus(s output.KvProvider) error
+}
+
+// CommandTemplatesController manages stored commands with {{param}} placeholders, the templates are kept
+// in the command library of the rport server, or in the local store if the server doesn't support it
+type CommandTemplatesController struct {
+	Rport              CommandTemplatesStore
+	LocalStore         CommandTemplatesStore
+	Renderer           CommandTemplateRenderer
+	CommandsController *CommandsController
+	PromptReader       config.PromptReader
+}
+
+func (ctc *CommandTemplatesController) List(ctx context.Context) error {
+	_, templates, err := ctc.resolveStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	return ctc.Renderer.RenderCommandTemplates(templates)
+}
+
+func (ctc *CommandTemplatesController) Get(ctx context.Context, idOrName string) error {
+	_, template, err := ctc.findTemplate(ctx, idOrName)
+	if err != nil {
+		return err
+	}
+
+	return ctc.Renderer.RenderCommandTemplate(template)
+}
+
+func (ctc *CommandTemplatesController) Create(ctx context.Context, name string, params *options.ParameterBag) error {
+	cmd, err := params.ReadRequiredString(Command)
+	if err != nil {
+		return err
+	}
+
+	store, _, err := ctc.resolveStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	template, err := store.CreateCommandTemplate(ctx, &models.CommandTemplate{
+		Name: name,
+		Cmd:  cmd,
+		Tags: parseScriptTags(params.ReadStrings(CommandTemplateTag)),
+	})
+	if err != nil {
+		return err
+	}
+
+	status := fmt.Sprintf("Command template '%s' created", name)
+	if template != nil && template.ID != "" {
+		status += fmt.Sprintf(" with id %s", template.ID)
+	}
+
+	return ctc.Renderer.RenderStatus(&models.OperationStatus{Status: status})
+}
+
+// Update changes only the provided fields, all tags are replaced if at least one tag is provided
+func (ctc *CommandTemplatesController) Update(ctx context.Context, idOrName string, params *options.ParameterBag) error {
+	store, template, err := ctc.findTemplate(ctx, idOrName)
+	if err != nil {
+		return err
+	}
+
+	if newName := params.ReadString(CommandTemplateNewName, ""); newName != "" {
+		template.Name = newName
+	}
+
+	if cmd := params.ReadString(Command, ""); cmd != "" {
+		template.Cmd = cmd
+	}
+
+	tags := params.ReadStrings(CommandTemplateTag)
+	if len(tags) > 0 {
+		template.Tags = parseScriptTags(tags)
+	}
+
+	err = store.UpdateCommandTemplate(ctx, template)
+	if err != nil {
+		return err
+	}
+
+	return ctc.Renderer.RenderStatus(&models.OperationStatus{
+		Status: fmt.Sprintf("Command template '%s' updated", template.Name),
+	})
+}
+
+func (ctc *CommandTemplatesController) Delete(ctx context.Context, idOrName string) error {
+	store, template, err := ctc.findTemplate(ctx, idOrName)
+	if err != nil {
+		return err
+	}
+
+	err = store.DeleteCommandTemplate(ctx, template.ID)
+	if err != nil {
+		return err
+	}
+
+	return ctc.Renderer.RenderStatus(&models.OperationStatus{
+		Status: fmt.Sprintf("Command template '%s' deleted", template.Name),
+	})
+}
+
+// Run fills the placeholders of the template with the provided params, values of missing params are prompted,
+// and executes the resulting command
+func (ctc *CommandTemplatesController) Run(ctx context.Context, idOrName string, params *options.ParameterBag) error {
+	cmd, err := ctc.renderCommand(ctx, idOrName, params)
+	if err != nil {
+		if ctc.CommandsController.ReadWriter != nil {
+			io2.CloseResourceSecure("read writer", ctc.CommandsController.ReadWriter)
+		}
+		return err
+	}
+
+	runParams := options.New(options.NewValuesProviderComposite(
+		options.NewMapValuesProvider(map[string]interface{}{Command: cmd}),
+		params.BaseValuesProvider,
+	))
+
+	return ctc.CommandsController.Start(ctx, runParams)
+}
+
+func (ctc *CommandTemplatesController) renderCommand(ctx context.Context, idOrName string, params *options.ParameterBag) (string, error) {
+	_, template, err := ctc.findTemplate(ctx, idOrName)
+	if err != nil {
+		return "", err
+	}
+
+	values, err := ctc.readParamValues(template, params.ReadStrings(CommandTemplateParam))
+	if err != nil {
+		return "", err
+	}
+
+	cmd, err := template.Render(values)
+	if err != nil {
+		return "", err
+	}
+	logrus.Debugf("command template '%s' is rendered to '%s'", template.Name, cmd)
+
+	return cmd, nil
+}
+
+// readParamValues parses expressions like 'service=nginx' and prompts the values of missing params
+func (ctc *CommandTemplatesController) readParamValues(
+	template *models.CommandTemplate,
+	expressions []string,
+) (map[string]string, error) {
+	templateParams := template.Params()
+	isTemplateParam := make(map[string]bool, len(templateParams))
+	for _, param := range templateParams {
+		isTemplateParam[param] = true
+	}
+
+	values := make(map[string]string, len(templateParams))
+	for _, expr := range expressions {
+		exprParts := strings.SplitN(expr, "=", 2)
+		if len(exprParts) != 2 || exprParts[0] == "" {
+			return nil, fmt.Errorf("invalid param '%s', expected format is key=value", expr)
+		}
+
+		key := strings.TrimSpace(exprParts[0])
+		if !isTemplateParam[key] {
+			return nil, fmt.Errorf(
+				"unknown param '%s', params of command template '%s' are: %s",
+				key,
+				template.Name,
+				strings.Join(templateParams, ", "),
+			)
+		}
+		values[key] = exprParts[1]
+	}
+
+	var missedRequirements []config.ParameterRequirement
+	for _, param := range templateParams {
+		if _, ok := values[param]; ok {
+			continue
+		}
+		missedRequirements = append(missedRequirements, config.ParameterRequirement{
+			Field:    param,
+			Help:     fmt.Sprintf("Enter value of param '%s'", param),
+			Validate: config.RequiredValidate,
+		})
+	}
+
+	if len(missedRequirements) == 0 {
+		return values, nil
+	}
+
+	if ctc.PromptReader == nil {
+		return nil, fmt.Errorf("no value provided for param '%s'", missedRequirements[0].Field)
+	}
+
+	promptedValues := make(map[string]interface{}, len(missedRequirements))
+	err := config.PromptRequiredValues(missedRequirements, promptedValues, ctc.PromptReader)
+	if err != nil {
+		return nil, err
+	}
+
+	for param, value := range promptedValues {
+		values[param] = fmt.Sprint(value)
+	}
+
+	return values, nil
+}
+
+// resolveStore gives the command library of the server together with its templates,
+// or the local store if the server doesn't support it
+func (ctc *CommandTemplatesController) resolveStore(ctx context.Context) (CommandTemplatesStore, []*models.CommandTemplate, error) {
+	templates, err := ctc.Rport.CommandTemplates(ctx)
+	if err == nil {
+		return ctc.Rport, templates, nil
+	}
+
+	if !errors.Is(err, api.ErrCommandLibraryNotSupported) || ctc.LocalStore == nil {
+		return nil, nil, err
+	}
+
+	logrus.Debugf("%v, local command templates are used", err)
+	templates, err = ctc.LocalStore.CommandTemplates(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ctc.LocalStore, templates, nil
+}
+
+// findTemplate gives a template by id or by name together with its store, as names are not unique,
+// a name of multiple templates is rejected
+func (ctc *CommandTemplatesController) findTemplate(
+	ctx context.Context,
+	idOrName string,
+) (CommandTemplatesStore, *models.CommandTemplate, error) {
+	store, templates, err := ctc.resolveStore(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([]namedEntry, 0, len(templates))
+	for _, template := range templates {
+		entries = append(entries, namedEntry{ID: template.ID, Name: template.Name})
+	}
+
+	templateID, err := findIDByIDOrName(entries, idOrName, "command template")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template, err := store.CommandTemplate(ctx, templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if template == nil {
+		return nil, nil, fmt.Errorf("command template '%s' not found", idOrName)
+	}
+
+	return store, template, nil
+}
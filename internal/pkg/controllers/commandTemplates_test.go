@@ -0,0 +1,269 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+type CommandTemplatesStoreMock struct {
+	mock.Mock
+}
+
+func (ctsm *CommandTemplatesStoreMock) CommandTemplates(ctx context.Context) ([]*models.CommandTemplate, error) {
+	args := ctsm.Called(ctx)
+
+	templates, _ := args.Get(0).([]*models.CommandTemplate)
+	return templates, args.Error(1)
+}
+
+func (ctsm *CommandTemplatesStoreMock) CommandTemplate(ctx context.Context, templateID string) (*models.CommandTemplate, error) {
+	args := ctsm.Called(ctx, templateID)
+
+	template, _ := args.Get(0).(*models.CommandTemplate)
+	return template, args.Error(1)
+}
+
+func (ctsm *CommandTemplatesStoreMock) CreateCommandTemplate(
+	ctx context.Context,
+	template *models.CommandTemplate,
+) (*models.CommandTemplate, error) {
+	args := ctsm.Called(ctx, template)
+
+	created, _ := args.Get(0).(*models.CommandTemplate)
+	return created, args.Error(1)
+}
+
+func (ctsm *CommandTemplatesStoreMock) UpdateCommandTemplate(ctx context.Context, template *models.CommandTemplate) error {
+	args := ctsm.Called(ctx, template)
+
+	return args.Error(0)
+}
+
+func (ctsm *CommandTemplatesStoreMock) DeleteCommandTemplate(ctx context.Context, templateID string) error {
+	args := ctsm.Called(ctx, templateID)
+
+	return args.Error(0)
+}
+
+type CommandTemplateRendererMock struct {
+	mock.Mock
+}
+
+func (ctrm *CommandTemplateRendererMock) RenderCommandTemplates(templates []*models.CommandTemplate) error {
+	args := ctrm.Called(templates)
+
+	return args.Error(0)
+}
+
+func (ctrm *CommandTemplateRendererMock) RenderCommandTemplate(template *models.CommandTemplate) error {
+	args := ctrm.Called(template)
+
+	return args.Error(0)
+}
+
+func (ctrm *CommandTemplateRendererMock) RenderStatus(s output.KvProvider) error {
+	args := ctrm.Called(s)
+
+	return args.Error(0)
+}
+
+var restartTemplate = &models.CommandTemplate{ID: "1", Name: "restart", Cmd: "systemctl restart {{service}} {{ flags }}"}
+
+func newCommandTemplatesStoreMock() *CommandTemplatesStoreMock {
+	storeMock := &CommandTemplatesStoreMock{}
+	storeMock.On("CommandTemplates", mock.Anything).Return([]*models.CommandTemplate{restartTemplate}, nil)
+	storeMock.On("CommandTemplate", mock.Anything, "1").Return(restartTemplate, nil)
+
+	return storeMock
+}
+
+func TestListCommandTemplatesFromServer(t *testing.T) {
+	rportMock := newCommandTemplatesStoreMock()
+	localMock := &CommandTemplatesStoreMock{}
+
+	renderMock := &CommandTemplateRendererMock{}
+	renderMock.On("RenderCommandTemplates", mock.Anything).Return(nil)
+
+	ctc := &CommandTemplatesController{Rport: rportMock, LocalStore: localMock, Renderer: renderMock}
+
+	err := ctc.List(context.Background())
+	require.NoError(t, err)
+
+	localMock.AssertNotCalled(t, "CommandTemplates", mock.Anything)
+	renderMock.AssertCalled(t, "RenderCommandTemplates", mock.MatchedBy(func(templates []*models.CommandTemplate) bool {
+		return len(templates) == 1
+	}))
+}
+
+func TestCommandTemplatesFallbackToLocalStore(t *testing.T) {
+	rportMock := &CommandTemplatesStoreMock{}
+	rportMock.On("CommandTemplates", mock.Anything).Return(nil, api.ErrCommandLibraryNotSupported)
+
+	localMock := &CommandTemplatesStoreMock{}
+	localMock.On("CommandTemplates", mock.Anything).Return([]*models.CommandTemplate{}, nil)
+	localMock.On("CreateCommandTemplate", mock.Anything, mock.Anything).Return(&models.CommandTemplate{ID: "a1b2c3d4"}, nil)
+
+	renderMock := &CommandTemplateRendererMock{}
+	renderMock.On("RenderStatus", mock.Anything).Return(nil)
+
+	ctc := &CommandTemplatesController{Rport: rportMock, LocalStore: localMock, Renderer: renderMock}
+
+	err := ctc.Create(context.Background(), "uptime", config.FromValues(map[string]string{
+		Command:            "uptime",
+		CommandTemplateTag: "linux",
+	}))
+	require.NoError(t, err)
+
+	rportMock.AssertNotCalled(t, "CreateCommandTemplate", mock.Anything, mock.Anything)
+	localMock.AssertCalled(t, "CreateCommandTemplate", mock.Anything, &models.CommandTemplate{
+		Name: "uptime",
+		Cmd:  "uptime",
+		Tags: []string{"linux"},
+	})
+	renderMock.AssertCalled(t, "RenderStatus", &models.OperationStatus{Status: "Command template 'uptime' created with id a1b2c3d4"})
+}
+
+func TestCommandTemplatesNoFallbackOnOtherErrors(t *testing.T) {
+	rportMock := &CommandTemplatesStoreMock{}
+	rportMock.On("CommandTemplates", mock.Anything).Return(nil, errors.New("unauthorized"))
+
+	localMock := &CommandTemplatesStoreMock{}
+
+	ctc := &CommandTemplatesController{Rport: rportMock, LocalStore: localMock, Renderer: &CommandTemplateRendererMock{}}
+
+	err := ctc.List(context.Background())
+	assert.EqualError(t, err, "unauthorized")
+	localMock.AssertNotCalled(t, "CommandTemplates", mock.Anything)
+}
+
+func TestRunCommandTemplate(t *testing.T) {
+	testCases := []struct {
+		name            string
+		params          []string
+		promptedValues  []string
+		expectedCommand string
+		expectedPrompts int
+	}{
+		{
+			name:            "all params provided",
+			params:          []string{"service=nginx", "flags=--no-block"},
+			expectedCommand: "systemctl restart nginx --no-block",
+		},
+		{
+			name:            "value with equal sign",
+			params:          []string{"service=nginx", "flags=--signal=HUP"},
+			expectedCommand: "systemctl restart nginx --signal=HUP",
+		},
+		{
+			name:            "missing param is prompted",
+			params:          []string{"service=nginx"},
+			promptedValues:  []string{"--quiet"},
+			expectedCommand: "systemctl restart nginx --quiet",
+			expectedPrompts: 1,
+		},
+		{
+			name:            "empty prompted value is prompted again",
+			promptedValues:  []string{"nginx", "", "--quiet"},
+			expectedCommand: "systemctl restart nginx --quiet",
+			expectedPrompts: 3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			jobBytes, err := json.Marshal(models.Job{Jid: "123", ClientID: "cl1", Status: models.JobStatusSuccessful})
+			require.NoError(t, err)
+
+			rw := &ReadWriterMock{
+				itemsToRead: []ReadChunk{{Output: jobBytes}, {Err: io.EOF}},
+			}
+			promptReader := &PromptReaderMock{ReadOutputs: tc.promptedValues}
+
+			ctc := &CommandTemplatesController{
+				Rport: newCommandTemplatesStoreMock(),
+				CommandsController: &CommandsController{
+					ExecutionHelper: &ExecutionHelper{
+						ReadWriter:  rw,
+						JobRenderer: &JobRendererMock{},
+					},
+				},
+				PromptReader: promptReader,
+			}
+
+			params := config.FromValues(map[string]string{
+				ClientIDs: "cl1",
+				Timeout:   "10",
+			})
+			params.BaseValuesProvider = options.NewValuesProviderComposite(
+				options.NewMapValuesProvider(map[string]interface{}{CommandTemplateParam: tc.params}),
+				params.BaseValuesProvider,
+			)
+
+			err = ctc.Run(context.Background(), "restart", params)
+			require.NoError(t, err)
+
+			require.Len(t, rw.writtenItems, 1)
+			var wsCmd models.WsScriptCommand
+			require.NoError(t, json.Unmarshal([]byte(rw.writtenItems[0]), &wsCmd))
+			assert.Equal(t, tc.expectedCommand, wsCmd.Command)
+			assert.Equal(t, []string{"cl1"}, wsCmd.ClientIDs)
+			assert.Equal(t, tc.expectedPrompts, promptReader.ReadCount)
+			assert.True(t, rw.isClosed)
+		})
+	}
+}
+
+func TestRunCommandTemplateWithInvalidParams(t *testing.T) {
+	testCases := []struct {
+		name          string
+		param         string
+		expectedError string
+	}{
+		{
+			name:          "unknown param",
+			param:         "port=80",
+			expectedError: "unknown param 'port', params of command template 'restart' are: service, flags",
+		},
+		{
+			name:          "no value",
+			param:         "service",
+			expectedError: "invalid param 'service', expected format is key=value",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			rw := &ReadWriterMock{}
+			ctc := &CommandTemplatesController{
+				Rport: newCommandTemplatesStoreMock(),
+				CommandsController: &CommandsController{
+					ExecutionHelper: &ExecutionHelper{ReadWriter: rw},
+				},
+				PromptReader: &PromptReaderMock{},
+			}
+
+			err := ctc.Run(context.Background(), "restart", config.FromValues(map[string]string{
+				ClientIDs:            "cl1",
+				CommandTemplateParam: tc.param,
+			}))
+			assert.EqualError(t, err, tc.expectedError)
+			assert.Len(t, rw.writtenItems, 0)
+			assert.True(t, rw.isClosed)
+		})
+	}
+}
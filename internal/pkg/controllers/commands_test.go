@@ -4,15 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"os"
 	"testing"
 	"time"
 
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/client"
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type ReadChunk struct {
@@ -51,8 +57,9 @@ func (rwm *ReadWriterMock) Close() error {
 }
 
 type JobRendererMock struct {
-	jobToRender *models.Job
-	err         error
+	jobToRender        *models.Job
+	jobStartedToRender *models.JobStarted
+	err                error
 }
 
 func (jrm *JobRendererMock) RenderJob(j *models.Job) error {
@@ -60,6 +67,63 @@ func (jrm *JobRendererMock) RenderJob(j *models.Job) error {
 	return jrm.err
 }
 
+func (jrm *JobRendererMock) RenderJobStarted(js *models.JobStarted) error {
+	jrm.jobStartedToRender = js
+	return jrm.err
+}
+
+type FlushingJobRendererMock struct {
+	JobRendererMock
+	renderedJobsCount int
+	flushedJobsCount  int
+}
+
+func (fjrm *FlushingJobRendererMock) RenderJob(j *models.Job) error {
+	fjrm.renderedJobsCount++
+	return fjrm.JobRendererMock.RenderJob(j)
+}
+
+func (fjrm *FlushingJobRendererMock) Flush() error {
+	fjrm.flushedJobsCount = fjrm.renderedJobsCount
+	return nil
+}
+
+type SpinnerMock struct {
+	messages []string
+}
+
+func (sm *SpinnerMock) Start(msg string) {
+	sm.messages = append(sm.messages, "start: "+msg)
+}
+
+func (sm *SpinnerMock) Update(msg string) {
+	sm.messages = append(sm.messages, "update: "+msg)
+}
+
+func (sm *SpinnerMock) StopSuccess(msg string) {
+	sm.messages = append(sm.messages, "success: "+msg)
+}
+
+func (sm *SpinnerMock) StopError(msg string) {
+	sm.messages = append(sm.messages, "error: "+msg)
+}
+
+type JobStarterMock struct {
+	commandGiven *models.WsScriptCommand
+	scriptGiven  *models.WsScriptCommand
+	jidToGive    string
+}
+
+func (jsm *JobStarterMock) StartMultiClientCommand(ctx context.Context, wsCmd *models.WsScriptCommand) (*api.JobStartedResponse, error) {
+	jsm.commandGiven = wsCmd
+	return &api.JobStartedResponse{Data: &models.JobStarted{Jid: jsm.jidToGive}}, nil
+}
+
+func (jsm *JobStarterMock) StartMultiClientScript(ctx context.Context, wsCmd *models.WsScriptCommand) (*api.JobStartedResponse, error) {
+	jsm.scriptGiven = wsCmd
+	return &api.JobStartedResponse{Data: &models.JobStarted{Jid: jsm.jidToGive}}, nil
+}
+
 func TestCommandExecutionByClientIDsSuccess(t *testing.T) {
 	jobResp := models.Job{
 		Jid:         "123",
@@ -231,7 +295,34 @@ func TestInvalidInputForCommand(t *testing.T) {
 		CheckPort:      "1",
 	})
 	err := cc.Start(context.Background(), params)
-	assert.EqualError(t, err, "no client id nor name provided")
+	assert.EqualError(t, err, "no client id nor name nor filter provided")
+}
+
+func TestCommandWithFiltersAndClientIDs(t *testing.T) {
+	cc := &CommandsController{
+		ExecutionHelper: &ExecutionHelper{},
+	}
+	params := options.New(options.NewMapValuesProvider(map[string]interface{}{
+		ClientIDs:         "cl1",
+		client.FilterFlag: []string{"os_family=linux"},
+		Command:           "ls",
+	}))
+	err := cc.Start(context.Background(), params)
+	assert.EqualError(t, err, "client filters cannot be combined with client ids, use client names instead")
+}
+
+func TestCommandWithFiltersMatchingNoClients(t *testing.T) {
+	cc := &CommandsController{
+		ExecutionHelper: &ExecutionHelper{
+			ClientSearch: &ClientSearchMock{},
+		},
+	}
+	params := options.New(options.NewMapValuesProvider(map[string]interface{}{
+		client.FilterFlag: []string{"os_family=linux"},
+		Command:           "ls",
+	}))
+	err := cc.Start(context.Background(), params)
+	assert.EqualError(t, err, "no client matches the provided filters")
 }
 
 func TestCommandExecutionWithInvalidResponse(t *testing.T) {
@@ -283,3 +374,356 @@ func TestCommandExecutionWithInvalidResponse(t *testing.T) {
 	}
 	assert.Contains(t, err.Error(), "some error, code: 500, details: some error detail")
 }
+
+func TestCommandExecutionDetached(t *testing.T) {
+	jr := &JobRendererMock{}
+	js := &JobStarterMock{jidToGive: "multi123"}
+
+	ic := &CommandsController{
+		ExecutionHelper: &ExecutionHelper{
+			JobRenderer: jr,
+			JobStarter:  js,
+		},
+	}
+
+	params := config.FromValues(map[string]string{
+		ClientIDs: "1235,1236",
+		Command:   "sleep 600",
+		Detach:    "1",
+	})
+	err := ic.Start(context.Background(), params)
+	require.NoError(t, err)
+
+	require.NotNil(t, js.commandGiven)
+	assert.Equal(t, []string{"1235", "1236"}, js.commandGiven.ClientIDs)
+	assert.Equal(t, "sleep 600", js.commandGiven.Command)
+	assert.Nil(t, js.scriptGiven)
+	assert.Equal(t, &models.JobStarted{Jid: "multi123"}, jr.jobStartedToRender)
+	assert.Nil(t, jr.jobToRender)
+}
+
+func TestCommandExecutionProgress(t *testing.T) {
+	var itemsToRead []ReadChunk
+	for _, job := range []models.Job{
+		{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful},
+		{Jid: "2", ClientID: "cl3", ClientName: "group client", Status: models.JobStatusRunning},
+		{Jid: "2", ClientID: "cl3", ClientName: "group client", Status: models.JobStatusFailed},
+	} {
+		jobBytes, err := json.Marshal(job)
+		require.NoError(t, err)
+		itemsToRead = append(itemsToRead, ReadChunk{Output: jobBytes})
+	}
+	itemsToRead = append(itemsToRead, ReadChunk{Err: io.EOF})
+
+	spinner := &SpinnerMock{}
+	cc := &CommandsController{
+		ExecutionHelper: &ExecutionHelper{
+			ReadWriter:  &ReadWriterMock{itemsToRead: itemsToRead},
+			JobRenderer: &JobRendererMock{},
+			ClientSearch: &ClientSearchMock{
+				clientsToGive: []*models.Client{
+					{ID: "cl1", Name: "client 1"},
+					{ID: "cl2", Name: "client 2"},
+				},
+			},
+			Spinner: spinner,
+		},
+	}
+
+	params := config.FromValues(map[string]string{
+		ClientNameFlag: "client*",
+		GroupIDs:       "g1",
+		Command:        "ls",
+	})
+	err := cc.Start(context.Background(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"start: waiting for the command to finish [0/2]\nclient 1 (cl1)\tpending\nclient 2 (cl2)\tpending",
+		"update: waiting for the command to finish [1/2]\nclient 1 (cl1)\tsuccess\nclient 2 (cl2)\tpending",
+		"update: waiting for the command to finish [1/3]\nclient 1 (cl1)\tsuccess\nclient 2 (cl2)\tpending\ngroup client (cl3)\trunning",
+		"update: waiting for the command to finish [2/3]\nclient 1 (cl1)\tsuccess\nclient 2 (cl2)\tpending\ngroup client (cl3)\tfailed",
+		"error: command finished [2/3]\nclient 1 (cl1)\tsuccess\nclient 2 (cl2)\tpending\ngroup client (cl3)\tfailed",
+	}, spinner.messages)
+}
+
+type JobsSummaryRendererMock struct {
+	summary *models.JobsSummary
+}
+
+func (jsrm *JobsSummaryRendererMock) RenderJobsSummary(s *models.JobsSummary) error {
+	jsrm.summary = s
+	return nil
+}
+
+func TestCommandExecutionSummary(t *testing.T) {
+	startedAt := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name              string
+		jobs              []models.Job
+		expectedResults   []string
+		expectedDurations []time.Duration
+		expectedDuration  time.Duration
+		expectedError     string
+		expectedExitCode  int
+	}{
+		{
+			name: "all succeeded",
+			jobs: []models.Job{
+				{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful, StartedAt: startedAt, FinishedAt: startedAt.Add(time.Second)},
+				{Jid: "2", ClientID: "cl2", Status: models.JobStatusSuccessful, StartedAt: startedAt, FinishedAt: startedAt.Add(3 * time.Second)},
+				{Jid: "3", ClientID: "cl3", Status: models.JobStatusSuccessful, StartedAt: startedAt.Add(time.Second), FinishedAt: startedAt.Add(5 * time.Second)},
+			},
+			expectedResults:   []string{models.JobResultSucceeded, models.JobResultSucceeded, models.JobResultSucceeded},
+			expectedDurations: []time.Duration{time.Second, 3 * time.Second, 4 * time.Second},
+			expectedDuration:  5 * time.Second,
+		},
+		{
+			name: "partial failure",
+			jobs: []models.Job{
+				{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful, StartedAt: startedAt, FinishedAt: startedAt.Add(time.Second)},
+				{Jid: "2", ClientID: "cl2", Status: models.JobStatusUnknown, StartedAt: startedAt, FinishedAt: startedAt.Add(30 * time.Second)},
+			},
+			expectedResults:   []string{models.JobResultSucceeded, models.JobResultTimedOut, models.JobResultUnreachable},
+			expectedDurations: []time.Duration{time.Second, 30 * time.Second, 0},
+			expectedDuration:  30 * time.Second,
+			expectedError:     "execution failed on 2 of 3 clients",
+			expectedExitCode:  ExitCodePartialFailure,
+		},
+		{
+			name: "total failure",
+			jobs: []models.Job{
+				{Jid: "1", ClientID: "cl1", Status: models.JobStatusFailed, Error: "exit status 1"},
+				{Jid: "2", ClientID: "cl2", Status: models.JobStatusFailed},
+				{Jid: "3", ClientID: "cl3", Status: models.JobStatusUnknown},
+			},
+			expectedResults:   []string{models.JobResultFailed, models.JobResultFailed, models.JobResultTimedOut},
+			expectedDurations: []time.Duration{0, 0, 0},
+			expectedError:     "execution failed on all 3 clients",
+			expectedExitCode:  ExitCodeTotalFailure,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			var itemsToRead []ReadChunk
+			for _, job := range tc.jobs {
+				jobBytes, err := json.Marshal(job)
+				require.NoError(t, err)
+				itemsToRead = append(itemsToRead, ReadChunk{Output: jobBytes})
+			}
+			itemsToRead = append(itemsToRead, ReadChunk{Err: io.EOF})
+
+			summaryRenderer := &JobsSummaryRendererMock{}
+			cc := &CommandsController{
+				ExecutionHelper: &ExecutionHelper{
+					ReadWriter:      &ReadWriterMock{itemsToRead: itemsToRead},
+					JobRenderer:     &JobRendererMock{},
+					SummaryRenderer: summaryRenderer,
+				},
+			}
+
+			err := cc.Start(context.Background(), config.FromValues(map[string]string{
+				ClientIDs: "cl1,cl2,cl3",
+				Command:   "ls",
+			}))
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				jobsErr, ok := err.(*JobsFailedError)
+				require.True(t, ok)
+				assert.Equal(t, tc.expectedExitCode, jobsErr.ExitCode())
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.NotNil(t, summaryRenderer.summary)
+			assert.Equal(t, tc.expectedDuration, summaryRenderer.summary.Duration)
+			require.Len(t, summaryRenderer.summary.Clients, 3)
+			for i, cl := range summaryRenderer.summary.Clients {
+				assert.Equal(t, tc.expectedResults[i], cl.Result)
+				assert.Equal(t, tc.expectedDurations[i], cl.Duration)
+			}
+		})
+	}
+}
+
+func TestCommandExecutionSingleClientFailure(t *testing.T) {
+	jobBytes, err := json.Marshal(models.Job{Jid: "1", ClientID: "cl1", Status: models.JobStatusFailed})
+	require.NoError(t, err)
+
+	summaryRenderer := &JobsSummaryRendererMock{}
+	cc := &CommandsController{
+		ExecutionHelper: &ExecutionHelper{
+			ReadWriter:      &ReadWriterMock{itemsToRead: []ReadChunk{{Output: jobBytes}, {Err: io.EOF}}},
+			JobRenderer:     &JobRendererMock{},
+			SummaryRenderer: summaryRenderer,
+		},
+	}
+
+	err = cc.Start(context.Background(), config.FromValues(map[string]string{
+		ClientIDs: "cl1",
+		Command:   "ls",
+	}))
+	require.NoError(t, err, "a single client run should keep its exit code")
+	assert.Nil(t, summaryRenderer.summary, "summary should be rendered only for multiple clients")
+}
+
+// interruptingReadWriterMock simulates Ctrl-C after the given items are read and waits till it's closed
+type interruptingReadWriterMock struct {
+	ReadWriterMock
+	closed chan struct{}
+}
+
+func (irw *interruptingReadWriterMock) Read() (msg []byte, err error) {
+	if irw.itemReadIndex < len(irw.itemsToRead) {
+		return irw.ReadWriterMock.Read()
+	}
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return nil, err
+	}
+	err = process.Signal(os.Interrupt)
+	if err != nil {
+		return nil, err
+	}
+	<-irw.closed
+
+	return nil, io.EOF
+}
+
+func (irw *interruptingReadWriterMock) Close() error {
+	close(irw.closed)
+	return nil
+}
+
+func TestCommandExecutionInterrupted(t *testing.T) {
+	jobBytes, err := json.Marshal(models.Job{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful})
+	require.NoError(t, err)
+
+	summaryRenderer := &JobsSummaryRendererMock{}
+	cc := &CommandsController{
+		ExecutionHelper: &ExecutionHelper{
+			ReadWriter: &interruptingReadWriterMock{
+				ReadWriterMock: ReadWriterMock{itemsToRead: []ReadChunk{{Output: jobBytes}}},
+				closed:         make(chan struct{}),
+			},
+			JobRenderer:     &JobRendererMock{},
+			SummaryRenderer: summaryRenderer,
+		},
+	}
+
+	err = cc.Start(context.Background(), config.FromValues(map[string]string{
+		ClientIDs: "cl1,cl2",
+		Command:   "ls",
+	}))
+	require.EqualError(t, err, "stopped waiting for the job results, the jobs keep running on the clients")
+	assert.Equal(t, ExitCodeInterrupted, err.(*JobsInterruptedError).ExitCode())
+	assert.Nil(t, summaryRenderer.summary, "clients still running after Ctrl-C should not be summarized as unreachable")
+}
+
+type JobSaverMock struct {
+	savedJobs []*models.Job
+}
+
+func (jsm *JobSaverMock) SaveJob(j *models.Job) error {
+	jsm.savedJobs = append(jsm.savedJobs, j)
+	return nil
+}
+
+func TestCommandExecutionSavesFinishedJobs(t *testing.T) {
+	var itemsToRead []ReadChunk
+	for _, job := range []models.Job{
+		{Jid: "1", ClientID: "cl1", Status: models.JobStatusRunning},
+		{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "out1"}},
+		{Jid: "2", ClientID: "cl2", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "out2"}},
+	} {
+		jobBytes, err := json.Marshal(job)
+		require.NoError(t, err)
+		itemsToRead = append(itemsToRead, ReadChunk{Output: jobBytes})
+	}
+
+	jobSaver := &JobSaverMock{}
+	cc := &CommandsController{
+		ExecutionHelper: &ExecutionHelper{
+			ReadWriter:  &ReadWriterMock{itemsToRead: append(itemsToRead, ReadChunk{Err: io.EOF})},
+			JobRenderer: &JobRendererMock{},
+			JobSaver:    jobSaver,
+		},
+	}
+
+	err := cc.Start(context.Background(), config.FromValues(map[string]string{
+		ClientIDs: "cl1,cl2",
+		Command:   "ls",
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, jobSaver.savedJobs, 2)
+	assert.Equal(t, "out1", jobSaver.savedJobs[0].Result.Stdout)
+	assert.Equal(t, "out2", jobSaver.savedJobs[1].Result.Stdout)
+}
+
+func TestCommandExecutionFlushesJobRenderer(t *testing.T) {
+	var itemsToRead []ReadChunk
+	for _, job := range []models.Job{
+		{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "out"}},
+		{Jid: "2", ClientID: "cl2", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "out"}},
+	} {
+		jobBytes, err := json.Marshal(job)
+		require.NoError(t, err)
+		itemsToRead = append(itemsToRead, ReadChunk{Output: jobBytes})
+	}
+
+	jobRenderer := &FlushingJobRendererMock{}
+	cc := &CommandsController{
+		ExecutionHelper: &ExecutionHelper{
+			ReadWriter:  &ReadWriterMock{itemsToRead: append(itemsToRead, ReadChunk{Err: io.EOF})},
+			JobRenderer: jobRenderer,
+		},
+	}
+
+	err := cc.Start(context.Background(), config.FromValues(map[string]string{
+		ClientIDs: "cl1,cl2",
+		Command:   "ls",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, jobRenderer.flushedJobsCount, "the renderer should be flushed after all jobs are rendered")
+}
+
+func TestCommandExecutionFlushesJobRendererOnError(t *testing.T) {
+	jobBytes, err := json.Marshal(models.Job{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful})
+	require.NoError(t, err)
+
+	jobRenderer := &FlushingJobRendererMock{}
+	cc := &CommandsController{
+		ExecutionHelper: &ExecutionHelper{
+			ReadWriter:  &ReadWriterMock{itemsToRead: []ReadChunk{{Output: jobBytes}, {Output: []byte("{")}, {Err: io.EOF}}},
+			JobRenderer: jobRenderer,
+		},
+	}
+
+	err = cc.Start(context.Background(), config.FromValues(map[string]string{
+		ClientIDs: "cl1,cl2",
+		Command:   "ls",
+	}))
+	require.Error(t, err)
+
+	assert.Equal(t, 1, jobRenderer.flushedJobsCount, "the jobs received before the error should be flushed")
+}
+
+func TestCommandExecutionDetachedWithOutputDir(t *testing.T) {
+	cc := &CommandsController{
+		ExecutionHelper: &ExecutionHelper{
+			JobSaver: &JobSaverMock{},
+		},
+	}
+
+	err := cc.Start(context.Background(), config.FromValues(map[string]string{
+		ClientIDs: "cl1",
+		Command:   "ls",
+		Detach:    "1",
+	}))
+	assert.EqualError(t, err, "--output-dir cannot be combined with --detach, as no results are received")
+}
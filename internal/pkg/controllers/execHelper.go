@@ -13,6 +13,8 @@ import (
 
 	options "github.com/breathbath/go_utils/v2/pkg/config"
 	io2 "github.com/breathbath/go_utils/v2/pkg/io"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/client"
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
 	"github.com/sirupsen/logrus"
 )
@@ -30,7 +32,16 @@ const (
 	IsSudo                   = "is_sudo"
 	Interpreter              = "interpreter"
 	IsFullOutput             = "full-command-response"
+	Detach                   = "detach"
+	NoProgress               = "no-progress"
+	OutputDir                = "output-dir"
+	OutputFileTemplate       = "output-file-template"
+	GroupByOutput            = "group-by-output"
 	waitingMsg               = "waiting for the command to finish"
+	finishedMsg              = "command finished"
+	ExitCodePartialFailure   = 2
+	ExitCodeTotalFailure     = 3
+	ExitCodeInterrupted      = 130
 )
 
 type CliReader interface {
@@ -43,6 +54,7 @@ type ReadWriter interface {
 	io.Closer
 }
 
+// Spinner shows the progress of a running job, messages contain a title line followed by tab separated lines
 type Spinner interface {
 	Start(msg string)
 	Update(msg string)
@@ -52,41 +64,183 @@ type Spinner interface {
 
 type JobRenderer interface {
 	RenderJob(j *models.Job) error
+	RenderJobStarted(js *models.JobStarted) error
+}
+
+// JobsFlusher is implemented by job renderers which render the collected jobs only when all of them are finished
+type JobsFlusher interface {
+	Flush() error
+}
+
+type JobsSummaryRenderer interface {
+	RenderJobsSummary(s *models.JobsSummary) error
+}
+
+type JobSaver interface {
+	SaveJob(j *models.Job) error
+}
+
+type JobStarter interface {
+	StartMultiClientCommand(ctx context.Context, wsCmd *models.WsScriptCommand) (*api.JobStartedResponse, error)
+	StartMultiClientScript(ctx context.Context, wsCmd *models.WsScriptCommand) (*api.JobStartedResponse, error)
 }
 
 type ExecutionHelper struct {
-	ClientSearch ClientSearch
-	JobRenderer  JobRenderer
-	ReadWriter   ReadWriter
+	ClientSearch    ClientSearch
+	JobRenderer     JobRenderer
+	ReadWriter      ReadWriter
+	JobStarter      JobStarter
+	Spinner         Spinner
+	SummaryRenderer JobsSummaryRenderer
+	JobSaver        JobSaver
+	// ReadWriterProvider opens connections for the submissions following the first one,
+	// as rport closes the websocket after sending the job results
+	ReadWriterProvider ReadWriterProvider
+}
+
+// JobsFailedError is returned when a command or script didn't succeed on some or all of multiple clients,
+// the exit code tells partial failures from total ones
+type JobsFailedError struct {
+	Summary *models.JobsSummary
+}
+
+func (e *JobsFailedError) IsPartial() bool {
+	return e.Summary.Count(models.JobResultSucceeded) > 0
+}
+
+func (e *JobsFailedError) ExitCode() int {
+	if e.IsPartial() {
+		return ExitCodePartialFailure
+	}
+
+	return ExitCodeTotalFailure
+}
+
+func (e *JobsFailedError) Error() string {
+	total := len(e.Summary.Clients)
+	if e.IsPartial() {
+		return fmt.Sprintf("execution failed on %d of %d clients", total-e.Summary.Count(models.JobResultSucceeded), total)
+	}
+
+	return fmt.Sprintf("execution failed on all %d clients", total)
+}
+
+// JobsInterruptedError is returned when waiting for the results was stopped by Ctrl-C
+type JobsInterruptedError struct{}
+
+func (e *JobsInterruptedError) ExitCode() int {
+	return ExitCodeInterrupted
+}
+
+func (e *JobsInterruptedError) Error() string {
+	return "stopped waiting for the job results, the jobs keep running on the clients"
 }
 
 func (eh *ExecutionHelper) execute(ctx context.Context, params *options.ParameterBag, scriptPayload, interpreter string) error {
+	defer eh.closeReadWriter()
+
+	clients, err := eh.getClients(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	wsCmd := eh.buildExecInput(params, clients, scriptPayload, interpreter)
+
+	return eh.executeInput(ctx, params, clients, wsCmd)
+}
+
+// closeReadWriter closes the current connection, it's replaced for each submission
+func (eh *ExecutionHelper) closeReadWriter() {
 	if eh.ReadWriter != nil {
-		defer io2.CloseResourceSecure("read writer", eh.ReadWriter)
+		io2.CloseResourceSecure("read writer", eh.ReadWriter)
+	}
+}
+
+// executeInput sends the command or script to the clients and renders the results unless it should run in background
+func (eh *ExecutionHelper) executeInput(
+	ctx context.Context,
+	params *options.ParameterBag,
+	clients []*models.Client,
+	wsCmd *models.WsScriptCommand,
+) error {
+	return eh.executeInputs(ctx, params, clients, []*models.WsScriptCommand{wsCmd})
+}
+
+// executeInputs sends the submissions one after another and renders the results of all of them together,
+// it's used if the clients need different payloads, the next submissions are skipped on interrupts
+// or if a submission failed and the execution should be aborted on errors
+func (eh *ExecutionHelper) executeInputs(
+	ctx context.Context,
+	params *options.ParameterBag,
+	clients []*models.Client,
+	wsCmds []*models.WsScriptCommand,
+) error {
+	if params.ReadBool(Detach, false) {
+		if eh.JobSaver != nil {
+			return fmt.Errorf("--%s cannot be combined with --%s, as no results are received", OutputDir, Detach)
+		}
+		for _, wsCmd := range wsCmds {
+			err := eh.startDetached(ctx, wsCmd)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	clientIDs, err := eh.getClientIDs(ctx, params)
+	progress := newJobProgress(clients)
+	interrupted, err := eh.startReading(ctx, progress, wsCmds)
+	// results received before an error are rendered as well, e.g. if they are grouped by output
+	if flusher, ok := eh.JobRenderer.(JobsFlusher); ok {
+		flushErr := flusher.Flush()
+		if err == nil {
+			err = flushErr
+		}
+	}
 	if err != nil {
 		return err
 	}
 
-	wsCmd := eh.buildExecInput(params, clientIDs, scriptPayload, interpreter)
-	err = eh.sendCommand(wsCmd)
+	// clients without results are still running after Ctrl-C, so they are not summarized as unreachable
+	if interrupted {
+		return &JobsInterruptedError{}
+	}
+
+	return eh.summarize(progress)
+}
+
+// summarize renders results of all clients if there are multiple ones and fails if the job didn't succeed on any of them,
+// the failure exit codes come with the summary, so single client runs and runs without a summary keep exiting with 0
+func (eh *ExecutionHelper) summarize(progress *jobProgress) error {
+	summary := progress.summary()
+	if eh.SummaryRenderer == nil || len(summary.Clients) <= 1 {
+		return nil
+	}
+
+	err := eh.SummaryRenderer.RenderJobsSummary(summary)
 	if err != nil {
 		return err
 	}
 
-	err = eh.startReading(ctx)
+	if summary.Count(models.JobResultSucceeded) < len(summary.Clients) {
+		return &JobsFailedError{Summary: summary}
+	}
 
-	return err
+	return nil
 }
 
 func (eh *ExecutionHelper) buildExecInput(
 	params *options.ParameterBag,
-	clientIDs, scriptPayload, interpreter string,
+	clients []*models.Client,
+	scriptPayload, interpreter string,
 ) *models.WsScriptCommand {
+	clientIDs := make([]string, 0, len(clients))
+	for _, cl := range clients {
+		clientIDs = append(clientIDs, cl.ID)
+	}
+
 	wsCmd := &models.WsScriptCommand{
-		ClientIDs:           strings.Split(clientIDs, ","),
+		ClientIDs:           clientIDs,
 		TimeoutSec:          params.ReadInt(Timeout, DefaultCmdTimeoutSeconds),
 		ExecuteConcurrently: params.ReadBool(ExecConcurrently, false),
 		GroupIDs:            nil,
@@ -126,41 +280,164 @@ func (eh *ExecutionHelper) sendCommand(wsCmd *models.WsScriptCommand) error {
 	return nil
 }
 
-func (eh *ExecutionHelper) getClientIDs(ctx context.Context, params *options.ParameterBag) (clientIDs string, err error) {
-	clientIDs = params.ReadString(ClientIDs, "")
+func (eh *ExecutionHelper) startDetached(ctx context.Context, wsCmd *models.WsScriptCommand) error {
+	var jobResp *api.JobStartedResponse
+	var err error
+	if wsCmd.Script != "" {
+		jobResp, err = eh.JobStarter.StartMultiClientScript(ctx, wsCmd)
+	} else {
+		jobResp, err = eh.JobStarter.StartMultiClientCommand(ctx, wsCmd)
+	}
+	if err != nil {
+		return err
+	}
+
+	if jobResp.Data == nil || jobResp.Data.Jid == "" {
+		return errors.New("no job id received from rport")
+	}
+
+	return eh.JobRenderer.RenderJobStarted(jobResp.Data)
+}
+
+func (eh *ExecutionHelper) getClients(ctx context.Context, params *options.ParameterBag) ([]*models.Client, error) {
+	clientIDs := params.ReadString(ClientIDs, "")
 	clientName := params.ReadString(ClientNameFlag, "")
+	hasFilters := client.HasFilters(params)
+
+	if clientIDs == "" && clientName == "" && !hasFilters {
+		return nil, errors.New("no client id nor name nor filter provided")
+	}
+
+	if clientIDs != "" && hasFilters {
+		return nil, errors.New("client filters cannot be combined with client ids, use client names instead")
+	}
+
+	if clientIDs != "" {
+		ids := strings.Split(clientIDs, ",")
+		clients := make([]*models.Client, 0, len(ids))
+		for _, id := range ids {
+			clients = append(clients, &models.Client{ID: id})
+		}
+		return clients, nil
+	}
+
+	clients, err := eh.ClientSearch.Search(ctx, clientName, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(clients) == 0 {
+		if clientName == "" {
+			return nil, errors.New("no client matches the provided filters")
+		}
+		return nil, fmt.Errorf("unknown client(s) '%s'", clientName)
+	}
 
-	if clientIDs == "" && clientName == "" {
-		return "", errors.New("no client id nor name provided")
+	return clients, nil
+}
+
+// getClientsWithDetails gives the clients together with their details like os and tags,
+// clients given by ids are searched as well
+func (eh *ExecutionHelper) getClientsWithDetails(ctx context.Context, params *options.ParameterBag) ([]*models.Client, error) {
+	clients, err := eh.getClients(ctx, params)
+	if err != nil {
+		return nil, err
 	}
 
+	clientIDs := params.ReadString(ClientIDs, "")
 	if clientIDs == "" {
-		clients, err := eh.ClientSearch.Search(ctx, clientName, params)
-		if err != nil {
-			return "", err
+		return clients, nil
+	}
+
+	// the search matches id prefixes, so only the clients with exactly the same ids are taken
+	foundClients, err := eh.ClientSearch.Search(ctx, clientIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	clientsByID := make(map[string]*models.Client, len(foundClients))
+	for _, cl := range foundClients {
+		clientsByID[cl.ID] = cl
+	}
+
+	for i, cl := range clients {
+		foundClient, ok := clientsByID[cl.ID]
+		if !ok {
+			return nil, fmt.Errorf("unknown client '%s'", cl.ID)
+		}
+		clients[i] = foundClient
+	}
+
+	return clients, nil
+}
+
+func (eh *ExecutionHelper) startReading(
+	ctx context.Context,
+	progress *jobProgress,
+	wsCmds []*models.WsScriptCommand,
+) (interrupted bool, err error) {
+	if eh.Spinner != nil {
+		eh.Spinner.Start(progress.message(waitingMsg))
+		defer func() {
+			if err == nil && progress.isSuccessful() {
+				eh.Spinner.StopSuccess(progress.message(finishedMsg))
+			} else {
+				eh.Spinner.StopError(progress.message(finishedMsg))
+			}
+		}()
+	}
+
+	for i, wsCmd := range wsCmds {
+		if i > 0 {
+			if wsCmd.AbortOnError && progress.hasFailures() {
+				logrus.Debugf("%d submissions are skipped, as the execution failed", len(wsCmds)-i)
+				return false, nil
+			}
+			err = eh.reconnect(ctx)
+			if err != nil {
+				return false, err
+			}
 		}
 
-		if len(clients) == 0 {
-			return "", fmt.Errorf("unknown client(s) '%s'", clientName)
+		err = eh.sendCommand(wsCmd)
+		if err != nil {
+			return false, err
 		}
 
-		for i := range clients {
-			cl := clients[i]
-			clientIDs += cl.ID + ","
+		interrupted, err = eh.readJobs(ctx, progress)
+		if err != nil || interrupted {
+			return interrupted, err
 		}
+	}
 
-		clientIDs = strings.Trim(clientIDs, ",")
+	return false, nil
+}
+
+// reconnect replaces the connection closed by rport after the results of the previous submission
+func (eh *ExecutionHelper) reconnect(ctx context.Context) error {
+	if eh.ReadWriterProvider == nil {
+		return errors.New("cannot send multiple submissions, as no connection provider is set")
+	}
+
+	io2.CloseResourceSecure("read writer", eh.ReadWriter)
+	rw, err := eh.ReadWriterProvider(ctx)
+	if err != nil {
+		return err
 	}
+	eh.ReadWriter = rw
 
-	return clientIDs, nil
+	return nil
 }
 
-func (eh *ExecutionHelper) startReading(ctx context.Context) error {
+// readJobs renders the job results till the connection is closed, it tells if the reading was interrupted
+func (eh *ExecutionHelper) readJobs(ctx context.Context, progress *jobProgress) (interrupted bool, err error) {
 	errsChan := make(chan error, 1)
 	msgChan := make(chan []byte, 1)
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
 
+	rw := eh.ReadWriter
 	go func() {
 		defer close(msgChan)
 		for {
@@ -168,7 +445,7 @@ func (eh *ExecutionHelper) startReading(ctx context.Context) error {
 			case <-ctx.Done():
 				return
 			default:
-				msg, err := eh.ReadWriter.Read()
+				msg, err := rw.Read()
 				if err != nil {
 					if err == io.EOF {
 						return
@@ -187,22 +464,49 @@ mainLoop:
 			break mainLoop
 		case msg, ok := <-msgChan:
 			if !ok {
-				return nil
+				return false, nil
 			}
-			err := eh.processRawMessage(msg)
+			err := eh.processRawMessage(msg, progress)
 			if err != nil {
-				return err
+				return false, err
 			}
 			logrus.Debug(waitingMsg)
 		case err := <-errsChan:
-			return err
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (eh *ExecutionHelper) processRawMessage(msg []byte, progress *jobProgress) error {
+	job, err := parseJobMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	progress.update(job)
+	if eh.Spinner != nil {
+		eh.Spinner.Update(progress.message(waitingMsg))
+	}
+
+	err = eh.JobRenderer.RenderJob(job)
+	if err != nil {
+		return err
+	}
+
+	if eh.JobSaver != nil && job.Status != models.JobStatusRunning {
+		err = eh.JobSaver.SaveJob(job)
+		if err != nil {
+			return fmt.Errorf("failed to save results of job %s: %v", job.Jid, err)
 		}
 	}
 
 	return nil
 }
 
-func (eh *ExecutionHelper) processRawMessage(msg []byte) error {
+// parseJobMessage converts a websocket message to a job result or to an error if rport sent one
+func parseJobMessage(msg []byte) (*models.Job, error) {
 	var job models.Job
 	err := json.Unmarshal(msg, &job)
 	if err != nil || job.Jid == "" {
@@ -211,13 +515,12 @@ func (eh *ExecutionHelper) processRawMessage(msg []byte) error {
 		err = json.Unmarshal(msg, &errResp)
 		if err != nil {
 			e := fmt.Errorf("cannot recognize command output message: %s, reason: %v", string(msg), err)
-			return e
+			return nil, e
 		}
-		return errResp
+		return nil, errResp
 	}
 
 	logrus.Debugf("received message: '%s'", string(msg))
 
-	err = eh.JobRenderer.RenderJob(&job)
-	return err
+	return &job, nil
 }
@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+const (
+	GroupDescription = "description"
+	GroupParam       = "param"
+)
+
+type ClientGroupAPI interface {
+	ClientGroups(ctx context.Context) (*api.ClientGroupsResponse, error)
+	ClientGroup(ctx context.Context, groupID string) (*api.ClientGroupResponse, error)
+	CreateClientGroup(ctx context.Context, group *models.ClientGroup) error
+	UpdateClientGroup(ctx context.Context, group *models.ClientGroup) error
+	DeleteClientGroup(ctx context.Context, groupID string) error
+	GetClients(ctx context.Context, filters ...*models.ClientFilter) (cls []*models.Client, err error)
+}
+
+type ClientGroupRenderer interface {
+	RenderClientGroups(groups []*models.ClientGroup) error
+	RenderClientGroup(group *models.ClientGroupDetails) error
+	RenderStatus(s output.KvProvider) error
+}
+
+type ClientGroupController struct {
+	Rport               ClientGroupAPI
+	ClientGroupRenderer ClientGroupRenderer
+}
+
+func (cgc *ClientGroupController) List(ctx context.Context) error {
+	groupsResp, err := cgc.Rport.ClientGroups(ctx)
+	if err != nil {
+		return err
+	}
+
+	return cgc.ClientGroupRenderer.RenderClientGroups(groupsResp.Data)
+}
+
+// Get renders a client group together with its member clients
+func (cgc *ClientGroupController) Get(ctx context.Context, groupID string) error {
+	groupResp, err := cgc.Rport.ClientGroup(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	group := groupResp.Data
+	if group == nil {
+		return fmt.Errorf("client group '%s' not found", groupID)
+	}
+
+	groupDetails := &models.ClientGroupDetails{
+		ClientGroup: *group,
+		Clients:     make([]*models.Client, 0, len(group.ClientIDs)),
+	}
+
+	if len(group.ClientIDs) > 0 {
+		clients, err := cgc.Rport.GetClients(ctx)
+		if err != nil {
+			return err
+		}
+
+		clientsByID := make(map[string]*models.Client, len(clients))
+		for _, cl := range clients {
+			clientsByID[cl.ID] = cl
+		}
+
+		for _, clientID := range group.ClientIDs {
+			cl, ok := clientsByID[clientID]
+			if !ok {
+				cl = &models.Client{ID: clientID}
+			}
+			groupDetails.Clients = append(groupDetails.Clients, cl)
+		}
+	}
+
+	return cgc.ClientGroupRenderer.RenderClientGroup(groupDetails)
+}
+
+func (cgc *ClientGroupController) Create(ctx context.Context, groupID string, params *options.ParameterBag) error {
+	groupParams, err := parseClientGroupParams(params.ReadStrings(GroupParam))
+	if err != nil {
+		return err
+	}
+
+	group := &models.ClientGroup{
+		ID:          groupID,
+		Description: params.ReadString(GroupDescription, ""),
+		Params:      groupParams,
+	}
+
+	err = cgc.Rport.CreateClientGroup(ctx, group)
+	if err != nil {
+		return err
+	}
+
+	return cgc.ClientGroupRenderer.RenderStatus(&models.OperationStatus{
+		Status: fmt.Sprintf("Client group '%s' created", groupID),
+	})
+}
+
+// Update changes the description if provided and replaces all params if at least one param is provided
+func (cgc *ClientGroupController) Update(ctx context.Context, groupID string, params *options.ParameterBag) error {
+	groupResp, err := cgc.Rport.ClientGroup(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	group := groupResp.Data
+	if group == nil {
+		return fmt.Errorf("client group '%s' not found", groupID)
+	}
+
+	if description, found := params.Read(GroupDescription, ""); found {
+		group.Description = fmt.Sprint(description)
+	}
+
+	paramExpressions := params.ReadStrings(GroupParam)
+	if len(paramExpressions) > 0 {
+		group.Params, err = parseClientGroupParams(paramExpressions)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = cgc.Rport.UpdateClientGroup(ctx, group)
+	if err != nil {
+		return err
+	}
+
+	return cgc.ClientGroupRenderer.RenderStatus(&models.OperationStatus{
+		Status: fmt.Sprintf("Client group '%s' updated", groupID),
+	})
+}
+
+func (cgc *ClientGroupController) Delete(ctx context.Context, groupID string) error {
+	err := cgc.Rport.DeleteClientGroup(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	return cgc.ClientGroupRenderer.RenderStatus(&models.OperationStatus{
+		Status: fmt.Sprintf("Client group '%s' deleted", groupID),
+	})
+}
+
+// parseClientGroupParams converts expressions like 'name=web*,db*' to client group params
+func parseClientGroupParams(expressions []string) (*models.ClientGroupParams, error) {
+	groupParams := &models.ClientGroupParams{}
+	for _, expr := range expressions {
+		exprParts := strings.SplitN(expr, "=", 2)
+		if len(exprParts) != 2 || exprParts[0] == "" || exprParts[1] == "" {
+			return nil, fmt.Errorf("invalid param '%s', expected format is key=value1,value2", expr)
+		}
+
+		values := strings.Split(exprParts[1], ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+
+		err := groupParams.Add(strings.TrimSpace(exprParts[0]), values...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return groupParams, nil
+}
@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+type ClientGroupAPIMock struct {
+	mock.Mock
+}
+
+func (cgam *ClientGroupAPIMock) ClientGroups(ctx context.Context) (*api.ClientGroupsResponse, error) {
+	args := cgam.Called(ctx)
+
+	return args.Get(0).(*api.ClientGroupsResponse), args.Error(1)
+}
+
+func (cgam *ClientGroupAPIMock) ClientGroup(ctx context.Context, groupID string) (*api.ClientGroupResponse, error) {
+	args := cgam.Called(ctx, groupID)
+
+	return args.Get(0).(*api.ClientGroupResponse), args.Error(1)
+}
+
+func (cgam *ClientGroupAPIMock) CreateClientGroup(ctx context.Context, group *models.ClientGroup) error {
+	args := cgam.Called(ctx, group)
+
+	return args.Error(0)
+}
+
+func (cgam *ClientGroupAPIMock) UpdateClientGroup(ctx context.Context, group *models.ClientGroup) error {
+	args := cgam.Called(ctx, group)
+
+	return args.Error(0)
+}
+
+func (cgam *ClientGroupAPIMock) DeleteClientGroup(ctx context.Context, groupID string) error {
+	args := cgam.Called(ctx, groupID)
+
+	return args.Error(0)
+}
+
+func (cgam *ClientGroupAPIMock) GetClients(ctx context.Context, filters ...*models.ClientFilter) ([]*models.Client, error) {
+	args := cgam.Called(ctx)
+
+	return args.Get(0).([]*models.Client), args.Error(1)
+}
+
+type ClientGroupRendererMock struct {
+	mock.Mock
+}
+
+func (cgrm *ClientGroupRendererMock) RenderClientGroups(groups []*models.ClientGroup) error {
+	args := cgrm.Called(groups)
+
+	return args.Error(0)
+}
+
+func (cgrm *ClientGroupRendererMock) RenderClientGroup(group *models.ClientGroupDetails) error {
+	args := cgrm.Called(group)
+
+	return args.Error(0)
+}
+
+func (cgrm *ClientGroupRendererMock) RenderStatus(s output.KvProvider) error {
+	args := cgrm.Called(s)
+
+	return args.Error(0)
+}
+
+func TestListClientGroups(t *testing.T) {
+	groups := []*models.ClientGroup{{ID: "web"}, {ID: "db"}}
+
+	apiMock := &ClientGroupAPIMock{}
+	apiMock.On("ClientGroups", mock.Anything).Return(&api.ClientGroupsResponse{Data: groups}, nil)
+
+	renderMock := &ClientGroupRendererMock{}
+	renderMock.On("RenderClientGroups", groups).Return(nil)
+
+	cgc := &ClientGroupController{Rport: apiMock, ClientGroupRenderer: renderMock}
+
+	err := cgc.List(context.Background())
+	require.NoError(t, err)
+	renderMock.AssertExpectations(t)
+}
+
+func TestGetClientGroupWithMemberClients(t *testing.T) {
+	apiMock := &ClientGroupAPIMock{}
+	apiMock.On("ClientGroup", mock.Anything, "web").Return(&api.ClientGroupResponse{Data: &models.ClientGroup{
+		ID:        "web",
+		Params:    &models.ClientGroupParams{Name: []string{"web*"}},
+		ClientIDs: []string{"cl2", "cl3"},
+	}}, nil)
+	apiMock.On("GetClients", mock.Anything).Return([]*models.Client{
+		{ID: "cl1", Name: "db1"},
+		{ID: "cl2", Name: "web1"},
+	}, nil)
+
+	renderMock := &ClientGroupRendererMock{}
+	renderMock.On("RenderClientGroup", mock.Anything).Return(nil)
+
+	cgc := &ClientGroupController{Rport: apiMock, ClientGroupRenderer: renderMock}
+
+	err := cgc.Get(context.Background(), "web")
+	require.NoError(t, err)
+
+	renderMock.AssertCalled(t, "RenderClientGroup", &models.ClientGroupDetails{
+		ClientGroup: models.ClientGroup{
+			ID:        "web",
+			Params:    &models.ClientGroupParams{Name: []string{"web*"}},
+			ClientIDs: []string{"cl2", "cl3"},
+		},
+		Clients: []*models.Client{
+			{ID: "cl2", Name: "web1"},
+			{ID: "cl3"},
+		},
+	})
+}
+
+func TestCreateClientGroup(t *testing.T) {
+	apiMock := &ClientGroupAPIMock{}
+	apiMock.On("CreateClientGroup", mock.Anything, mock.Anything).Return(nil)
+
+	renderMock := &ClientGroupRendererMock{}
+	renderMock.On("RenderStatus", mock.Anything).Return(nil)
+
+	cgc := &ClientGroupController{Rport: apiMock, ClientGroupRenderer: renderMock}
+
+	params := options.New(options.NewMapValuesProvider(map[string]interface{}{
+		GroupDescription: "Linux web servers",
+		GroupParam:       []string{"name=web*, www*", "os_family=linux"},
+	}))
+
+	err := cgc.Create(context.Background(), "web", params)
+	require.NoError(t, err)
+
+	apiMock.AssertCalled(t, "CreateClientGroup", mock.Anything, &models.ClientGroup{
+		ID:          "web",
+		Description: "Linux web servers",
+		Params: &models.ClientGroupParams{
+			Name:     []string{"web*", "www*"},
+			OSFamily: []string{"linux"},
+		},
+	})
+	renderMock.AssertCalled(t, "RenderStatus", &models.OperationStatus{Status: "Client group 'web' created"})
+}
+
+func TestCreateClientGroupWithInvalidParams(t *testing.T) {
+	testCases := []struct {
+		param         string
+		expectedError string
+	}{
+		{
+			param:         "name",
+			expectedError: "invalid param 'name', expected format is key=value1,value2",
+		},
+		{
+			param:         "color=red",
+			expectedError: "unknown client group param 'color', supported params are",
+		},
+	}
+
+	for _, tc := range testCases {
+		cgc := &ClientGroupController{Rport: &ClientGroupAPIMock{}, ClientGroupRenderer: &ClientGroupRendererMock{}}
+
+		params := options.New(options.NewMapValuesProvider(map[string]interface{}{
+			GroupParam: []string{tc.param},
+		}))
+
+		err := cgc.Create(context.Background(), "web", params)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.expectedError)
+	}
+}
+
+func TestUpdateClientGroup(t *testing.T) {
+	testCases := []struct {
+		name          string
+		params        map[string]interface{}
+		expectedGroup *models.ClientGroup
+	}{
+		{
+			name:   "description only",
+			params: map[string]interface{}{GroupDescription: "new"},
+			expectedGroup: &models.ClientGroup{
+				ID:          "web",
+				Description: "new",
+				Params:      &models.ClientGroupParams{Name: []string{"web*"}},
+			},
+		},
+		{
+			name:   "params only",
+			params: map[string]interface{}{GroupParam: []string{"tag=prod"}},
+			expectedGroup: &models.ClientGroup{
+				ID:          "web",
+				Description: "old",
+				Params:      &models.ClientGroupParams{Tag: []string{"prod"}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			apiMock := &ClientGroupAPIMock{}
+			apiMock.On("ClientGroup", mock.Anything, "web").Return(&api.ClientGroupResponse{Data: &models.ClientGroup{
+				ID:          "web",
+				Description: "old",
+				Params:      &models.ClientGroupParams{Name: []string{"web*"}},
+			}}, nil)
+			apiMock.On("UpdateClientGroup", mock.Anything, mock.Anything).Return(nil)
+
+			renderMock := &ClientGroupRendererMock{}
+			renderMock.On("RenderStatus", mock.Anything).Return(nil)
+
+			cgc := &ClientGroupController{Rport: apiMock, ClientGroupRenderer: renderMock}
+
+			err := cgc.Update(context.Background(), "web", options.New(options.NewMapValuesProvider(tc.params)))
+			require.NoError(t, err)
+
+			apiMock.AssertCalled(t, "UpdateClientGroup", mock.Anything, tc.expectedGroup)
+			renderMock.AssertCalled(t, "RenderStatus", &models.OperationStatus{Status: "Client group 'web' updated"})
+		})
+	}
+}
+
+func TestDeleteClientGroup(t *testing.T) {
+	apiMock := &ClientGroupAPIMock{}
+	apiMock.On("DeleteClientGroup", mock.Anything, "web").Return(nil)
+
+	renderMock := &ClientGroupRendererMock{}
+	renderMock.On("RenderStatus", mock.Anything).Return(nil)
+
+	cgc := &ClientGroupController{Rport: apiMock, ClientGroupRenderer: renderMock}
+
+	err := cgc.Delete(context.Background(), "web")
+	require.NoError(t, err)
+	renderMock.AssertCalled(t, "RenderStatus", &models.OperationStatus{Status: "Client group 'web' deleted"})
+}
@@ -1,25 +1,32 @@
 package controllers
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
+	"time"
 
 	options "github.com/breathbath/go_utils/v2/pkg/config"
 	"github.com/breathbath/go_utils/v2/pkg/env"
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/auth"
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
+const SaveQrImage = "save-qr"
+
 type ConfigWriter func(params *options.ParameterBag) (err error)
 
 type QrImageWriterProvider func(namePattern string) (writer io.Writer, closr io.Closer, name string, err error)
 
+type QrImageDeleter func(name string) error
+
 type TotPSecretRenderer interface {
 	RenderTotPSecret(key *models.TotPSecretOutput) error
 }
@@ -29,12 +36,19 @@ type InitController struct {
 	PromptReader          config.PromptReader
 	TotPSecretRenderer    TotPSecretRenderer
 	QrImageWriterProvider QrImageWriterProvider
+	QrImageDeleter        QrImageDeleter
+	// Now gives the time to generate TOTP codes
+	Now func() time.Time
 }
 
 func (ic *InitController) InitConfig(ctx context.Context, params *options.ParameterBag) error {
 	login := params.ReadString(config.Login, "")
 	serverURL := params.ReadString(config.ServerURL, config.DefaultServerURL)
 
+	if params.ReadString(config.APIToken, "") != "" {
+		return ic.initConfigWithAPIToken(ctx, params)
+	}
+
 	apiAuth := &utils.StorageBasicAuth{
 		AuthProvider: func() (l, p string, err error) {
 			p = params.ReadString(config.Password, "")
@@ -60,7 +74,7 @@ func (ic *InitController) InitConfig(ctx context.Context, params *options.Parame
 				return loginResp.Data.Token, nil
 			},
 		})
-		loginResp, err = ic.processTotP(ctx, loginResp.Data.TwoFA.TotPKeyStatus, cl, login, tokenValidity)
+		loginResp, err = ic.processTotP(ctx, params, loginResp.Data.TwoFA.TotPKeyStatus, cl, login, tokenValidity)
 		if err != nil {
 			return fmt.Errorf("totP secret processing to rport failed: %v", err)
 		}
@@ -72,7 +86,7 @@ func (ic *InitController) InitConfig(ctx context.Context, params *options.Parame
 				return loginResp.Data.Token, nil
 			},
 		})
-		loginResp, err = ic.process2FA(ctx, cl, loginResp.Data, login, tokenValidity)
+		loginResp, err = ic.process2FA(ctx, params, cl, loginResp.Data, login, tokenValidity)
 		if err != nil {
 			return fmt.Errorf("2 factor login to rport failed: %v", err)
 		}
@@ -84,6 +98,9 @@ func (ic *InitController) InitConfig(ctx context.Context, params *options.Parame
 	valuesProvider := options.NewMapValuesProvider(map[string]interface{}{
 		config.ServerURL: params.ReadString(config.ServerURL, ""),
 		config.Token:     loginResp.Data.Token,
+		config.Profile:   params.ReadString(config.Profile, ""),
+		// empty secret store keeps the one which was used for the profile before
+		config.SecretStoreFlag: params.ReadString(config.SecretStoreFlag, ""),
 	})
 
 	err = ic.ConfigWriter(options.New(valuesProvider))
@@ -94,37 +111,64 @@ func (ic *InitController) InitConfig(ctx context.Context, params *options.Parame
 	return nil
 }
 
+// initConfigWithAPIToken verifies the api token and stores it to login with basic auth, so no session token is needed
+func (ic *InitController) initConfigWithAPIToken(ctx context.Context, params *options.ParameterBag) error {
+	login := params.ReadString(config.Login, "")
+	apiToken := params.ReadString(config.APIToken, "")
+	serverURL := params.ReadString(config.ServerURL, config.DefaultServerURL)
+
+	cl := api.New(serverURL, &utils.StorageBasicAuth{
+		AuthProvider: func() (string, string, error) {
+			return login, apiToken, nil
+		},
+	})
+	_, err := cl.Me(ctx)
+	if err != nil {
+		return fmt.Errorf("config verification failed against the rport: %v", err)
+	}
+
+	valuesProvider := options.NewMapValuesProvider(map[string]interface{}{
+		config.ServerURL:       params.ReadString(config.ServerURL, ""),
+		config.Login:           login,
+		config.APIToken:        apiToken,
+		config.Profile:         params.ReadString(config.Profile, ""),
+		config.SecretStoreFlag: params.ReadString(config.SecretStoreFlag, ""),
+	})
+
+	return ic.ConfigWriter(options.New(valuesProvider))
+}
+
 func (ic *InitController) process2FA(
 	ctx context.Context,
+	params *options.ParameterBag,
 	cl *api.Rport,
 	loginToken models.Token,
 	username string,
 	tokenLifetime int,
 ) (li api.LoginResponse, err error) {
-	req := config.ParameterRequirement{
-		Field: "code",
-		Help: fmt.Sprintf(
-			"2 factor auth is enabled, please provide code that was sent to %s via %s",
-			loginToken.TwoFA.SentTo,
-			loginToken.TwoFA.DeliveryMethod,
-		),
-		Validate:   config.RequiredValidate,
-		IsRequired: true,
-		Type:       config.StringRequirementType,
+	code := params.ReadString(config.TwoFACode, "")
+	if code != "" {
+		li, err = cl.GetTokenBy2FA(ctx, code, username, tokenLifetime)
+		return li, wrapProvided2FACodeError(err)
 	}
-	resultMap := map[string]interface{}{}
-	err = config.PromptRequiredValues([]config.ParameterRequirement{req}, resultMap, ic.PromptReader)
+
+	code, err = ic.prompt2FACode(fmt.Sprintf(
+		"2 factor auth is enabled, please provide code that was sent to %s via %s",
+		loginToken.TwoFA.SentTo,
+		loginToken.TwoFA.DeliveryMethod,
+	))
 	if err != nil {
 		return li, err
 	}
 
-	li, err = cl.GetTokenBy2FA(ctx, resultMap["code"].(string), username, tokenLifetime)
+	li, err = cl.GetTokenBy2FA(ctx, code, username, tokenLifetime)
 
 	return li, err
 }
 
 func (ic *InitController) processTotP(
 	ctx context.Context,
+	params *options.ParameterBag,
 	totPSecretKeyStatus string,
 	cl *api.Rport,
 	login string,
@@ -137,44 +181,133 @@ func (ic *InitController) processTotP(
 			return li, err
 		}
 
-		totPSecretOutput := &models.TotPSecretOutput{
-			Secret: totpSecretResp.Secret,
+		var totPSecretOutput *models.TotPSecretOutput
+		totPSecretOutput, err = ic.buildTotPSecretOutput(totpSecretResp, params.ReadBool(SaveQrImage, false))
+		if totPSecretOutput != nil && totPSecretOutput.File != "" {
+			defer ic.deleteTotPSecretQr(totPSecretOutput.File)
 		}
-		totPSecretOutput.File, err = ic.saveTotPSecretQr(totpSecretResp.QRImageBase64)
 		if err != nil {
 			return li, err
 		}
 
-		totPSecretOutput.Comment = "New Authenticator app secret key was created. " +
-			"Please use the secret key below to create a new account in an Authenticator app. " +
-			"Alternatively you can open the qr code image and scan it with your camera. " +
-			"Don't forget to delete the qr code image after it!"
-
 		err = ic.TotPSecretRenderer.RenderTotPSecret(totPSecretOutput)
 		if err != nil {
 			return li, err
 		}
 	}
 
+	code := params.ReadString(config.TwoFACode, "")
+	if code != "" {
+		li, err = cl.GetTokenBy2FA(ctx, code, login, tokenLifetime)
+		return li, wrapProvided2FACodeError(err)
+	}
+
+	totPSecret := params.ReadString(config.TotPSecret, "")
+	if totPSecret != "" {
+		code, err = auth.GenerateTotPCode(totPSecret, ic.now())
+		if err != nil {
+			return li, err
+		}
+
+		li, err = cl.GetTokenBy2FA(ctx, code, login, tokenLifetime)
+		if err != nil {
+			return li, fmt.Errorf(
+				"the code generated from the TOTP secret provided by --%s or %s was rejected, "+
+					"please check the secret and the system clock: %v",
+				config.TotPSecret,
+				config.TotPSecretEnvVar,
+				err,
+			)
+		}
+
+		return li, nil
+	}
+
+	code, err = ic.prompt2FACode("Please provide code generated by your Authenticator app")
+	if err != nil {
+		return li, err
+	}
+
+	li, err = cl.GetTokenBy2FA(ctx, code, login, tokenLifetime)
+
+	return li, err
+}
+
+func (ic *InitController) prompt2FACode(help string) (string, error) {
 	req := config.ParameterRequirement{
 		Field:      "code",
-		Help:       "Please provide code generated by your Authenticator app",
+		Help:       help,
 		Validate:   config.RequiredValidate,
 		IsRequired: true,
 		Type:       config.StringRequirementType,
 	}
 	resultMap := map[string]interface{}{}
-	err = config.PromptRequiredValues([]config.ParameterRequirement{req}, resultMap, ic.PromptReader)
+	err := config.PromptRequiredValues([]config.ParameterRequirement{req}, resultMap, ic.PromptReader)
 	if err != nil {
-		return li, err
+		return "", err
 	}
 
-	li, err = cl.GetTokenBy2FA(ctx, resultMap["code"].(string), login, tokenLifetime)
+	return resultMap["code"].(string), nil
+}
 
-	return li, err
+func (ic *InitController) now() time.Time {
+	if ic.Now != nil {
+		return ic.Now()
+	}
+
+	return time.Now()
+}
+
+func wrapProvided2FACodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("the code provided by --%s or %s was rejected: %v", config.TwoFACode, config.TwoFACodeEnvVar, err)
 }
 
-func (ic *InitController) saveTotPSecretQr(qrBase64 string) (filePath string, err error) {
+// buildTotPSecretOutput draws the qr code in the terminal, the qr code image is saved to a file if requested
+// or if it cannot be drawn
+func (ic *InitController) buildTotPSecretOutput(
+	totpSecretResp *models.TotPSecretResp,
+	isQrImageSaved bool,
+) (*models.TotPSecretOutput, error) {
+	totPSecretOutput := &models.TotPSecretOutput{
+		Secret: totpSecretResp.Secret,
+	}
+
+	qrImage, err := base64.StdEncoding.DecodeString(totpSecretResp.QRImageBase64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s from base64", totpSecretResp.QRImageBase64)
+	}
+
+	totPSecretOutput.QR, err = output.RenderQrImageToText(qrImage)
+	if err != nil {
+		logrus.Warnf("cannot show the qr code in the terminal, it will be saved as image: %v", err)
+		isQrImageSaved = true
+	}
+
+	if isQrImageSaved {
+		totPSecretOutput.File, err = ic.saveTotPSecretQr(qrImage)
+		if err != nil {
+			return totPSecretOutput, err
+		}
+	}
+
+	totPSecretOutput.Comment = "New Authenticator app secret key was created. " +
+		"Please use the secret key below to create a new account in an Authenticator app."
+	if totPSecretOutput.QR != "" {
+		totPSecretOutput.Comment += " Alternatively you can scan the qr code below with your camera."
+	}
+	if totPSecretOutput.File != "" {
+		totPSecretOutput.Comment += " The qr code image can be also opened and scanned from the file below, " +
+			"it will be deleted after the login."
+	}
+
+	return totPSecretOutput, nil
+}
+
+func (ic *InitController) saveTotPSecretQr(qrImage []byte) (filePath string, err error) {
 	qrWriter, clsr, name, err := ic.QrImageWriterProvider("qr-*.png")
 
 	if err != nil {
@@ -184,12 +317,21 @@ func (ic *InitController) saveTotPSecretQr(qrBase64 string) (filePath string, er
 		defer clsr.Close()
 	}
 
-	decoder := base64.NewDecoder(base64.StdEncoding, bytes.NewBufferString(qrBase64))
-
-	_, err = io.Copy(qrWriter, decoder)
+	_, err = qrWriter.Write(qrImage)
 	if err != nil {
-		return "", errors.Wrapf(err, "failed to decode %s from base64", qrBase64)
+		return name, errors.Wrapf(err, "failed to write qr code image to %s", name)
 	}
 
 	return name, nil
 }
+
+func (ic *InitController) deleteTotPSecretQr(filePath string) {
+	if ic.QrImageDeleter == nil {
+		return
+	}
+
+	err := ic.QrImageDeleter(filePath)
+	if err != nil {
+		logrus.Warnf("failed to delete the qr code image %s, please delete it manually: %v", filePath, err)
+	}
+}
@@ -6,12 +6,16 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"image"
+	"image/draw"
+	"image/png"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
 	"github.com/stretchr/testify/mock"
@@ -371,6 +375,41 @@ func TestInitTotPWithSecretSuccess(t *testing.T) {
 	assert.Equal(t, validLoginTokenWithout2Fa, writtenParams.ReadString(config.Token, ""))
 }
 
+func TestInitWithAPITokenSuccess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v1/me", r.URL.String())
+		assertBasicLoginPass(t, "admin", "ab12cd34secret", r)
+
+		e := json.NewEncoder(rw).Encode(api.UserResponse{Data: models.Me{Username: "admin"}})
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	var writtenParams *options.ParameterBag
+	tController := InitController{
+		ConfigWriter: func(params *options.ParameterBag) (err error) {
+			writtenParams = params
+			return nil
+		},
+		PromptReader:       &PromptReaderMock{},
+		TotPSecretRenderer: &TotPSecretRendererMock{},
+	}
+
+	params := config.FromValues(map[string]string{
+		config.ServerURL: srv.URL,
+		config.Login:     "admin",
+		config.APIToken:  "ab12cd34secret",
+	})
+	err := tController.InitConfig(context.Background(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, srv.URL, writtenParams.ReadString(config.ServerURL, ""))
+	assert.Equal(t, "admin", writtenParams.ReadString(config.Login, ""))
+	assert.Equal(t, "ab12cd34secret", writtenParams.ReadString(config.APIToken, ""))
+	assert.Equal(t, "", writtenParams.ReadString(config.Token, ""))
+}
+
 func TestInitError(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		rw.WriteHeader(http.StatusInternalServerError)
@@ -394,3 +433,235 @@ func TestInitError(t *testing.T) {
 
 	assert.EqualError(t, err, "config verification failed against the rport: operation failed")
 }
+
+func TestInitNonInteractive2FA(t *testing.T) {
+	const rfcTotPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	testCases := []struct {
+		name          string
+		twoFA         models.TwoFA
+		params        map[string]string
+		acceptedCode  string
+		expectedError string
+	}{
+		{
+			name:         "code sent by email",
+			twoFA:        models.TwoFA{SentTo: "no@mail.me", DeliveryMethod: "email"},
+			params:       map[string]string{config.TwoFACode: "someCode"},
+			acceptedCode: "someCode",
+		},
+		{
+			name:         "code of authenticator app",
+			twoFA:        models.TwoFA{DeliveryMethod: "totp_authenticator_app", TotPKeyStatus: api.TotPKeyExists},
+			params:       map[string]string{config.TwoFACode: "341234"},
+			acceptedCode: "341234",
+		},
+		{
+			name:         "code generated from totp secret",
+			twoFA:        models.TwoFA{DeliveryMethod: "totp_authenticator_app", TotPKeyStatus: api.TotPKeyExists},
+			params:       map[string]string{config.TotPSecret: rfcTotPSecret},
+			acceptedCode: "287082",
+		},
+		{
+			name:         "provided code is preferred to totp secret",
+			twoFA:        models.TwoFA{DeliveryMethod: "totp_authenticator_app", TotPKeyStatus: api.TotPKeyExists},
+			params:       map[string]string{config.TwoFACode: "341234", config.TotPSecret: rfcTotPSecret},
+			acceptedCode: "341234",
+		},
+		{
+			name:         "rejected code sent by email",
+			twoFA:        models.TwoFA{SentTo: "no@mail.me", DeliveryMethod: "email"},
+			params:       map[string]string{config.TwoFACode: "wrongCode"},
+			acceptedCode: "someCode",
+			expectedError: "2 factor login to rport failed: the code provided by --2fa-code or RPORT_2FA_CODE was rejected: " +
+				"invalid code",
+		},
+		{
+			name:         "rejected generated code",
+			twoFA:        models.TwoFA{DeliveryMethod: "totp_authenticator_app", TotPKeyStatus: api.TotPKeyExists},
+			params:       map[string]string{config.TotPSecret: "JBSWY3DPEHPK3PXP"},
+			acceptedCode: "287082",
+			expectedError: "totP secret processing to rport failed: the code generated from the TOTP secret provided by " +
+				"--totp-secret or RPORT_TOTP_SECRET was rejected, please check the secret and the system clock: invalid code",
+		},
+		{
+			name:          "invalid totp secret",
+			twoFA:         models.TwoFA{DeliveryMethod: "totp_authenticator_app", TotPKeyStatus: api.TotPKeyExists},
+			params:        map[string]string{config.TotPSecret: "1"},
+			expectedError: "totP secret processing to rport failed: invalid TOTP secret, a base32 encoded value is expected: illegal base32 data at input byte 0",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				if strings.HasPrefix(r.URL.String(), "/api/v1/login") {
+					e := json.NewEncoder(rw).Encode(api.LoginResponse{
+						Data: models.Token{Token: validLoginTokenWith2FaWithTotPSecret, TwoFA: tc.twoFA},
+					})
+					assert.NoError(t, e)
+					return
+				}
+
+				twoFALogin, e := getTwoFaLoginFromRequestBody(r)
+				require.NoError(t, e)
+				if twoFALogin.Token != tc.acceptedCode {
+					rw.WriteHeader(http.StatusUnauthorized)
+					e = json.NewEncoder(rw).Encode(map[string]interface{}{
+						"errors": []map[string]string{{"title": "invalid code"}},
+					})
+					assert.NoError(t, e)
+					return
+				}
+
+				e = json.NewEncoder(rw).Encode(api.LoginResponse{Data: models.Token{Token: validLoginTokenWithout2Fa}})
+				assert.NoError(t, e)
+			}))
+			defer srv.Close()
+
+			var writtenParams *options.ParameterBag
+			promptReader := &PromptReaderMock{}
+			tController := InitController{
+				ConfigWriter: func(params *options.ParameterBag) (err error) {
+					writtenParams = params
+					return nil
+				},
+				PromptReader:       promptReader,
+				TotPSecretRenderer: &TotPSecretRendererMock{},
+				Now: func() time.Time {
+					return time.Unix(59, 0)
+				},
+			}
+
+			paramValues := map[string]string{
+				config.ServerURL: srv.URL,
+				config.Login:     "log1",
+				config.Password:  "pass1",
+			}
+			for key, value := range tc.params {
+				paramValues[key] = value
+			}
+
+			err := tController.InitConfig(context.Background(), config.FromValues(paramValues))
+			assert.Equal(t, 0, promptReader.ReadCount)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, validLoginTokenWithout2Fa, writtenParams.ReadString(config.Token, ""))
+		})
+	}
+}
+
+// buildQrImageBase64 draws the finder patterns of a QR code of 21x21 modules with a quiet zone of 2 modules
+func buildQrImageBase64(t *testing.T) string {
+	const size, scale, quietZone = 21, 2, 2
+	img := image.NewGray(image.Rect(0, 0, (size+2*quietZone)*scale, (size+2*quietZone)*scale))
+	fillModules := func(rect image.Rectangle, c image.Image) {
+		rect = rect.Add(image.Pt(quietZone, quietZone))
+		draw.Draw(img, image.Rect(rect.Min.X*scale, rect.Min.Y*scale, rect.Max.X*scale, rect.Max.Y*scale), c, image.Point{}, draw.Src)
+	}
+
+	fillModules(image.Rect(-quietZone, -quietZone, size+quietZone, size+quietZone), image.White)
+	for _, corner := range []image.Point{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+		fillModules(image.Rect(0, 0, 7, 7).Add(corner), image.Black)
+		fillModules(image.Rect(1, 1, 6, 6).Add(corner), image.White)
+		fillModules(image.Rect(2, 2, 5, 5).Add(corner), image.Black)
+	}
+
+	buf := &bytes.Buffer{}
+	err := png.Encode(buf, img)
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestInitTotPEnrollmentQr(t *testing.T) {
+	qrImageBase64 := buildQrImageBase64(t)
+
+	testCases := []struct {
+		name           string
+		isQrImageSaved bool
+	}{
+		{
+			name: "qr code in terminal",
+		},
+		{
+			name:           "qr code in terminal and image",
+			isQrImageSaved: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				var resp interface{}
+				switch {
+				case strings.HasPrefix(r.URL.String(), "/api/v1/login"):
+					resp = api.LoginResponse{Data: models.Token{
+						Token: validLoginTokenWith2FaWithoutTotPSecret,
+						TwoFA: models.TwoFA{DeliveryMethod: "totp_authenticator_app", TotPKeyStatus: api.TotPKeyPending},
+					}}
+				case strings.HasPrefix(r.URL.String(), "/api/v1/me/totp-secret"):
+					resp = &models.TotPSecretResp{Secret: "some secret", QRImageBase64: qrImageBase64}
+				default:
+					resp = api.LoginResponse{Data: models.Token{Token: validLoginTokenWithout2Fa}}
+				}
+				e := json.NewEncoder(rw).Encode(resp)
+				assert.NoError(t, e)
+			}))
+			defer srv.Close()
+
+			totpSecretRenderer := &TotPSecretRendererMock{}
+			totpSecretRenderer.On("RenderTotPSecret", mock.Anything).Return(nil)
+
+			qrCodeBuf := &bytes.Buffer{}
+			deletedFiles := []string{}
+			tController := InitController{
+				ConfigWriter: func(params *options.ParameterBag) (err error) {
+					return nil
+				},
+				PromptReader: &PromptReaderMock{
+					ReadOutputs: []string{"123456"},
+				},
+				TotPSecretRenderer: totpSecretRenderer,
+				QrImageWriterProvider: func(namePattern string) (writer io.Writer, closr io.Closer, name string, err error) {
+					return qrCodeBuf, nil, "qr-file.png", nil
+				},
+				QrImageDeleter: func(name string) error {
+					deletedFiles = append(deletedFiles, name)
+					return nil
+				},
+			}
+
+			params := options.New(options.NewMapValuesProvider(map[string]interface{}{
+				config.ServerURL: srv.URL,
+				config.Login:     "log1",
+				config.Password:  "pass1",
+				SaveQrImage:      tc.isQrImageSaved,
+			}))
+			err := tController.InitConfig(context.Background(), params)
+			require.NoError(t, err)
+
+			actualOutput := totpSecretRenderer.Calls[0].Arguments.Get(0).(*models.TotPSecretOutput)
+			assert.Equal(t, "some secret", actualOutput.Secret)
+			assert.Contains(t, actualOutput.QR, "█")
+			assert.Contains(t, actualOutput.Comment, "scan the qr code below")
+
+			if !tc.isQrImageSaved {
+				assert.Equal(t, "", actualOutput.File)
+				assert.Equal(t, 0, qrCodeBuf.Len())
+				assert.Empty(t, deletedFiles)
+				return
+			}
+
+			assert.Equal(t, "qr-file.png", actualOutput.File)
+			assert.Equal(t, qrImageBase64, base64.StdEncoding.EncodeToString(qrCodeBuf.Bytes()))
+			assert.Equal(t, []string{"qr-file.png"}, deletedFiles)
+		})
+	}
+}
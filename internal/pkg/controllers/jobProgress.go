@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+const (
+	jobProgressPending  = "pending"
+	jobProgressRunning  = "running"
+	jobProgressSuccess  = "success"
+	jobProgressFailed   = "failed"
+	jobProgressTimedOut = "timed out"
+)
+
+// jobProgress keeps the execution state of each client of a multi client job, the state is given to Spinner
+// as a title line followed by lines with tab separated client name and state
+type jobProgress struct {
+	clientIDs []string
+	names     map[string]string
+	states    map[string]string
+	jobs      map[string]*models.Job
+}
+
+func newJobProgress(clients []*models.Client) *jobProgress {
+	jp := &jobProgress{
+		names:  map[string]string{},
+		states: map[string]string{},
+		jobs:   map[string]*models.Job{},
+	}
+	for _, cl := range clients {
+		jp.addClient(cl.ID, cl.Name)
+	}
+
+	return jp
+}
+
+func (jp *jobProgress) addClient(id, name string) {
+	if _, ok := jp.states[id]; !ok {
+		jp.clientIDs = append(jp.clientIDs, id)
+		jp.states[id] = jobProgressPending
+	}
+	if name != "" {
+		jp.names[id] = name
+	}
+}
+
+// update sets the state of the job client, clients of groups are not known before their first job arrives
+func (jp *jobProgress) update(j *models.Job) {
+	jp.addClient(j.ClientID, j.ClientName)
+	jp.jobs[j.ClientID] = j
+
+	switch j.Status {
+	case models.JobStatusRunning:
+		jp.states[j.ClientID] = jobProgressRunning
+	case models.JobStatusSuccessful:
+		jp.states[j.ClientID] = jobProgressSuccess
+	case models.JobStatusFailed:
+		jp.states[j.ClientID] = jobProgressFailed
+	case models.JobStatusUnknown:
+		jp.states[j.ClientID] = jobProgressTimedOut
+	default:
+		jp.states[j.ClientID] = j.Status
+	}
+}
+
+func (jp *jobProgress) countFinished() int {
+	finished := 0
+	for _, state := range jp.states {
+		if state != jobProgressPending && state != jobProgressRunning {
+			finished++
+		}
+	}
+
+	return finished
+}
+
+func (jp *jobProgress) isSuccessful() bool {
+	for _, state := range jp.states {
+		if state != jobProgressSuccess {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasFailures tells if the job failed or timed out on any client
+func (jp *jobProgress) hasFailures() bool {
+	for _, state := range jp.states {
+		if state == jobProgressFailed || state == jobProgressTimedOut {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (jp *jobProgress) message(title string) string {
+	lines := make([]string, 0, len(jp.clientIDs)+1)
+	lines = append(lines, fmt.Sprintf("%s [%d/%d]", title, jp.countFinished(), len(jp.clientIDs)))
+	for _, id := range jp.clientIDs {
+		name := id
+		if jp.names[id] != "" {
+			name = fmt.Sprintf("%s (%s)", jp.names[id], id)
+		}
+		lines = append(lines, name+"\t"+jp.states[id])
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// summary gives the result of each client, clients without finished jobs are unreachable
+func (jp *jobProgress) summary() *models.JobsSummary {
+	summary := &models.JobsSummary{
+		Clients: make([]*models.JobSummaryClient, 0, len(jp.clientIDs)),
+	}
+
+	var startedAt, finishedAt time.Time
+	for _, id := range jp.clientIDs {
+		summaryClient := &models.JobSummaryClient{
+			ClientID:   id,
+			ClientName: jp.names[id],
+		}
+		summary.Clients = append(summary.Clients, summaryClient)
+
+		switch jp.states[id] {
+		case jobProgressPending, jobProgressRunning:
+			summaryClient.Result = models.JobResultUnreachable
+			continue
+		case jobProgressSuccess:
+			summaryClient.Result = models.JobResultSucceeded
+		case jobProgressTimedOut:
+			summaryClient.Result = models.JobResultTimedOut
+		default:
+			summaryClient.Result = models.JobResultFailed
+		}
+
+		j := jp.jobs[id]
+		summaryClient.Error = j.Error
+		if j.StartedAt.IsZero() || j.FinishedAt.IsZero() {
+			continue
+		}
+		summaryClient.Duration = j.FinishedAt.Sub(j.StartedAt)
+		if startedAt.IsZero() || j.StartedAt.Before(startedAt) {
+			startedAt = j.StartedAt
+		}
+		if j.FinishedAt.After(finishedAt) {
+			finishedAt = j.FinishedAt
+		}
+	}
+
+	if !startedAt.IsZero() {
+		summary.Duration = finishedAt.Sub(startedAt)
+	}
+
+	return summary
+}
@@ -0,0 +1,314 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+const (
+	JobStatus           = "status"
+	JobsSince           = "since"
+	JobsUntil           = "until"
+	WaitInterval        = "interval"
+	DefaultWaitInterval = 2 * time.Second
+)
+
+type JobsAPI interface {
+	ClientJobs(ctx context.Context, clientID, status string) (*api.JobsResponse, error)
+	ClientJob(ctx context.Context, clientID, jid string) (*api.JobResponse, error)
+	MultiClientJobs(ctx context.Context) (*api.MultiJobsResponse, error)
+	MultiClientJob(ctx context.Context, jid string) (*api.MultiJobResponse, error)
+}
+
+type JobsRenderer interface {
+	RenderJob(j *models.Job) error
+	RenderJobs(jobs []*models.Job) error
+	RenderMultiJobs(multiJobs []*models.MultiJob) error
+	RenderMultiJob(multiJob *models.MultiJob) error
+}
+
+type JobsController struct {
+	Rport        JobsAPI
+	ClientSearch ClientSearch
+	JobRenderer  JobsRenderer
+}
+
+type timeRange struct {
+	since time.Time
+	until time.Time
+}
+
+func (tr timeRange) contains(t time.Time) bool {
+	if !tr.since.IsZero() && t.Before(tr.since) {
+		return false
+	}
+
+	if !tr.until.IsZero() && t.After(tr.until) {
+		return false
+	}
+
+	return true
+}
+
+// List renders jobs of the clients given in params or multi client jobs if no clients are given
+func (jc *JobsController) List(ctx context.Context, params *options.ParameterBag) error {
+	tr, err := jc.readTimeRange(params, time.Now())
+	if err != nil {
+		return err
+	}
+
+	status := params.ReadString(JobStatus, "")
+	if params.ReadString(ClientIDs, "") == "" && params.ReadString(ClientNameFlag, "") == "" {
+		if status != "" {
+			return errors.New("status filter can be applied to jobs of specific clients only, please provide client ids or names")
+		}
+		return jc.listMultiClientJobs(ctx, tr)
+	}
+
+	clients, err := jc.resolveClients(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	jobs := make([]*models.Job, 0)
+	for _, cl := range clients {
+		jobsResp, e := jc.Rport.ClientJobs(ctx, cl.ID, status)
+		if e != nil {
+			return e
+		}
+
+		for _, j := range jobsResp.Data {
+			if !tr.contains(jobTime(j)) {
+				continue
+			}
+			if j.ClientID == "" {
+				j.ClientID = cl.ID
+			}
+			if j.ClientName == "" {
+				j.ClientName = cl.Name
+			}
+			jobs = append(jobs, j)
+		}
+	}
+
+	sort.SliceStable(jobs, func(i, k int) bool {
+		return jobTime(jobs[i]).After(jobTime(jobs[k]))
+	})
+
+	return jc.JobRenderer.RenderJobs(jobs)
+}
+
+func (jc *JobsController) listMultiClientJobs(ctx context.Context, tr timeRange) error {
+	multiJobsResp, err := jc.Rport.MultiClientJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	multiJobs := make([]*models.MultiJob, 0, len(multiJobsResp.Data))
+	for _, mj := range multiJobsResp.Data {
+		if tr.contains(mj.StartedAt) {
+			multiJobs = append(multiJobs, mj)
+		}
+	}
+
+	return jc.JobRenderer.RenderMultiJobs(multiJobs)
+}
+
+// Get renders a job of a client if client id or name is given, otherwise a multi client job with all its jobs
+func (jc *JobsController) Get(ctx context.Context, jid string, params *options.ParameterBag) error {
+	if jid == "" {
+		return errors.New("no job id provided")
+	}
+
+	clientID := params.ReadString(ClientID, "")
+	clientName := params.ReadString(ClientNameFlag, "")
+	if clientID == "" && clientName == "" {
+		multiJobResp, err := jc.Rport.MultiClientJob(ctx, jid)
+		if err != nil {
+			return err
+		}
+
+		return jc.JobRenderer.RenderMultiJob(multiJobResp.Data)
+	}
+
+	if clientID == "" {
+		cl, err := jc.ClientSearch.FindOne(ctx, clientName, params)
+		if err != nil {
+			return err
+		}
+		clientID = cl.ID
+		clientName = cl.Name
+	}
+
+	jobResp, err := jc.Rport.ClientJob(ctx, clientID, jid)
+	if err != nil {
+		return err
+	}
+
+	job := jobResp.Data
+	if job != nil && job.ClientName == "" {
+		job.ClientName = clientName
+	}
+
+	return jc.JobRenderer.RenderJob(job)
+}
+
+// Wait polls a multi client job and renders results of its jobs as soon as they are finished
+func (jc *JobsController) Wait(ctx context.Context, jid string, params *options.ParameterBag) error {
+	if jid == "" {
+		return errors.New("no job id provided")
+	}
+
+	interval, err := time.ParseDuration(params.ReadString(WaitInterval, DefaultWaitInterval.String()))
+	if err != nil {
+		return fmt.Errorf("invalid %s value: %v", WaitInterval, err)
+	}
+
+	renderedJobs := map[string]bool{}
+	for {
+		multiJobResp, err := jc.Rport.MultiClientJob(ctx, jid)
+		if err != nil {
+			return err
+		}
+
+		multiJob := multiJobResp.Data
+		if multiJob == nil {
+			return fmt.Errorf("no data received for the job %s", jid)
+		}
+
+		for _, j := range multiJob.Jobs {
+			if j.Status == models.JobStatusRunning || renderedJobs[j.Jid] {
+				continue
+			}
+
+			err = jc.JobRenderer.RenderJob(j)
+			if err != nil {
+				return err
+			}
+			renderedJobs[j.Jid] = true
+		}
+
+		if isMultiJobFinished(multiJob) {
+			return nil
+		}
+
+		logrus.Debug(waitingMsg)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func isMultiJobFinished(multiJob *models.MultiJob) bool {
+	if multiJob.FinishedAt != nil && !multiJob.FinishedAt.IsZero() {
+		return true
+	}
+
+	hasFailedJobs := false
+	for _, j := range multiJob.Jobs {
+		if j.Status == models.JobStatusRunning {
+			return false
+		}
+		if j.Status == models.JobStatusFailed {
+			hasFailedJobs = true
+		}
+	}
+
+	// clients of groups are not known in advance, so a concurrent job is finished once it has jobs and all of them are finished,
+	// a sequential job gets the jobs of the next clients only later, so it's finished once the server sets finished_at
+	if len(multiJob.GroupIDs) > 0 || len(multiJob.ClientIDs) == 0 {
+		return multiJob.Concurrent && len(multiJob.Jobs) > 0
+	}
+
+	if len(multiJob.Jobs) >= len(multiJob.ClientIDs) {
+		return true
+	}
+
+	// remaining clients are skipped by the server if a sequential job fails with abort on error
+	return hasFailedJobs && multiJob.AbortOnErr && !multiJob.Concurrent
+}
+
+func (jc *JobsController) resolveClients(ctx context.Context, params *options.ParameterBag) ([]*models.Client, error) {
+	clientIDs := params.ReadString(ClientIDs, "")
+	if clientIDs != "" {
+		clients := make([]*models.Client, 0)
+		for _, clientID := range strings.Split(clientIDs, ",") {
+			clients = append(clients, &models.Client{ID: strings.TrimSpace(clientID)})
+		}
+		return clients, nil
+	}
+
+	clientName := params.ReadString(ClientNameFlag, "")
+	clients, err := jc.ClientSearch.Search(ctx, clientName, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("unknown client(s) '%s'", clientName)
+	}
+
+	return clients, nil
+}
+
+func (jc *JobsController) readTimeRange(params *options.ParameterBag, now time.Time) (tr timeRange, err error) {
+	tr.since, err = parseTimeParam(params.ReadString(JobsSince, ""), now, false)
+	if err != nil {
+		return tr, fmt.Errorf("invalid %s value: %v", JobsSince, err)
+	}
+
+	tr.until, err = parseTimeParam(params.ReadString(JobsUntil, ""), now, true)
+	if err != nil {
+		return tr, fmt.Errorf("invalid %s value: %v", JobsUntil, err)
+	}
+
+	return tr, nil
+}
+
+// parseTimeParam accepts a duration relative to now, e.g. 24h, an RFC3339 time or a date in the YYYY-MM-DD format,
+// a date is the start of the day or its end if isEndOfRange is true, so that the jobs of the day are included
+func parseTimeParam(val string, now time.Time, isEndOfRange bool) (time.Time, error) {
+	if val == "" {
+		return time.Time{}, nil
+	}
+
+	d, err := time.ParseDuration(val)
+	if err == nil {
+		return now.Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, val)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.ParseInLocation("2006-01-02", val, now.Location())
+	if err == nil {
+		if isEndOfRange {
+			return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("'%s' is neither a duration like 24h nor a time like 2021-01-01T00:00:00Z or 2021-01-01", val)
+}
+
+func jobTime(j *models.Job) time.Time {
+	if !j.StartedAt.IsZero() {
+		return j.StartedAt
+	}
+
+	return j.FinishedAt
+}
@@ -0,0 +1,310 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+type JobsAPIMock struct {
+	mock.Mock
+}
+
+func (jam *JobsAPIMock) ClientJobs(ctx context.Context, clientID, status string) (*api.JobsResponse, error) {
+	args := jam.Called(ctx, clientID, status)
+
+	return args.Get(0).(*api.JobsResponse), args.Error(1)
+}
+
+func (jam *JobsAPIMock) ClientJob(ctx context.Context, clientID, jid string) (*api.JobResponse, error) {
+	args := jam.Called(ctx, clientID, jid)
+
+	return args.Get(0).(*api.JobResponse), args.Error(1)
+}
+
+func (jam *JobsAPIMock) MultiClientJobs(ctx context.Context) (*api.MultiJobsResponse, error) {
+	args := jam.Called(ctx)
+
+	return args.Get(0).(*api.MultiJobsResponse), args.Error(1)
+}
+
+func (jam *JobsAPIMock) MultiClientJob(ctx context.Context, jid string) (*api.MultiJobResponse, error) {
+	args := jam.Called(ctx, jid)
+
+	return args.Get(0).(*api.MultiJobResponse), args.Error(1)
+}
+
+type JobsRendererMock struct {
+	mock.Mock
+}
+
+func (jrm *JobsRendererMock) RenderJob(j *models.Job) error {
+	return jrm.Called(j).Error(0)
+}
+
+func (jrm *JobsRendererMock) RenderJobs(jobs []*models.Job) error {
+	return jrm.Called(jobs).Error(0)
+}
+
+func (jrm *JobsRendererMock) RenderMultiJobs(multiJobs []*models.MultiJob) error {
+	return jrm.Called(multiJobs).Error(0)
+}
+
+func (jrm *JobsRendererMock) RenderMultiJob(multiJob *models.MultiJob) error {
+	return jrm.Called(multiJob).Error(0)
+}
+
+func parseTestTime(t *testing.T, val string) time.Time {
+	res, err := time.Parse(time.RFC3339, val)
+	require.NoError(t, err)
+
+	return res
+}
+
+func TestListClientJobs(t *testing.T) {
+	ctx := context.Background()
+
+	apiMock := &JobsAPIMock{}
+	apiMock.On("ClientJobs", ctx, "cl1", "failed").Return(&api.JobsResponse{Data: []*models.Job{
+		{Jid: "j1", StartedAt: parseTestTime(t, "2021-01-01T10:00:00Z")},
+		{Jid: "j2", StartedAt: parseTestTime(t, "2021-01-03T10:00:00Z")},
+	}}, nil)
+	apiMock.On("ClientJobs", ctx, "cl2", "failed").Return(&api.JobsResponse{Data: []*models.Job{
+		{Jid: "j3", StartedAt: parseTestTime(t, "2021-01-02T10:00:00Z")},
+	}}, nil)
+
+	renderMock := &JobsRendererMock{}
+	renderMock.On("RenderJobs", mock.Anything).Return(nil)
+
+	jc := &JobsController{
+		Rport: apiMock,
+		ClientSearch: &ClientSearchMock{
+			clientsToGive: []*models.Client{
+				{ID: "cl1", Name: "client 1"},
+				{ID: "cl2", Name: "client 2"},
+			},
+		},
+		JobRenderer: renderMock,
+	}
+
+	err := jc.List(ctx, config.FromValues(map[string]string{
+		ClientNameFlag: "client",
+		JobStatus:      "failed",
+		JobsSince:      "2021-01-02",
+	}))
+	require.NoError(t, err)
+
+	renderMock.AssertCalled(t, "RenderJobs", []*models.Job{
+		{Jid: "j2", StartedAt: parseTestTime(t, "2021-01-03T10:00:00Z"), ClientID: "cl1", ClientName: "client 1"},
+		{Jid: "j3", StartedAt: parseTestTime(t, "2021-01-02T10:00:00Z"), ClientID: "cl2", ClientName: "client 2"},
+	})
+}
+
+func TestListMultiClientJobs(t *testing.T) {
+	ctx := context.Background()
+
+	apiMock := &JobsAPIMock{}
+	apiMock.On("MultiClientJobs", ctx).Return(&api.MultiJobsResponse{Data: []*models.MultiJob{
+		{Jid: "mj1", StartedAt: parseTestTime(t, "2021-01-01T10:00:00Z")},
+		{Jid: "mj2", StartedAt: parseTestTime(t, "2021-01-03T10:00:00Z")},
+	}}, nil)
+
+	renderMock := &JobsRendererMock{}
+	renderMock.On("RenderMultiJobs", mock.Anything).Return(nil)
+
+	jc := &JobsController{
+		Rport:       apiMock,
+		JobRenderer: renderMock,
+	}
+
+	err := jc.List(ctx, config.FromValues(map[string]string{
+		JobsUntil: "2021-01-02T00:00:00Z",
+	}))
+	require.NoError(t, err)
+
+	renderMock.AssertCalled(t, "RenderMultiJobs", []*models.MultiJob{
+		{Jid: "mj1", StartedAt: parseTestTime(t, "2021-01-01T10:00:00Z")},
+	})
+
+	err = jc.List(ctx, config.FromValues(map[string]string{JobStatus: "failed"}))
+	assert.EqualError(t, err, "status filter can be applied to jobs of specific clients only, please provide client ids or names")
+
+	err = jc.List(ctx, config.FromValues(map[string]string{JobsSince: "yesterday"}))
+	assert.Error(t, err)
+}
+
+func TestGetJob(t *testing.T) {
+	ctx := context.Background()
+
+	apiMock := &JobsAPIMock{}
+	apiMock.On("MultiClientJob", ctx, "mj1").Return(&api.MultiJobResponse{Data: &models.MultiJob{Jid: "mj1"}}, nil)
+	apiMock.On("ClientJob", ctx, "cl1", "j1").Return(&api.JobResponse{Data: &models.Job{Jid: "j1"}}, nil)
+
+	renderMock := &JobsRendererMock{}
+	renderMock.On("RenderMultiJob", mock.Anything).Return(nil)
+	renderMock.On("RenderJob", mock.Anything).Return(nil)
+
+	jc := &JobsController{
+		Rport: apiMock,
+		ClientSearch: &ClientSearchMock{
+			clientsToGive: []*models.Client{{ID: "cl1", Name: "client 1"}},
+		},
+		JobRenderer: renderMock,
+	}
+
+	err := jc.Get(ctx, "mj1", config.FromValues(map[string]string{}))
+	require.NoError(t, err)
+	renderMock.AssertCalled(t, "RenderMultiJob", &models.MultiJob{Jid: "mj1"})
+
+	err = jc.Get(ctx, "j1", config.FromValues(map[string]string{ClientNameFlag: "client 1"}))
+	require.NoError(t, err)
+	renderMock.AssertCalled(t, "RenderJob", &models.Job{Jid: "j1", ClientName: "client 1"})
+}
+
+func TestParseTimeParam(t *testing.T) {
+	now := parseTestTime(t, "2021-01-02T10:00:00Z")
+
+	actualTime, err := parseTimeParam("24h", now, false)
+	require.NoError(t, err)
+	assert.Equal(t, parseTestTime(t, "2021-01-01T10:00:00Z"), actualTime)
+
+	actualTime, err = parseTimeParam("2021-01-01T11:00:00Z", now, true)
+	require.NoError(t, err)
+	assert.Equal(t, parseTestTime(t, "2021-01-01T11:00:00Z"), actualTime)
+
+	actualTime, err = parseTimeParam("2021-01-01", now, false)
+	require.NoError(t, err)
+	assert.Equal(t, parseTestTime(t, "2021-01-01T00:00:00Z"), actualTime)
+
+	actualTime, err = parseTimeParam("2021-01-01", now, true)
+	require.NoError(t, err)
+	assert.Equal(t, parseTestTime(t, "2021-01-01T23:59:59.999999999Z"), actualTime)
+
+	actualTime, err = parseTimeParam("", now, true)
+	require.NoError(t, err)
+	assert.True(t, actualTime.IsZero())
+}
+
+func TestWaitForMultiClientJob(t *testing.T) {
+	ctx := context.Background()
+
+	runningJob := &api.MultiJobResponse{Data: &models.MultiJob{
+		Jid:       "mj1",
+		ClientIDs: []string{"cl1", "cl2"},
+		Jobs: []*models.Job{
+			{Jid: "j1", ClientID: "cl1", Status: models.JobStatusSuccessful},
+			{Jid: "j2", ClientID: "cl2", Status: models.JobStatusRunning},
+		},
+	}}
+	finishedJob := &api.MultiJobResponse{Data: &models.MultiJob{
+		Jid:       "mj1",
+		ClientIDs: []string{"cl1", "cl2"},
+		Jobs: []*models.Job{
+			{Jid: "j1", ClientID: "cl1", Status: models.JobStatusSuccessful},
+			{Jid: "j2", ClientID: "cl2", Status: models.JobStatusFailed},
+		},
+	}}
+
+	apiMock := &JobsAPIMock{}
+	apiMock.On("MultiClientJob", ctx, "mj1").Return(runningJob, nil).Once()
+	apiMock.On("MultiClientJob", ctx, "mj1").Return(finishedJob, nil).Once()
+
+	renderMock := &JobsRendererMock{}
+	renderMock.On("RenderJob", mock.Anything).Return(nil)
+
+	jc := &JobsController{
+		Rport:       apiMock,
+		JobRenderer: renderMock,
+	}
+
+	err := jc.Wait(ctx, "mj1", config.FromValues(map[string]string{WaitInterval: "1ms"}))
+	require.NoError(t, err)
+
+	apiMock.AssertNumberOfCalls(t, "MultiClientJob", 2)
+	renderMock.AssertNumberOfCalls(t, "RenderJob", 2)
+	renderMock.AssertCalled(t, "RenderJob", finishedJob.Data.Jobs[1])
+}
+
+func TestWaitForMultiClientJobOfGroups(t *testing.T) {
+	ctx := context.Background()
+
+	startingJob := &api.MultiJobResponse{Data: &models.MultiJob{Jid: "mj1", GroupIDs: []string{"g1"}}}
+	// the job of the next client of a sequential run is not created yet
+	runningJob := &api.MultiJobResponse{Data: &models.MultiJob{
+		Jid:      "mj1",
+		GroupIDs: []string{"g1"},
+		Jobs: []*models.Job{
+			{Jid: "j1", ClientID: "cl1", Status: models.JobStatusSuccessful},
+		},
+	}}
+	finishedAt := time.Now()
+	finishedJob := &api.MultiJobResponse{Data: &models.MultiJob{
+		Jid:        "mj1",
+		GroupIDs:   []string{"g1"},
+		FinishedAt: &finishedAt,
+		Jobs: []*models.Job{
+			{Jid: "j1", ClientID: "cl1", Status: models.JobStatusSuccessful},
+			{Jid: "j2", ClientID: "cl2", Status: models.JobStatusSuccessful},
+		},
+	}}
+
+	apiMock := &JobsAPIMock{}
+	apiMock.On("MultiClientJob", ctx, "mj1").Return(startingJob, nil).Once()
+	apiMock.On("MultiClientJob", ctx, "mj1").Return(runningJob, nil).Once()
+	apiMock.On("MultiClientJob", ctx, "mj1").Return(finishedJob, nil).Once()
+
+	renderMock := &JobsRendererMock{}
+	renderMock.On("RenderJob", mock.Anything).Return(nil)
+
+	jc := &JobsController{
+		Rport:       apiMock,
+		JobRenderer: renderMock,
+	}
+
+	err := jc.Wait(ctx, "mj1", config.FromValues(map[string]string{WaitInterval: "1ms"}))
+	require.NoError(t, err)
+
+	apiMock.AssertNumberOfCalls(t, "MultiClientJob", 3)
+	renderMock.AssertNumberOfCalls(t, "RenderJob", 2)
+}
+
+func TestIsMultiJobFinished(t *testing.T) {
+	finishedAt := time.Now()
+	assert.False(t, isMultiJobFinished(&models.MultiJob{GroupIDs: []string{"g1"}}))
+	assert.False(t, isMultiJobFinished(&models.MultiJob{
+		ClientIDs: []string{"cl1"},
+		GroupIDs:  []string{"g1"},
+		Jobs:      []*models.Job{{Status: models.JobStatusRunning}, {Status: models.JobStatusSuccessful}},
+	}))
+	assert.False(t, isMultiJobFinished(&models.MultiJob{
+		GroupIDs: []string{"g1"},
+		Jobs:     []*models.Job{{Status: models.JobStatusSuccessful}},
+	}))
+	assert.True(t, isMultiJobFinished(&models.MultiJob{
+		GroupIDs:   []string{"g1"},
+		Concurrent: true,
+		Jobs:       []*models.Job{{Status: models.JobStatusSuccessful}},
+	}))
+	assert.True(t, isMultiJobFinished(&models.MultiJob{GroupIDs: []string{"g1"}, FinishedAt: &finishedAt}))
+
+	assert.False(t, isMultiJobFinished(&models.MultiJob{ClientIDs: []string{"cl1", "cl2"}}))
+	assert.True(t, isMultiJobFinished(&models.MultiJob{
+		ClientIDs:  []string{"cl1", "cl2"},
+		AbortOnErr: true,
+		Jobs:       []*models.Job{{Status: models.JobStatusFailed}},
+	}))
+	assert.False(t, isMultiJobFinished(&models.MultiJob{
+		ClientIDs:  []string{"cl1", "cl2"},
+		AbortOnErr: true,
+		Concurrent: true,
+		Jobs:       []*models.Job{{Status: models.JobStatusFailed}},
+	}))
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import "fmt"
+
+// namedEntry is an entry of a server list which can be referenced either by its id or by its name
+type namedEntry struct {
+	ID   string
+	Name string
+}
+
+// findIDByIDOrName gives the id of the entry with the given id or name, as names are not unique,
+// a name of multiple entries is rejected, kind is the entry type used in the errors, e.g. "script"
+func findIDByIDOrName(entries []namedEntry, idOrName, kind string) (string, error) {
+	var idsByName []string
+	for _, entry := range entries {
+		if entry.ID == idOrName {
+			return entry.ID, nil
+		}
+		if entry.Name == idOrName {
+			idsByName = append(idsByName, entry.ID)
+		}
+	}
+
+	switch len(idsByName) {
+	case 0:
+		return "", fmt.Errorf("%s '%s' not found", kind, idOrName)
+	case 1:
+		return idsByName[0], nil
+	default:
+		return "", fmt.Errorf("%d %ss are named '%s', use the %s id instead", len(idsByName), kind, idOrName, kind)
+	}
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindIDByIDOrName(t *testing.T) {
+	entries := []namedEntry{
+		{ID: "4", Name: "1"},
+		{ID: "1", Name: "uptime"},
+		{ID: "2", Name: "cleanup"},
+		{ID: "3", Name: "cleanup"},
+	}
+
+	testCases := []struct {
+		name          string
+		idOrName      string
+		expectedID    string
+		expectedError string
+	}{
+		{
+			name:       "by id",
+			idOrName:   "2",
+			expectedID: "2",
+		},
+		{
+			name:       "by name",
+			idOrName:   "uptime",
+			expectedID: "1",
+		},
+		{
+			name:       "id before name",
+			idOrName:   "1",
+			expectedID: "1",
+		},
+		{
+			name:          "unknown",
+			idOrName:      "reboot",
+			expectedError: "script 'reboot' not found",
+		},
+		{
+			name:          "ambiguous name",
+			idOrName:      "cleanup",
+			expectedError: "2 scripts are named 'cleanup', use the script id instead",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := findIDByIDOrName(entries, tc.idOrName, "script")
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedID, id)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+const (
+	UseProfile = "use"
+)
+
+type ProfileRenderer interface {
+	RenderProfiles(profiles []*models.Profile) error
+	RenderStatus(s output.KvProvider) error
+}
+
+type ProfileController struct {
+	ProfilesReader  func() (*config.Profiles, error)
+	ProfilesWriter  func(profiles *config.Profiles) error
+	SecretsDeleter  func(values map[string]interface{}) error
+	ProfileRenderer ProfileRenderer
+}
+
+func (pc *ProfileController) List(ctx context.Context) error {
+	profiles, err := pc.ProfilesReader()
+	if err != nil {
+		return err
+	}
+
+	currentProfile := profiles.Resolve("")
+	profilesToRender := make([]*models.Profile, 0, len(profiles.Profiles))
+	for _, name := range profiles.Names() {
+		values, _ := profiles.Get(name)
+		serverURL, _ := values[config.ServerURL].(string)
+		profilesToRender = append(profilesToRender, &models.Profile{
+			Name:      name,
+			ServerURL: serverURL,
+			IsCurrent: name == currentProfile,
+		})
+	}
+
+	return pc.ProfileRenderer.RenderProfiles(profilesToRender)
+}
+
+// Add creates a profile from the provided params only, so the server url is required even if the current profile has one
+func (pc *ProfileController) Add(ctx context.Context, name string, params *options.ParameterBag) error {
+	serverURL := params.ReadString(config.ServerURL, "")
+	if serverURL == "" {
+		return fmt.Errorf("no server url provided, use --%s", config.ServerURL)
+	}
+
+	profiles, err := pc.ProfilesReader()
+	if err != nil {
+		return err
+	}
+
+	if _, exists := profiles.Get(name); exists {
+		return fmt.Errorf("profile '%s' already exists", name)
+	}
+
+	values := map[string]interface{}{
+		config.ServerURL: serverURL,
+	}
+	secretStore := params.ReadString(config.SecretStoreFlag, "")
+	if secretStore != "" && secretStore != config.SecretStorePlain {
+		err = config.ValidateSecretStore(config.SecretStoreFlag, secretStore)
+		if err != nil {
+			return err
+		}
+		values[config.SecretStoreKey] = secretStore
+	}
+	profiles.Set(name, values)
+
+	if params.ReadBool(UseProfile, false) {
+		err = profiles.Use(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return pc.writeAndRenderStatus(profiles, fmt.Sprintf("Profile '%s' added, run init with --profile %s to login", name, name))
+}
+
+func (pc *ProfileController) Use(ctx context.Context, name string) error {
+	profiles, err := pc.ProfilesReader()
+	if err != nil {
+		return err
+	}
+
+	err = profiles.Use(name)
+	if err != nil {
+		return err
+	}
+
+	return pc.writeAndRenderStatus(profiles, fmt.Sprintf("Switched to profile '%s'", name))
+}
+
+func (pc *ProfileController) Remove(ctx context.Context, name string) error {
+	profiles, err := pc.ProfilesReader()
+	if err != nil {
+		return err
+	}
+
+	values, _ := profiles.Get(name)
+	err = profiles.Remove(name)
+	if err != nil {
+		return err
+	}
+
+	err = pc.writeAndRenderStatus(profiles, fmt.Sprintf("Profile '%s' removed", name))
+	if err != nil {
+		return err
+	}
+
+	if pc.SecretsDeleter != nil {
+		err = pc.SecretsDeleter(values)
+		if err != nil {
+			logrus.Warnf("failed to delete secrets of profile '%s': %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (pc *ProfileController) Rename(ctx context.Context, oldName, newName string) error {
+	profiles, err := pc.ProfilesReader()
+	if err != nil {
+		return err
+	}
+
+	err = profiles.Rename(oldName, newName)
+	if err != nil {
+		return err
+	}
+
+	return pc.writeAndRenderStatus(profiles, fmt.Sprintf("Profile '%s' renamed to '%s'", oldName, newName))
+}
+
+func (pc *ProfileController) writeAndRenderStatus(profiles *config.Profiles, status string) error {
+	err := pc.ProfilesWriter(profiles)
+	if err != nil {
+		return err
+	}
+
+	return pc.ProfileRenderer.RenderStatus(&models.OperationStatus{Status: status})
+}
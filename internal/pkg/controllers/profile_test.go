@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+type ProfileRendererMock struct {
+	mock.Mock
+}
+
+func (prm *ProfileRendererMock) RenderProfiles(profiles []*models.Profile) error {
+	args := prm.Called(profiles)
+
+	return args.Error(0)
+}
+
+func (prm *ProfileRendererMock) RenderStatus(s output.KvProvider) error {
+	args := prm.Called(s)
+
+	return args.Error(0)
+}
+
+func buildProfilesStub() *config.Profiles {
+	profiles := config.NewProfiles()
+	profiles.Set("prod", map[string]interface{}{config.ServerURL: "https://prod:3000", config.Token: "123"})
+	profiles.Set("staging", map[string]interface{}{config.ServerURL: "https://staging:3000"})
+
+	return profiles
+}
+
+func TestListProfiles(t *testing.T) {
+	renderMock := &ProfileRendererMock{}
+	renderMock.On("RenderProfiles", mock.Anything).Return(nil)
+
+	pc := &ProfileController{
+		ProfilesReader: func() (*config.Profiles, error) {
+			return buildProfilesStub(), nil
+		},
+		ProfileRenderer: renderMock,
+	}
+
+	err := pc.List(context.Background())
+	require.NoError(t, err)
+
+	renderMock.AssertCalled(t, "RenderProfiles", []*models.Profile{
+		{
+			Name:      "prod",
+			ServerURL: "https://prod:3000",
+			IsCurrent: true,
+		},
+		{
+			Name:      "staging",
+			ServerURL: "https://staging:3000",
+		},
+	})
+}
+
+func TestAddProfile(t *testing.T) {
+	renderMock := &ProfileRendererMock{}
+	renderMock.On("RenderStatus", mock.Anything).Return(nil)
+
+	var writtenProfiles *config.Profiles
+	pc := &ProfileController{
+		ProfilesReader: func() (*config.Profiles, error) {
+			return buildProfilesStub(), nil
+		},
+		ProfilesWriter: func(profiles *config.Profiles) error {
+			writtenProfiles = profiles
+			return nil
+		},
+		ProfileRenderer: renderMock,
+	}
+
+	err := pc.Add(context.Background(), "dev", config.FromValues(map[string]string{
+		config.ServerURL: "http://localhost:3000",
+		UseProfile:       "1",
+	}))
+	require.NoError(t, err)
+
+	require.NotNil(t, writtenProfiles)
+	assert.Equal(t, "dev", writtenProfiles.Current)
+	values, ok := writtenProfiles.Get("dev")
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{config.ServerURL: "http://localhost:3000"}, values)
+
+	renderMock.AssertCalled(t, "RenderStatus", &models.OperationStatus{
+		Status: "Profile 'dev' added, run init with --profile dev to login",
+	})
+
+	err = pc.Add(context.Background(), "prod", config.FromValues(map[string]string{
+		config.ServerURL: "http://localhost:3000",
+	}))
+	assert.EqualError(t, err, "profile 'prod' already exists")
+
+	err = pc.Add(context.Background(), "dev2", config.FromValues(map[string]string{}))
+	assert.EqualError(t, err, "no server url provided, use --server")
+}
+
+func TestUseRenameRemoveProfile(t *testing.T) {
+	renderMock := &ProfileRendererMock{}
+	renderMock.On("RenderStatus", mock.Anything).Return(nil)
+
+	profiles := buildProfilesStub()
+	deletedSecrets := []map[string]interface{}{}
+	pc := &ProfileController{
+		ProfilesReader: func() (*config.Profiles, error) {
+			return profiles, nil
+		},
+		ProfilesWriter: func(p *config.Profiles) error {
+			profiles = p
+			return nil
+		},
+		SecretsDeleter: func(values map[string]interface{}) error {
+			deletedSecrets = append(deletedSecrets, values)
+			return nil
+		},
+		ProfileRenderer: renderMock,
+	}
+
+	ctx := context.Background()
+
+	err := pc.Use(ctx, "staging")
+	require.NoError(t, err)
+	assert.Equal(t, "staging", profiles.Current)
+
+	err = pc.Rename(ctx, "staging", "stage")
+	require.NoError(t, err)
+	assert.Equal(t, "stage", profiles.Current)
+
+	err = pc.Remove(ctx, "stage")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod"}, profiles.Names())
+	assert.Equal(t, "prod", profiles.Current)
+	assert.Equal(t, []map[string]interface{}{{config.ServerURL: "https://staging:3000"}}, deletedSecrets)
+
+	err = pc.Use(ctx, "unknown")
+	assert.EqualError(t, err, "unknown profile 'unknown'")
+
+	renderMock.AssertCalled(t, "RenderStatus", &models.OperationStatus{Status: "Switched to profile 'staging'"})
+	renderMock.AssertCalled(t, "RenderStatus", &models.OperationStatus{Status: "Profile 'staging' renamed to 'stage'"})
+	renderMock.AssertCalled(t, "RenderStatus", &models.OperationStatus{Status: "Profile 'stage' removed"})
+}
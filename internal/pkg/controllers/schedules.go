@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+)
+
+const (
+	ScheduleCron         = "schedule"
+	ScheduleNewName      = "new-name"
+	ScheduleTimeout      = "timeout-sec"
+	ScheduleTimezone     = "timezone"
+	ScheduleNextRunCount = 5
+)
+
+type SchedulesAPI interface {
+	Schedules(ctx context.Context) (*api.SchedulesResponse, error)
+	Schedule(ctx context.Context, scheduleID string) (*api.ScheduleResponse, error)
+	CreateSchedule(ctx context.Context, schedule *models.Schedule) (*api.ScheduleResponse, error)
+	UpdateSchedule(ctx context.Context, schedule *models.Schedule) (*api.ScheduleResponse, error)
+	DeleteSchedule(ctx context.Context, scheduleID string) error
+}
+
+type ScheduleRenderer interface {
+	RenderSchedules(schedules []*models.Schedule) error
+	RenderSchedule(schedule *models.Schedule) error
+	RenderStatus(s output.KvProvider) error
+}
+
+// SchedulesController manages commands and scripts which the rport server executes periodically,
+// the next runs of each schedule are calculated from its cron expression in the timezone of the server
+type SchedulesController struct {
+	*ExecutionHelper
+	Rport            SchedulesAPI
+	ScheduleRenderer ScheduleRenderer
+	// Timezone is the timezone in which the rport server evaluates the cron expressions, UTC if not set
+	Timezone *time.Location
+}
+
+func (sc *SchedulesController) List(ctx context.Context) error {
+	schedulesResp, err := sc.Rport.Schedules(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := sc.now()
+	for _, schedule := range schedulesResp.Data {
+		setNextRuns(schedule, now)
+	}
+
+	return sc.ScheduleRenderer.RenderSchedules(schedulesResp.Data)
+}
+
+func (sc *SchedulesController) Get(ctx context.Context, idOrName string) error {
+	schedule, err := sc.findSchedule(ctx, idOrName)
+	if err != nil {
+		return err
+	}
+
+	setNextRuns(schedule, sc.now())
+
+	return sc.ScheduleRenderer.RenderSchedule(schedule)
+}
+
+// Create adds a schedule of either a command or a script and renders it with a preview of its next runs
+func (sc *SchedulesController) Create(ctx context.Context, name string, params *options.ParameterBag) error {
+	schedule := &models.Schedule{
+		Name: name,
+		Details: models.WsScriptCommand{
+			TimeoutSec: DefaultCmdTimeoutSeconds,
+		},
+	}
+
+	err := applyScheduleParams(schedule, params)
+	if err != nil {
+		return err
+	}
+
+	scheduleResp, err := sc.Rport.CreateSchedule(ctx, schedule)
+	if err != nil {
+		return err
+	}
+
+	if scheduleResp.Data != nil && scheduleResp.Data.ID != "" {
+		schedule = scheduleResp.Data
+	}
+	setNextRuns(schedule, sc.now())
+
+	return sc.ScheduleRenderer.RenderSchedule(schedule)
+}
+
+// Update changes only the provided fields, providing a command or a script changes the type of the schedule
+func (sc *SchedulesController) Update(ctx context.Context, idOrName string, params *options.ParameterBag) error {
+	schedule, err := sc.findSchedule(ctx, idOrName)
+	if err != nil {
+		return err
+	}
+
+	if newName := params.ReadString(ScheduleNewName, ""); newName != "" {
+		schedule.Name = newName
+	}
+
+	err = applyScheduleParams(schedule, params)
+	if err != nil {
+		return err
+	}
+
+	_, err = sc.Rport.UpdateSchedule(ctx, schedule)
+	if err != nil {
+		return err
+	}
+
+	setNextRuns(schedule, sc.now())
+
+	return sc.ScheduleRenderer.RenderSchedule(schedule)
+}
+
+func (sc *SchedulesController) Delete(ctx context.Context, idOrName string) error {
+	schedule, err := sc.findSchedule(ctx, idOrName)
+	if err != nil {
+		return err
+	}
+
+	err = sc.Rport.DeleteSchedule(ctx, schedule.ID)
+	if err != nil {
+		return err
+	}
+
+	return sc.ScheduleRenderer.RenderStatus(&models.OperationStatus{
+		Status: fmt.Sprintf("Schedule '%s' deleted", schedule.Name),
+	})
+}
+
+// RunCopy starts an ad-hoc copy of the command or script of the schedule on its clients in background,
+// as rport has no api to trigger a schedule, the started job is not related to the schedule and its runs,
+// the results can be followed with the id of the started job
+func (sc *SchedulesController) RunCopy(ctx context.Context, idOrName string) error {
+	schedule, err := sc.findSchedule(ctx, idOrName)
+	if err != nil {
+		return err
+	}
+
+	wsCmd := schedule.Details
+	if schedule.Type == models.ScheduleTypeScript {
+		wsCmd.Command = ""
+	} else {
+		wsCmd.Script = ""
+	}
+
+	return sc.startDetached(ctx, &wsCmd)
+}
+
+// applyScheduleParams sets the provided fields of the schedule and validates the result
+func applyScheduleParams(schedule *models.Schedule, params *options.ParameterBag) error {
+	if cron := params.ReadString(ScheduleCron, ""); cron != "" {
+		schedule.Schedule = cron
+	}
+
+	cmd := params.ReadString(Command, "")
+	scriptFilePath := params.ReadString(Script, "")
+	if cmd != "" && scriptFilePath != "" {
+		return fmt.Errorf("--%s and --%s cannot be combined", Command, Script)
+	}
+
+	if cmd != "" {
+		schedule.Type = models.ScheduleTypeCommand
+		schedule.Details.Command = cmd
+		schedule.Details.Script = ""
+	}
+
+	if scriptFilePath != "" {
+		content, err := readScriptFile(scriptFilePath)
+		if err != nil {
+			return err
+		}
+		schedule.Type = models.ScheduleTypeScript
+		schedule.Details.Script = base64.StdEncoding.EncodeToString(content)
+		schedule.Details.Command = ""
+		schedule.Details.Interpreter = resolveInterpreter(scriptFilePath, content, params.ReadString(Interpreter, ""))
+	}
+
+	if clientIDs, found := params.Read(ClientIDs, ""); found {
+		schedule.Details.ClientIDs = splitIDs(fmt.Sprint(clientIDs))
+	}
+
+	if groupIDs, found := params.Read(GroupIDs, ""); found {
+		schedule.Details.GroupIDs = splitIDs(fmt.Sprint(groupIDs))
+	}
+
+	if interpreter, found := params.Read(Interpreter, ""); found {
+		schedule.Details.Interpreter = fmt.Sprint(interpreter)
+	}
+
+	if cwd, found := params.Read(Cwd, ""); found {
+		schedule.Details.Cwd = fmt.Sprint(cwd)
+	}
+
+	if _, found := params.Read(ScheduleTimeout, ""); found {
+		timeout, err := params.ReadRequiredInt(ScheduleTimeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid %s value, expected a positive number of seconds", ScheduleTimeout)
+		}
+		schedule.Details.TimeoutSec = timeout
+	}
+
+	if _, found := params.Read(IsSudo, false); found {
+		schedule.Details.IsSudo = params.ReadBool(IsSudo, false)
+	}
+
+	if _, found := params.Read(ExecConcurrently, false); found {
+		schedule.Details.ExecuteConcurrently = params.ReadBool(ExecConcurrently, false)
+	}
+
+	if _, found := params.Read(AbortOnError, false); found {
+		schedule.Details.AbortOnError = params.ReadBool(AbortOnError, false)
+	}
+
+	return validateSchedule(schedule)
+}
+
+func validateSchedule(schedule *models.Schedule) error {
+	if schedule.Schedule == "" {
+		return fmt.Errorf("no cron expression provided, use --%s", ScheduleCron)
+	}
+
+	_, err := utils.ParseCron(schedule.Schedule)
+	if err != nil {
+		return fmt.Errorf("invalid cron expression '%s': %v", schedule.Schedule, err)
+	}
+
+	if schedule.Type == "" {
+		return fmt.Errorf("either --%s or --%s should be provided", Command, Script)
+	}
+
+	if len(schedule.Details.ClientIDs) == 0 && len(schedule.Details.GroupIDs) == 0 {
+		return errors.New("no client ids nor group ids provided")
+	}
+
+	return nil
+}
+
+// now gives the current time in the timezone of the server, so the next runs are calculated and rendered in it
+func (sc *SchedulesController) now() time.Time {
+	if sc.Timezone == nil {
+		return time.Now().UTC()
+	}
+
+	return time.Now().In(sc.Timezone)
+}
+
+// setNextRuns calculates a preview of the next runs of the schedule in the timezone of the given time,
+// schedules with expressions which can't be parsed are left without it, as they might use a syntax supported only by the server
+func setNextRuns(schedule *models.Schedule, now time.Time) {
+	cron, err := utils.ParseCron(schedule.Schedule)
+	if err != nil {
+		return
+	}
+
+	schedule.NextRuns = cron.NextRuns(now, ScheduleNextRunCount)
+}
+
+// findSchedule gives a schedule by id or by name, as names are not unique, a name of multiple schedules is rejected
+func (sc *SchedulesController) findSchedule(ctx context.Context, idOrName string) (*models.Schedule, error) {
+	schedulesResp, err := sc.Rport.Schedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]namedEntry, 0, len(schedulesResp.Data))
+	for _, schedule := range schedulesResp.Data {
+		entries = append(entries, namedEntry{ID: schedule.ID, Name: schedule.Name})
+	}
+
+	scheduleID, err := findIDByIDOrName(entries, idOrName, "schedule")
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleResp, err := sc.Rport.Schedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheduleResp.Data == nil {
+		return nil, fmt.Errorf("schedule '%s' not found", idOrName)
+	}
+
+	return scheduleResp.Data, nil
+}
+
+func splitIDs(commaSeparatedIDs string) []string {
+	ids := []string{}
+	for _, id := range strings.Split(commaSeparatedIDs, ",") {
+		id = strings.TrimSpace(id)
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,326 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+type SchedulesAPIMock struct {
+	mock.Mock
+}
+
+func (sam *SchedulesAPIMock) Schedules(ctx context.Context) (*api.SchedulesResponse, error) {
+	args := sam.Called(ctx)
+
+	return args.Get(0).(*api.SchedulesResponse), args.Error(1)
+}
+
+func (sam *SchedulesAPIMock) Schedule(ctx context.Context, scheduleID string) (*api.ScheduleResponse, error) {
+	args := sam.Called(ctx, scheduleID)
+
+	return args.Get(0).(*api.ScheduleResponse), args.Error(1)
+}
+
+func (sam *SchedulesAPIMock) CreateSchedule(ctx context.Context, schedule *models.Schedule) (*api.ScheduleResponse, error) {
+	args := sam.Called(ctx, schedule)
+
+	return args.Get(0).(*api.ScheduleResponse), args.Error(1)
+}
+
+func (sam *SchedulesAPIMock) UpdateSchedule(ctx context.Context, schedule *models.Schedule) (*api.ScheduleResponse, error) {
+	args := sam.Called(ctx, schedule)
+
+	return args.Get(0).(*api.ScheduleResponse), args.Error(1)
+}
+
+func (sam *SchedulesAPIMock) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	args := sam.Called(ctx, scheduleID)
+
+	return args.Error(0)
+}
+
+type ScheduleRendererMock struct {
+	mock.Mock
+}
+
+func (srm *ScheduleRendererMock) RenderSchedules(schedules []*models.Schedule) error {
+	args := srm.Called(schedules)
+
+	return args.Error(0)
+}
+
+func (srm *ScheduleRendererMock) RenderSchedule(schedule *models.Schedule) error {
+	args := srm.Called(schedule)
+
+	return args.Error(0)
+}
+
+func (srm *ScheduleRendererMock) RenderStatus(s output.KvProvider) error {
+	args := srm.Called(s)
+
+	return args.Error(0)
+}
+
+func newSchedulesAPIMock(schedule *models.Schedule) *SchedulesAPIMock {
+	apiMock := &SchedulesAPIMock{}
+	apiMock.On("Schedules", mock.Anything).Return(&api.SchedulesResponse{Data: []*models.Schedule{
+		{ID: schedule.ID, Name: schedule.Name},
+	}}, nil)
+	apiMock.On("Schedule", mock.Anything, schedule.ID).Return(&api.ScheduleResponse{Data: schedule}, nil)
+
+	return apiMock
+}
+
+func TestCreateCommandSchedule(t *testing.T) {
+	apiMock := &SchedulesAPIMock{}
+	apiMock.On("CreateSchedule", mock.Anything, mock.Anything).Return(&api.ScheduleResponse{}, nil)
+
+	renderMock := &ScheduleRendererMock{}
+	renderMock.On("RenderSchedule", mock.Anything).Return(nil)
+
+	sc := &SchedulesController{Rport: apiMock, ScheduleRenderer: renderMock}
+
+	err := sc.Create(context.Background(), "cleanup", config.FromValues(map[string]string{
+		ScheduleCron:     "0 3 * * *",
+		Command:          "rm -rf /tmp/*",
+		ClientIDs:        "cl1, cl2",
+		ExecConcurrently: "true",
+	}))
+	require.NoError(t, err)
+
+	expectedSchedule := &models.Schedule{
+		Name:     "cleanup",
+		Schedule: "0 3 * * *",
+		Type:     models.ScheduleTypeCommand,
+		Details: models.WsScriptCommand{
+			ClientIDs:           []string{"cl1", "cl2"},
+			Command:             "rm -rf /tmp/*",
+			TimeoutSec:          DefaultCmdTimeoutSeconds,
+			ExecuteConcurrently: true,
+		},
+	}
+	apiMock.AssertCalled(t, "CreateSchedule", mock.Anything, mock.MatchedBy(func(s *models.Schedule) bool {
+		return s.Name == expectedSchedule.Name &&
+			s.Schedule == expectedSchedule.Schedule &&
+			s.Type == expectedSchedule.Type &&
+			assert.ObjectsAreEqual(expectedSchedule.Details, s.Details)
+	}))
+	renderMock.AssertCalled(t, "RenderSchedule", mock.MatchedBy(func(s *models.Schedule) bool {
+		return len(s.NextRuns) == ScheduleNextRunCount && s.NextRuns[0].Hour() == 3 && s.NextRuns[0].Location() == time.UTC
+	}))
+}
+
+func TestCreateScriptSchedule(t *testing.T) {
+	scriptFilePath := filepath.Join(t.TempDir(), "backup.ps1")
+	require.NoError(t, ioutil.WriteFile(scriptFilePath, []byte("Backup-Database"), 0600))
+
+	apiMock := &SchedulesAPIMock{}
+	apiMock.On("CreateSchedule", mock.Anything, mock.Anything).Return(&api.ScheduleResponse{}, nil)
+
+	renderMock := &ScheduleRendererMock{}
+	renderMock.On("RenderSchedule", mock.Anything).Return(nil)
+
+	sc := &SchedulesController{Rport: apiMock, ScheduleRenderer: renderMock}
+
+	err := sc.Create(context.Background(), "backup", config.FromValues(map[string]string{
+		ScheduleCron:    "@daily",
+		Script:          scriptFilePath,
+		GroupIDs:        "windows",
+		ScheduleTimeout: "600",
+	}))
+	require.NoError(t, err)
+
+	apiMock.AssertCalled(t, "CreateSchedule", mock.Anything, mock.MatchedBy(func(s *models.Schedule) bool {
+		return s.Type == models.ScheduleTypeScript &&
+			s.Details.Script == base64.StdEncoding.EncodeToString([]byte("Backup-Database")) &&
+			s.Details.Interpreter == "powershell" &&
+			s.Details.TimeoutSec == 600 &&
+			assert.ObjectsAreEqual([]string{"windows"}, s.Details.GroupIDs)
+	}))
+}
+
+func TestCreateInvalidSchedule(t *testing.T) {
+	testCases := []struct {
+		name          string
+		params        map[string]string
+		expectedError string
+	}{
+		{
+			name:          "no cron",
+			params:        map[string]string{Command: "uptime", ClientIDs: "cl1"},
+			expectedError: "no cron expression provided, use --schedule",
+		},
+		{
+			name:          "invalid cron",
+			params:        map[string]string{ScheduleCron: "0 25 * * *", Command: "uptime", ClientIDs: "cl1"},
+			expectedError: "invalid cron expression '0 25 * * *': value 25 is out of range 0-23 in hour field",
+		},
+		{
+			name:          "no command nor script",
+			params:        map[string]string{ScheduleCron: "@hourly", ClientIDs: "cl1"},
+			expectedError: "either --command or --script should be provided",
+		},
+		{
+			name:          "command and script",
+			params:        map[string]string{ScheduleCron: "@hourly", Command: "uptime", Script: "uptime.sh", ClientIDs: "cl1"},
+			expectedError: "--command and --script cannot be combined",
+		},
+		{
+			name:          "no clients",
+			params:        map[string]string{ScheduleCron: "@hourly", Command: "uptime"},
+			expectedError: "no client ids nor group ids provided",
+		},
+		{
+			name:          "invalid timeout",
+			params:        map[string]string{ScheduleCron: "@hourly", Command: "uptime", ClientIDs: "cl1", ScheduleTimeout: "-1"},
+			expectedError: "invalid timeout-sec value, expected a positive number of seconds",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			apiMock := &SchedulesAPIMock{}
+			sc := &SchedulesController{Rport: apiMock, ScheduleRenderer: &ScheduleRendererMock{}}
+
+			err := sc.Create(context.Background(), "uptime", config.FromValues(tc.params))
+			assert.EqualError(t, err, tc.expectedError)
+			apiMock.AssertNotCalled(t, "CreateSchedule", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateScheduleCronAndCommand(t *testing.T) {
+	apiMock := newSchedulesAPIMock(&models.Schedule{
+		ID:       "1",
+		Name:     "cleanup",
+		Schedule: "0 3 * * *",
+		Type:     models.ScheduleTypeScript,
+		Details:  models.WsScriptCommand{ClientIDs: []string{"cl1"}, Script: "cm0gLXJmIC90bXAvKg=="},
+	})
+	apiMock.On("UpdateSchedule", mock.Anything, mock.Anything).Return(&api.ScheduleResponse{}, nil)
+
+	renderMock := &ScheduleRendererMock{}
+	renderMock.On("RenderSchedule", mock.Anything).Return(nil)
+
+	sc := &SchedulesController{Rport: apiMock, ScheduleRenderer: renderMock}
+
+	err := sc.Update(context.Background(), "cleanup", config.FromValues(map[string]string{
+		ScheduleCron: "30 2 * * *",
+		Command:      "rm -rf /tmp/*",
+	}))
+	require.NoError(t, err)
+
+	apiMock.AssertCalled(t, "UpdateSchedule", mock.Anything, mock.MatchedBy(func(s *models.Schedule) bool {
+		return s.Name == "cleanup" &&
+			s.Schedule == "30 2 * * *" &&
+			s.Type == models.ScheduleTypeCommand &&
+			s.Details.Command == "rm -rf /tmp/*" &&
+			s.Details.Script == "" &&
+			assert.ObjectsAreEqual([]string{"cl1"}, s.Details.ClientIDs)
+	}))
+}
+
+func TestGetScheduleWithNextRuns(t *testing.T) {
+	schedule := &models.Schedule{ID: "1", Name: "cleanup", Schedule: "@hourly"}
+
+	renderMock := &ScheduleRendererMock{}
+	renderMock.On("RenderSchedule", schedule).Return(nil)
+
+	sc := &SchedulesController{Rport: newSchedulesAPIMock(schedule), ScheduleRenderer: renderMock}
+
+	err := sc.Get(context.Background(), "cleanup")
+	require.NoError(t, err)
+
+	renderMock.AssertExpectations(t)
+	assert.Len(t, schedule.NextRuns, ScheduleNextRunCount)
+}
+
+func TestGetScheduleWithNextRunsInTimezone(t *testing.T) {
+	schedule := &models.Schedule{ID: "1", Name: "cleanup", Schedule: "0 3 * * *"}
+
+	renderMock := &ScheduleRendererMock{}
+	renderMock.On("RenderSchedule", schedule).Return(nil)
+
+	timezone := time.FixedZone("UTC+2", 2*60*60)
+	sc := &SchedulesController{Rport: newSchedulesAPIMock(schedule), ScheduleRenderer: renderMock, Timezone: timezone}
+
+	err := sc.Get(context.Background(), "cleanup")
+	require.NoError(t, err)
+
+	require.Len(t, schedule.NextRuns, ScheduleNextRunCount)
+	assert.Equal(t, timezone, schedule.NextRuns[0].Location())
+	assert.Equal(t, 3, schedule.NextRuns[0].Hour())
+	assert.Equal(t, 1, schedule.NextRuns[0].UTC().Hour())
+}
+
+func TestRunScheduleCopy(t *testing.T) {
+	testCases := []struct {
+		name            string
+		schedule        *models.Schedule
+		expectedCommand bool
+	}{
+		{
+			name: "command",
+			schedule: &models.Schedule{
+				ID:      "1",
+				Name:    "cleanup",
+				Type:    models.ScheduleTypeCommand,
+				Details: models.WsScriptCommand{ClientIDs: []string{"cl1"}, Command: "rm -rf /tmp/*", TimeoutSec: 60},
+			},
+			expectedCommand: true,
+		},
+		{
+			name: "script",
+			schedule: &models.Schedule{
+				ID:      "1",
+				Name:    "cleanup",
+				Type:    models.ScheduleTypeScript,
+				Details: models.WsScriptCommand{GroupIDs: []string{"linux"}, Script: "cm0gLXJmIC90bXAvKg==", TimeoutSec: 60},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			js := &JobStarterMock{jidToGive: "multi123"}
+			jr := &JobRendererMock{}
+
+			sc := &SchedulesController{
+				ExecutionHelper: &ExecutionHelper{
+					JobStarter:  js,
+					JobRenderer: jr,
+				},
+				Rport: newSchedulesAPIMock(tc.schedule),
+			}
+
+			err := sc.RunCopy(context.Background(), "cleanup")
+			require.NoError(t, err)
+
+			if tc.expectedCommand {
+				require.NotNil(t, js.commandGiven)
+				assert.Nil(t, js.scriptGiven)
+				assert.Equal(t, &tc.schedule.Details, js.commandGiven)
+			} else {
+				require.NotNil(t, js.scriptGiven)
+				assert.Nil(t, js.commandGiven)
+				assert.Equal(t, &tc.schedule.Details, js.scriptGiven)
+			}
+			assert.Equal(t, "multi123", jr.jobStartedToRender.Jid)
+		})
+	}
+}
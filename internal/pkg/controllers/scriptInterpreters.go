@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+// fileExtInterpreters are the interpreters of scripts by their file extension for unix and windows clients,
+// an empty interpreter means the script cannot be executed on the os
+var fileExtInterpreters = map[string]scriptInterpreters{
+	".sh":   {unix: "sh"},
+	".bash": {unix: "bash"},
+	".py":   {unix: "python3", windows: "python"},
+	".pl":   {unix: "perl", windows: "perl"},
+	".rb":   {unix: "ruby", windows: "ruby"},
+	".ps1":  {unix: "powershell", windows: "powershell"},
+	".bat":  {windows: "cmd"},
+	".cmd":  {windows: "cmd"},
+	".vbs":  {windows: "cscript"},
+}
+
+var (
+	unixOnlyInterpreters    = []string{"sh", "bash", "dash", "ash", "ksh", "zsh", "csh", "tcsh"}
+	windowsOnlyInterpreters = []string{"cmd", "cscript", "wscript"}
+	utf8BOM                 = []byte{0xEF, 0xBB, 0xBF}
+)
+
+// scriptInterpreters are the interpreters of a script for unix and windows clients, they are either provided
+// or detected from the shebang line and the file extension, source tells where they come from
+type scriptInterpreters struct {
+	unix    string
+	windows string
+	source  string
+}
+
+// resolveScriptInterpreters gives the provided interpreter for all clients, otherwise the interpreter of the shebang
+// is used for unix clients and the one of the file extension for the rest
+func resolveScriptInterpreters(scriptFilePath string, scriptContent []byte, interpreterFromArgs string) *scriptInterpreters {
+	if interpreterFromArgs != "" {
+		return &scriptInterpreters{
+			unix:    interpreterFromArgs,
+			windows: interpreterFromArgs,
+			source:  fmt.Sprintf("interpreter '%s'", interpreterFromArgs),
+		}
+	}
+
+	extInterpreters, hasExtInterpreters := fileExtInterpreters[strings.ToLower(filepath.Ext(scriptFilePath))]
+	shebangInterpreter := parseShebang(scriptContent)
+	if shebangInterpreter == "" {
+		if !hasExtInterpreters {
+			return &scriptInterpreters{}
+		}
+		extInterpreters.source = fmt.Sprintf("interpreter of extension %s", filepath.Ext(scriptFilePath))
+		return &extInterpreters
+	}
+
+	return &scriptInterpreters{
+		unix:    shebangInterpreter,
+		windows: extInterpreters.windows,
+		source:  fmt.Sprintf("interpreter '%s' of the shebang", shebangInterpreter),
+	}
+}
+
+// resolveInterpreter gives the interpreter of a script whose clients are not known in advance
+func resolveInterpreter(scriptFilePath string, scriptContent []byte, interpreterFromArgs string) string {
+	return resolveScriptInterpreters(scriptFilePath, scriptContent, interpreterFromArgs).fallback()
+}
+
+// parseShebang gives the interpreter from the first script line like '#!/bin/bash -e' or '#!/usr/bin/env python3',
+// arguments of the interpreter are dropped as rport accepts only the interpreter itself
+func parseShebang(scriptContent []byte) string {
+	scriptContent = bytes.TrimPrefix(scriptContent, utf8BOM)
+	if !bytes.HasPrefix(scriptContent, []byte("#!")) {
+		return ""
+	}
+
+	firstLine, _ := bufio.NewReader(bytes.NewReader(scriptContent[2:])).ReadString('\n')
+	fields := strings.Fields(firstLine)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	if path.Base(fields[0]) != "env" {
+		return fields[0]
+	}
+
+	for _, field := range fields[1:] {
+		// options and variable assignments of env, e.g. '#!/usr/bin/env -S VAR=1 python3'
+		if strings.HasPrefix(field, "-") || strings.Contains(field, "=") {
+			continue
+		}
+		return field
+	}
+
+	return ""
+}
+
+// fallback gives the interpreter for clients with unknown os, e.g. members of client groups
+func (si *scriptInterpreters) fallback() string {
+	if si.unix != "" {
+		return si.unix
+	}
+
+	return si.windows
+}
+
+// dependsOnOs tells if the interpreter has to be chosen or validated by the os of each client,
+// only an interpreter for all os which isn't specific to one of them can be used without knowing the clients
+func (si *scriptInterpreters) dependsOnOs() bool {
+	return si.unix == "" || si.windows == "" || si.unix != si.windows ||
+		!isInterpreterCompatible(si.unix, false) || !isInterpreterCompatible(si.unix, true)
+}
+
+// forClient gives the interpreter for the os of the client and fails if the script cannot be executed on it,
+// clients with unknown os get the fallback interpreter
+func (si *scriptInterpreters) forClient(cl *models.Client) (string, error) {
+	if cl.OsKernel == "" && cl.OsFamily == "" {
+		return si.fallback(), nil
+	}
+
+	isWindows := isWindowsClient(cl)
+	interpreter := si.unix
+	if isWindows {
+		interpreter = si.windows
+	}
+
+	if (interpreter == "" && si.fallback() != "") || !isInterpreterCompatible(interpreter, isWindows) {
+		osFamily := cl.OsFamily
+		if osFamily == "" {
+			osFamily = cl.OsKernel
+		}
+		return "", fmt.Errorf(
+			"%s is not compatible with client %s of os family '%s', use --%s to provide a compatible one",
+			si.source,
+			clientLabel(cl),
+			osFamily,
+			Interpreter,
+		)
+	}
+
+	return interpreter, nil
+}
+
+// isInterpreterCompatible tells if the interpreter can exist on the os, unknown interpreters are considered compatible
+func isInterpreterCompatible(interpreter string, isWindows bool) bool {
+	if interpreter == "" {
+		return true
+	}
+
+	isWindowsPath := strings.Contains(interpreter, `\`) || (len(interpreter) > 1 && interpreter[1] == ':')
+	isUnixPath := strings.HasPrefix(interpreter, "/")
+	name := strings.TrimSuffix(strings.ToLower(path.Base(strings.ReplaceAll(interpreter, `\`, "/"))), ".exe")
+
+	if isWindows {
+		return !isUnixPath && !containsString(unixOnlyInterpreters, name)
+	}
+
+	return !isWindowsPath && !strings.HasSuffix(strings.ToLower(interpreter), ".exe") && !containsString(windowsOnlyInterpreters, name)
+}
+
+func clientLabel(cl *models.Client) string {
+	if cl.Name == "" {
+		return fmt.Sprintf("'%s'", cl.ID)
+	}
+
+	return fmt.Sprintf("'%s' (%s)", cl.Name, cl.ID)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
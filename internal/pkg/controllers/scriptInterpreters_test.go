@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestResolveScriptInterpreters(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		scriptFilePath       string
+		scriptContent        string
+		interpreterFromArgs  string
+		expectedInterpreters scriptInterpreters
+	}{
+		{
+			name:                 "provided",
+			scriptFilePath:       "script.bat",
+			scriptContent:        "#!/bin/bash",
+			interpreterFromArgs:  "powershell",
+			expectedInterpreters: scriptInterpreters{unix: "powershell", windows: "powershell", source: "interpreter 'powershell'"},
+		},
+		{
+			name:                 "extension",
+			scriptFilePath:       "script.PY",
+			scriptContent:        "print(1)",
+			expectedInterpreters: scriptInterpreters{unix: "python3", windows: "python", source: "interpreter of extension .PY"},
+		},
+		{
+			name:                 "windows_only_extension",
+			scriptFilePath:       "script.vbs",
+			expectedInterpreters: scriptInterpreters{windows: "cscript", source: "interpreter of extension .vbs"},
+		},
+		{
+			name:           "shebang",
+			scriptFilePath: "script",
+			scriptContent:  "#!/bin/bash -e\necho 1",
+			expectedInterpreters: scriptInterpreters{
+				unix:   "/bin/bash",
+				source: "interpreter '/bin/bash' of the shebang",
+			},
+		},
+		{
+			name:           "env_shebang_with_extension",
+			scriptFilePath: "script.py",
+			scriptContent:  "\xEF\xBB\xBF#! /usr/bin/env -S PYTHONUNBUFFERED=1 python3.9\r\nprint(1)",
+			expectedInterpreters: scriptInterpreters{
+				unix:    "python3.9",
+				windows: "python",
+				source:  "interpreter 'python3.9' of the shebang",
+			},
+		},
+		{
+			name:                 "unknown",
+			scriptFilePath:       "script.txt",
+			scriptContent:        "echo 1",
+			expectedInterpreters: scriptInterpreters{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			actualInterpreters := resolveScriptInterpreters(tc.scriptFilePath, []byte(tc.scriptContent), tc.interpreterFromArgs)
+			assert.Equal(t, tc.expectedInterpreters, *actualInterpreters)
+		})
+	}
+}
+
+func TestScriptInterpreterForClient(t *testing.T) {
+	linuxClient := &models.Client{ID: "cl1", Name: "web", OsKernel: "linux", OsFamily: "debian"}
+	windowsClient := &models.Client{ID: "cl2", OsKernel: "windows", OsFamily: "windows"}
+	unknownClient := &models.Client{ID: "cl3"}
+
+	testCases := []struct {
+		name                string
+		interpreters        *scriptInterpreters
+		client              *models.Client
+		expectedInterpreter string
+		expectedError       string
+	}{
+		{
+			name:                "unix",
+			interpreters:        &scriptInterpreters{unix: "python3", windows: "python"},
+			client:              linuxClient,
+			expectedInterpreter: "python3",
+		},
+		{
+			name:                "windows",
+			interpreters:        &scriptInterpreters{unix: "python3", windows: "python"},
+			client:              windowsClient,
+			expectedInterpreter: "python",
+		},
+		{
+			name:                "unknown_os",
+			interpreters:        &scriptInterpreters{windows: "cmd"},
+			client:              unknownClient,
+			expectedInterpreter: "cmd",
+		},
+		{
+			name:                "no_interpreter",
+			interpreters:        &scriptInterpreters{},
+			client:              windowsClient,
+			expectedInterpreter: "",
+		},
+		{
+			name:          "missing_for_os",
+			interpreters:  &scriptInterpreters{windows: "cmd", source: "interpreter of extension .bat"},
+			client:        linuxClient,
+			expectedError: "interpreter of extension .bat is not compatible with client 'web' (cl1) of os family 'debian', use --interpreter to provide a compatible one",
+		},
+		{
+			name:          "provided_windows_path",
+			interpreters:  &scriptInterpreters{unix: `C:\Python\python.exe`, windows: `C:\Python\python.exe`, source: "interpreter 'C:\\Python\\python.exe'"},
+			client:        linuxClient,
+			expectedError: "interpreter 'C:\\Python\\python.exe' is not compatible with client 'web' (cl1) of os family 'debian', use --interpreter to provide a compatible one",
+		},
+		{
+			name:          "provided_unix_shell",
+			interpreters:  &scriptInterpreters{unix: "/bin/bash", windows: "/bin/bash", source: "interpreter '/bin/bash'"},
+			client:        windowsClient,
+			expectedError: "interpreter '/bin/bash' is not compatible with client 'cl2' of os family 'windows', use --interpreter to provide a compatible one",
+		},
+		{
+			name:                "provided_cross_platform",
+			interpreters:        &scriptInterpreters{unix: "powershell", windows: "powershell"},
+			client:              linuxClient,
+			expectedInterpreter: "powershell",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			interpreter, err := tc.interpreters.forClient(tc.client)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedInterpreter, interpreter)
+		})
+	}
+}
+
+func TestScriptInterpretersDependOnOs(t *testing.T) {
+	testCases := []struct {
+		name         string
+		interpreters scriptInterpreters
+		expected     bool
+	}{
+		{name: "same for all os", interpreters: scriptInterpreters{unix: "powershell", windows: "powershell"}},
+		{name: "different per os", interpreters: scriptInterpreters{unix: "python3", windows: "python"}, expected: true},
+		{name: "unix only", interpreters: scriptInterpreters{unix: "sh"}, expected: true},
+		{name: "windows only", interpreters: scriptInterpreters{windows: "cmd"}, expected: true},
+		{name: "provided unix interpreter", interpreters: scriptInterpreters{unix: "/bin/bash", windows: "/bin/bash"}, expected: true},
+		{name: "provided windows interpreter", interpreters: scriptInterpreters{unix: "cmd", windows: "cmd"}, expected: true},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.interpreters.dependsOnOs())
+		})
+	}
+}
+
+func TestScriptIsSubmittedForEachOs(t *testing.T) {
+	scriptPath := filepath.Join(t.TempDir(), "inventory.py")
+	require.NoError(t, ioutil.WriteFile(scriptPath, []byte("print(1)"), 0600))
+
+	firstRw := newJobReadWriter(
+		t,
+		&models.Job{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful},
+		&models.Job{Jid: "1", ClientID: "cl3", Status: models.JobStatusSuccessful},
+	)
+	secondRw := newJobReadWriter(t, &models.Job{Jid: "2", ClientID: "cl2", Status: models.JobStatusSuccessful})
+	connections := &connectionsMock{readWriters: []*ReadWriterMock{secondRw}}
+
+	sc := &ScriptsController{
+		ExecutionHelper: &ExecutionHelper{
+			ClientSearch: &ClientSearchMock{clientsToGive: []*models.Client{
+				{ID: "cl1", OsKernel: "linux", OsFamily: "debian"},
+				{ID: "cl2", OsKernel: "windows", OsFamily: "windows"},
+				{ID: "cl3", OsKernel: "linux", OsFamily: "alpine"},
+			}},
+			ReadWriter:         firstRw,
+			ReadWriterProvider: connections.provide,
+			JobRenderer:        &JobRendererMock{},
+		},
+	}
+
+	err := sc.Start(context.Background(), config.FromValues(map[string]string{
+		Script:    scriptPath,
+		ClientIDs: "cl1,cl2,cl3",
+	}))
+	require.NoError(t, err)
+
+	scriptBase64 := base64.StdEncoding.EncodeToString([]byte("print(1)"))
+
+	unixCmd := readSubmission(t, firstRw)
+	assert.Equal(t, []string{"cl1", "cl3"}, unixCmd.ClientIDs)
+	assert.Equal(t, "python3", unixCmd.Interpreter)
+	assert.Equal(t, scriptBase64, unixCmd.Script)
+
+	windowsCmd := readSubmission(t, secondRw)
+	assert.Equal(t, []string{"cl2"}, windowsCmd.ClientIDs)
+	assert.Equal(t, "python", windowsCmd.Interpreter)
+	assert.Equal(t, scriptBase64, windowsCmd.Script)
+}
+
+func TestScriptIsRejectedForIncompatibleClients(t *testing.T) {
+	scriptPath := filepath.Join(t.TempDir(), "cleanup.bat")
+	require.NoError(t, ioutil.WriteFile(scriptPath, []byte("del /q %TEMP%\\*"), 0600))
+
+	rw := &ReadWriterMock{}
+	sc := &ScriptsController{
+		ExecutionHelper: &ExecutionHelper{
+			ClientSearch: &ClientSearchMock{clientsToGive: []*models.Client{
+				{ID: "cl1", Name: "win", OsKernel: "windows", OsFamily: "windows"},
+				{ID: "cl2", Name: "web", OsKernel: "linux", OsFamily: "debian"},
+			}},
+			ReadWriter: rw,
+		},
+	}
+
+	err := sc.Start(context.Background(), config.FromValues(map[string]string{
+		Script:    scriptPath,
+		ClientIDs: "cl1,cl2",
+	}))
+	assert.EqualError(
+		t,
+		err,
+		"interpreter of extension .bat is not compatible with client 'web' (cl2) of os family 'debian', use --interpreter to provide a compatible one",
+	)
+	assert.Len(t, rw.writtenItems, 0)
+	assert.True(t, rw.isClosed)
+}
+
+func TestScriptWithGroupsIsRejectedForMixedOs(t *testing.T) {
+	scriptPath := filepath.Join(t.TempDir(), "inventory.py")
+	require.NoError(t, ioutil.WriteFile(scriptPath, []byte("print(1)"), 0600))
+
+	rw := &ReadWriterMock{}
+	sc := &ScriptsController{
+		ExecutionHelper: &ExecutionHelper{
+			ClientSearch: &ClientSearchMock{clientsToGive: []*models.Client{
+				{ID: "cl1", OsKernel: "linux"},
+				{ID: "cl2", OsKernel: "windows"},
+			}},
+			ReadWriter: rw,
+		},
+	}
+
+	err := sc.Start(context.Background(), config.FromValues(map[string]string{
+		Script:    scriptPath,
+		ClientIDs: "cl1,cl2",
+		GroupIDs:  "group1",
+	}))
+	assert.EqualError(
+		t,
+		err,
+		"--gids cannot be combined with clients of different os, as the script is submitted for each os separately, use --interpreter instead",
+	)
+	assert.Len(t, rw.writtenItems, 0)
+}
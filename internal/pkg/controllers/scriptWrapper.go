@@ -0,0 +1,284 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+const (
+	ScriptArg      = "arg"
+	ScriptEnv      = "env"
+	ScriptTemplate = "template"
+
+	interpreterKindSh         = "sh"
+	interpreterKindPowershell = "powershell"
+	interpreterKindCmd        = "cmd"
+
+	defaultShInterpreter = "/bin/sh"
+	// cmd lines are limited to 8191 chars, so the base64 payload is written in chunks
+	cmdBase64LineLength = 76
+)
+
+var envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var interpreterKinds = map[string]string{
+	"sh":         interpreterKindSh,
+	"bash":       interpreterKindSh,
+	"dash":       interpreterKindSh,
+	"ash":        interpreterKindSh,
+	"ksh":        interpreterKindSh,
+	"zsh":        interpreterKindSh,
+	"powershell": interpreterKindPowershell,
+	"pwsh":       interpreterKindPowershell,
+	"cmd":        interpreterKindCmd,
+}
+
+type envVar struct {
+	name  string
+	value string
+}
+
+// scriptOptions are positional arguments and environment variables given to a script and a flag telling if its body
+// is a go template rendered for each client
+type scriptOptions struct {
+	args       []string
+	env        []envVar
+	isTemplate bool
+}
+
+func readScriptOptions(params *options.ParameterBag) (*scriptOptions, error) {
+	opts := &scriptOptions{
+		args:       params.ReadStrings(ScriptArg),
+		isTemplate: params.ReadBool(ScriptTemplate, false),
+	}
+
+	for _, expr := range params.ReadStrings(ScriptEnv) {
+		exprParts := strings.SplitN(expr, "=", 2)
+		if len(exprParts) != 2 {
+			return nil, fmt.Errorf("invalid env variable '%s', expected format is KEY=VALUE", expr)
+		}
+		if !envNameRegex.MatchString(exprParts[0]) {
+			return nil, fmt.Errorf("invalid env variable name '%s', only letters, digits and underscores are allowed", exprParts[0])
+		}
+		opts.env = append(opts.env, envVar{name: exprParts[0], value: exprParts[1]})
+	}
+
+	return opts, nil
+}
+
+func (so *scriptOptions) needsWrapper() bool {
+	return len(so.args) > 0 || len(so.env) > 0
+}
+
+// isPerClient tells if the script should be prepared for each client, as the template uses its fields or
+// the wrapper depends on its os if no interpreter is given
+func (so *scriptOptions) isPerClient(interpreter string) bool {
+	return so.isTemplate || (so.needsWrapper() && interpreter == "")
+}
+
+// prepare renders the script for the client if it's a template and wraps it to pass the args and env variables,
+// the client is nil if the clients are not looked up, as neither the interpreter nor the script depends on them
+func (so *scriptOptions) prepare(content []byte, interpreter string, cl *models.Client) ([]byte, error) {
+	var err error
+	if so.isTemplate {
+		content, err = renderScriptTemplate(content, cl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !so.needsWrapper() {
+		return content, nil
+	}
+
+	kind, err := resolveInterpreterKind(interpreter, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case interpreterKindPowershell:
+		return wrapPowershellScript(content, so.args, so.env), nil
+	case interpreterKindCmd:
+		return wrapCmdScript(content, so.args, so.env)
+	default:
+		if interpreter == "" {
+			interpreter = defaultShInterpreter
+		}
+		return wrapShScript(content, interpreter, so.args, so.env), nil
+	}
+}
+
+// renderScriptTemplate renders the script body with the fields of the client, e.g. {{.Name}}, {{.ID}}, {{.Os}} or {{.Tags}}
+func renderScriptTemplate(content []byte, cl *models.Client) ([]byte, error) {
+	tmpl, err := template.New("script").Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid script template: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, cl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render script template for client %s: %v", cl.ID, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// resolveInterpreterKind tells which wrapper fits the interpreter, rport uses cmd on windows and sh on other systems
+// if no interpreter is given
+func resolveInterpreterKind(interpreter string, cl *models.Client) (string, error) {
+	if interpreter == "" {
+		if cl != nil && isWindowsClient(cl) {
+			return interpreterKindCmd, nil
+		}
+		return interpreterKindSh, nil
+	}
+
+	name := strings.ToLower(path.Base(strings.ReplaceAll(interpreter, "\\", "/")))
+	kind, ok := interpreterKinds[strings.TrimSuffix(name, ".exe")]
+	if !ok {
+		return "", fmt.Errorf(
+			"--%s and --%s are not supported for interpreter '%s', supported are sh, bash, powershell and cmd",
+			ScriptArg,
+			ScriptEnv,
+			interpreter,
+		)
+	}
+
+	return kind, nil
+}
+
+func isWindowsClient(cl *models.Client) bool {
+	return strings.EqualFold(cl.OsKernel, "windows") || strings.EqualFold(cl.OsFamily, "windows")
+}
+
+// wrapShScript decodes the script to a temp file and runs it with the interpreter, the exit code of the script is kept
+func wrapShScript(content []byte, interpreter string, args []string, env []envVar) []byte {
+	buf := &bytes.Buffer{}
+	for _, v := range env {
+		fmt.Fprintf(buf, "%s=%s; export %s\n", v.name, quoteSh(v.value), v.name)
+	}
+	buf.WriteString("rport_script=$(mktemp) || exit 1\n")
+	buf.WriteString("trap 'rm -f \"$rport_script\"' EXIT\n")
+	fmt.Fprintf(buf, "printf '%%s' %s | base64 -d > \"$rport_script\" || exit 1\n", quoteSh(base64.StdEncoding.EncodeToString(content)))
+	fmt.Fprintf(buf, "%s \"$rport_script\"", quoteSh(interpreter))
+	for _, arg := range args {
+		buf.WriteString(" " + quoteSh(arg))
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes()
+}
+
+// wrapPowershellScript runs the decoded script as a script block, so no temp file is needed and the execution policy
+// for script files doesn't apply
+func wrapPowershellScript(content []byte, args []string, env []envVar) []byte {
+	buf := &bytes.Buffer{}
+	for _, v := range env {
+		fmt.Fprintf(buf, "$env:%s = %s\n", v.name, quotePowershell(v.value))
+	}
+	fmt.Fprintf(
+		buf,
+		"$rportScript = [System.Text.Encoding]::UTF8.GetString([System.Convert]::FromBase64String(%s)).TrimStart([char]0xFEFF)\n",
+		quotePowershell(base64.StdEncoding.EncodeToString(content)),
+	)
+	buf.WriteString("& ([scriptblock]::Create($rportScript))")
+	for _, arg := range args {
+		buf.WriteString(" " + quotePowershell(arg))
+	}
+	// $LASTEXITCODE is set only by native commands, so a failed pure powershell script exits with 1
+	buf.WriteString("\nif (-not $?) { if ($LASTEXITCODE) { exit $LASTEXITCODE }; exit 1 }\nexit $LASTEXITCODE\n")
+
+	return buf.Bytes()
+}
+
+// wrapCmdScript decodes the script to a temp batch file with certutil and calls it, the exit code of the script is kept,
+// the args are passed via variables, as call expands the percent signs of its line once more
+func wrapCmdScript(content []byte, args []string, env []envVar) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("@echo off\r\nsetlocal DisableDelayedExpansion\r\n")
+	for _, v := range env {
+		if err := validateCmdValue(v.value); err != nil {
+			return nil, err
+		}
+		// special chars are literal inside the quoted set, so only percent signs need escaping
+		fmt.Fprintf(buf, "set \"%s=%s\"\r\n", v.name, escapeCmd(v.value))
+	}
+	for i, arg := range args {
+		if err := validateCmdArg(arg); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(buf, "set \"rport_arg%d=%s\"\r\n", i+1, escapeCmd(arg))
+	}
+	buf.WriteString("set \"rport_script=%TEMP%\\rport-script-%RANDOM%%RANDOM%.bat\"\r\n")
+	buf.WriteString("(\r\n")
+	payload := base64.StdEncoding.EncodeToString(content)
+	for len(payload) > 0 {
+		lineLength := cmdBase64LineLength
+		if len(payload) < lineLength {
+			lineLength = len(payload)
+		}
+		fmt.Fprintf(buf, "echo %s\r\n", payload[:lineLength])
+		payload = payload[lineLength:]
+	}
+	buf.WriteString(")>\"%rport_script%.b64\"\r\n")
+	buf.WriteString("certutil -f -decode \"%rport_script%.b64\" \"%rport_script%\" >nul\r\n")
+	buf.WriteString("del \"%rport_script%.b64\"\r\n")
+	buf.WriteString("call \"%rport_script%\"")
+	for i := range args {
+		// %%rport_argN%% becomes %rport_argN% in the batch line and is expanded to the value by call,
+		// so the value itself is not expanded again
+		fmt.Fprintf(buf, " \"%%%%rport_arg%d%%%%\"", i+1)
+	}
+	buf.WriteString("\r\nset \"rport_exit_code=%ERRORLEVEL%\"\r\n")
+	buf.WriteString("del \"%rport_script%\"\r\n")
+	buf.WriteString("exit /b %rport_exit_code%\r\n")
+
+	return buf.Bytes(), nil
+}
+
+func quoteSh(val string) string {
+	return "'" + strings.ReplaceAll(val, "'", `'\''`) + "'"
+}
+
+func quotePowershell(val string) string {
+	return "'" + strings.ReplaceAll(val, "'", "''") + "'"
+}
+
+// escapeCmd escapes a value used inside double quotes of a set command in a batch file
+func escapeCmd(val string) string {
+	return strings.ReplaceAll(val, "%", "%%")
+}
+
+// validateCmdValue rejects values which cannot be quoted for cmd, as a double quote ends the quoted value
+// and a line break ends the command
+func validateCmdValue(val string) error {
+	if strings.ContainsAny(val, "\"\r\n") {
+		return fmt.Errorf("double quotes and line breaks are not supported in --%s and --%s values for cmd: %q", ScriptArg, ScriptEnv, val)
+	}
+
+	return nil
+}
+
+// validateCmdArg rejects args which cannot be passed to the script, call doubles the carets even in quoted args
+func validateCmdArg(arg string) error {
+	if err := validateCmdValue(arg); err != nil {
+		return err
+	}
+
+	if strings.Contains(arg, "^") {
+		return fmt.Errorf("carets are not supported in --%s values for cmd, as call doubles them: %q", ScriptArg, arg)
+	}
+
+	return nil
+}
@@ -0,0 +1,409 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestReadScriptOptions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		env           []string
+		expectedEnv   []envVar
+		expectedError string
+	}{
+		{
+			name:        "valid_env",
+			env:         []string{"NAME=value", "EMPTY=", "WITH_EQ=a=b"},
+			expectedEnv: []envVar{{name: "NAME", value: "value"}, {name: "EMPTY", value: ""}, {name: "WITH_EQ", value: "a=b"}},
+		},
+		{
+			name:          "no_value",
+			env:           []string{"NAME"},
+			expectedError: "invalid env variable 'NAME', expected format is KEY=VALUE",
+		},
+		{
+			name:          "invalid_name",
+			env:           []string{"1NAME=value"},
+			expectedError: "invalid env variable name '1NAME', only letters, digits and underscores are allowed",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := readScriptOptions(options.New(options.NewMapValuesProvider(map[string]interface{}{
+				ScriptEnv: tc.env,
+				ScriptArg: []string{"a"},
+			})))
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedEnv, opts.env)
+			assert.Equal(t, []string{"a"}, opts.args)
+			assert.False(t, opts.isTemplate)
+		})
+	}
+}
+
+func TestResolveInterpreterKind(t *testing.T) {
+	testCases := []struct {
+		interpreter   string
+		client        *models.Client
+		expectedKind  string
+		expectedError string
+	}{
+		{interpreter: "", expectedKind: interpreterKindSh},
+		{interpreter: "", client: &models.Client{OsKernel: "linux"}, expectedKind: interpreterKindSh},
+		{interpreter: "", client: &models.Client{OsKernel: "windows"}, expectedKind: interpreterKindCmd},
+		{interpreter: "/bin/bash", expectedKind: interpreterKindSh},
+		{interpreter: "pwsh", expectedKind: interpreterKindPowershell},
+		{interpreter: `C:\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`, expectedKind: interpreterKindPowershell},
+		{interpreter: "CMD.EXE", expectedKind: interpreterKindCmd},
+		{
+			interpreter:   "python3",
+			expectedError: "--arg and --env are not supported for interpreter 'python3', supported are sh, bash, powershell and cmd",
+		},
+	}
+
+	for _, tc := range testCases {
+		kind, err := resolveInterpreterKind(tc.interpreter, tc.client)
+		if tc.expectedError != "" {
+			assert.EqualError(t, err, tc.expectedError)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tc.expectedKind, kind, tc.interpreter)
+	}
+}
+
+func TestWrapShScriptPassesArgsAndEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+
+	script := []byte("#!/bin/sh\necho \"$#|$1|$2|$GREETING\"\nexit 3\n")
+	wrapped := wrapShScript(script, "/bin/sh", []string{"it's", "two words"}, []envVar{{name: "GREETING", value: "hello 'world' $HOME"}})
+
+	wrapperPath := filepath.Join(t.TempDir(), "wrapper.sh")
+	require.NoError(t, ioutil.WriteFile(wrapperPath, wrapped, 0600))
+
+	out, err := exec.Command("/bin/sh", wrapperPath).Output()
+	exitErr, ok := err.(*exec.ExitError)
+	require.True(t, ok, "the exit code of the script should be kept, got %v", err)
+	assert.Equal(t, 3, exitErr.ExitCode())
+	assert.Equal(t, "2|it's|two words|hello 'world' $HOME\n", string(out))
+}
+
+func TestWrapPowershellScript(t *testing.T) {
+	wrapped := wrapPowershellScript([]byte("Write-Output $args[0]"), []string{"it's"}, []envVar{{name: "NAME", value: "o'neil"}})
+
+	expectedWrapper := "$env:NAME = 'o''neil'\n" +
+		"$rportScript = [System.Text.Encoding]::UTF8.GetString([System.Convert]::FromBase64String('" +
+		base64.StdEncoding.EncodeToString([]byte("Write-Output $args[0]")) + "')).TrimStart([char]0xFEFF)\n" +
+		"& ([scriptblock]::Create($rportScript)) 'it''s'\n" +
+		"if (-not $?) { if ($LASTEXITCODE) { exit $LASTEXITCODE }; exit 1 }\n" +
+		"exit $LASTEXITCODE\n"
+	assert.Equal(t, expectedWrapper, string(wrapped))
+}
+
+// expandBatchPercents expands the variables of a batch file line like cmd does, %% gives a percent sign and
+// unknown variables are removed
+func expandBatchPercents(line string, vars map[string]string) string {
+	out := &strings.Builder{}
+	for i := 0; i < len(line); i++ {
+		if line[i] != '%' {
+			out.WriteByte(line[i])
+			continue
+		}
+		if i+1 < len(line) && line[i+1] == '%' {
+			out.WriteByte('%')
+			i++
+			continue
+		}
+		end := strings.IndexByte(line[i+1:], '%')
+		if end < 0 {
+			continue
+		}
+		out.WriteString(vars[strings.ToLower(line[i+1:i+1+end])])
+		i += end + 1
+	}
+
+	return out.String()
+}
+
+// runCmdWrapper interprets the set, echo and call lines of the cmd wrapper and gives the decoded script,
+// the args as the called script gets them with %~1, %~2 etc and the variables set by the wrapper
+func runCmdWrapper(t *testing.T, wrapped []byte) (script []byte, args []string, vars map[string]string) {
+	vars = map[string]string{"temp": `C:\Temp`, "random": "42", "path": `C:\Windows`}
+	payload := ""
+	for _, line := range strings.Split(string(wrapped), "\r\n") {
+		switch {
+		case strings.HasPrefix(line, `set "`):
+			nameValue := strings.SplitN(strings.TrimSuffix(strings.TrimPrefix(expandBatchPercents(line, vars), `set "`), `"`), "=", 2)
+			vars[strings.ToLower(nameValue[0])] = nameValue[1]
+		case strings.HasPrefix(line, "echo "):
+			payload += strings.TrimPrefix(line, "echo ")
+		case strings.HasPrefix(line, "call "):
+			// call expands the line once more and doubles the carets
+			callLine := expandBatchPercents(expandBatchPercents(strings.TrimPrefix(line, "call "), vars), vars)
+			callLine = strings.ReplaceAll(callLine, "^", "^^")
+			tokens := regexp.MustCompile(`"[^"]*"|[^ ]+`).FindAllString(callLine, -1)
+			require.NotEmpty(t, tokens)
+			assert.Equal(t, `"C:\Temp\rport-script-4242.bat"`, tokens[0])
+			for _, token := range tokens[1:] {
+				args = append(args, strings.Trim(token, `"`))
+			}
+		}
+	}
+
+	script, err := base64.StdEncoding.DecodeString(payload)
+	require.NoError(t, err)
+
+	return script, args, vars
+}
+
+func TestWrapCmdScriptPassesArgsAndEnv(t *testing.T) {
+	script := []byte(strings.Repeat("echo %1\r\n", 20))
+	args := []string{"100%", "%PATH%", "%rport_arg1%", "a & b | c > d", "!bang!", "two words", ""}
+	env := []envVar{{name: "RATE", value: "100% & more"}, {name: "PATH_REF", value: "%PATH%"}, {name: "CARET", value: "^caret"}}
+
+	wrapped, err := wrapCmdScript(script, args, env)
+	require.NoError(t, err)
+
+	decodedScript, decodedArgs, vars := runCmdWrapper(t, wrapped)
+	assert.Equal(t, script, decodedScript)
+	assert.Equal(t, args, decodedArgs)
+	assert.Equal(t, "100% & more", vars["rate"])
+	assert.Equal(t, "%PATH%", vars["path_ref"])
+	assert.Equal(t, "^caret", vars["caret"])
+}
+
+func TestWrapCmdScriptRejectsCaretsInArgs(t *testing.T) {
+	_, err := wrapCmdScript([]byte("echo %1"), []string{"^caret"}, nil)
+	assert.EqualError(t, err, `carets are not supported in --arg values for cmd, as call doubles them: "^caret"`)
+}
+
+func TestWrapCmdScriptRejectsQuotesAndLineBreaks(t *testing.T) {
+	for _, val := range []string{`say "hi"`, "two\r\nlines", "two\nlines"} {
+		_, err := wrapCmdScript([]byte("echo %1"), []string{val}, nil)
+		assert.EqualError(t, err, fmt.Sprintf("double quotes and line breaks are not supported in --arg and --env values for cmd: %q", val))
+
+		_, err = wrapCmdScript([]byte("echo %1"), nil, []envVar{{name: "NAME", value: val}})
+		assert.Error(t, err)
+	}
+}
+
+func TestRenderScriptTemplate(t *testing.T) {
+	cl := &models.Client{ID: "cl1", Name: "web", Os: "Ubuntu 20.04", Tags: []string{"prod", "eu"}}
+
+	rendered, err := renderScriptTemplate([]byte(`echo {{.Name}} {{.ID}} "{{.Os}}" {{join .Tags ","}}`), cl)
+	assert.EqualError(t, err, `invalid script template: template: script:1: function "join" not defined`)
+	assert.Nil(t, rendered)
+
+	rendered, err = renderScriptTemplate([]byte(`echo {{.Name}} {{.ID}} "{{.Os}}"{{range .Tags}} {{.}}{{end}}`), cl)
+	require.NoError(t, err)
+	assert.Equal(t, `echo web cl1 "Ubuntu 20.04" prod eu`, string(rendered))
+
+	_, err = renderScriptTemplate([]byte(`echo {{.Unknown}}`), cl)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to render script template for client cl1")
+}
+
+// connectionsMock gives read writers of the submissions following the first one
+type connectionsMock struct {
+	readWriters []*ReadWriterMock
+	index       int
+}
+
+func (cm *connectionsMock) provide(ctx context.Context) (ReadWriter, error) {
+	rw := cm.readWriters[cm.index]
+	cm.index++
+	return rw, nil
+}
+
+func newJobReadWriter(t *testing.T, jobs ...*models.Job) *ReadWriterMock {
+	rw := &ReadWriterMock{}
+	for _, job := range jobs {
+		jobBytes, err := json.Marshal(job)
+		require.NoError(t, err)
+		rw.itemsToRead = append(rw.itemsToRead, ReadChunk{Output: jobBytes})
+	}
+	rw.itemsToRead = append(rw.itemsToRead, ReadChunk{Err: io.EOF})
+
+	return rw
+}
+
+func readSubmission(t *testing.T, rw *ReadWriterMock) *models.WsScriptCommand {
+	require.Len(t, rw.writtenItems, 1)
+	wsCmd := &models.WsScriptCommand{}
+	require.NoError(t, json.Unmarshal([]byte(rw.writtenItems[0]), wsCmd))
+
+	return wsCmd
+}
+
+func TestScriptTemplateIsRenderedForEachClient(t *testing.T) {
+	scriptPath := filepath.Join(t.TempDir(), "hello.sh")
+	require.NoError(t, ioutil.WriteFile(scriptPath, []byte("echo {{.Name}}"), 0600))
+
+	firstRw := newJobReadWriter(t, &models.Job{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful})
+	secondRw := newJobReadWriter(t, &models.Job{Jid: "2", ClientID: "cl2", Status: models.JobStatusSuccessful})
+	connections := &connectionsMock{readWriters: []*ReadWriterMock{secondRw}}
+	clientSearch := &ClientSearchMock{clientsToGive: []*models.Client{
+		{ID: "cl1", Name: "web"},
+		{ID: "cl10", Name: "other"},
+		{ID: "cl2", Name: "db"},
+	}}
+
+	sc := &ScriptsController{
+		ExecutionHelper: &ExecutionHelper{
+			ClientSearch:       clientSearch,
+			ReadWriter:         firstRw,
+			ReadWriterProvider: connections.provide,
+			JobRenderer:        &JobRendererMock{},
+		},
+	}
+
+	err := sc.Start(context.Background(), options.New(options.NewMapValuesProvider(map[string]interface{}{
+		Script:         scriptPath,
+		ClientIDs:      "cl1,cl2",
+		ScriptTemplate: true,
+	})))
+	require.NoError(t, err)
+
+	assert.Equal(t, "cl1,cl2", clientSearch.searchTermGiven)
+
+	firstCmd := readSubmission(t, firstRw)
+	assert.Equal(t, []string{"cl1"}, firstCmd.ClientIDs)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("echo web")), firstCmd.Script)
+
+	secondCmd := readSubmission(t, secondRw)
+	assert.Equal(t, []string{"cl2"}, secondCmd.ClientIDs)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("echo db")), secondCmd.Script)
+
+	assert.True(t, firstRw.isClosed)
+	assert.True(t, secondRw.isClosed)
+}
+
+func TestScriptArgsOfClientsWithSameOsAreSentOnce(t *testing.T) {
+	scriptPath := filepath.Join(t.TempDir(), "hello.sh")
+	require.NoError(t, ioutil.WriteFile(scriptPath, []byte("echo $1"), 0600))
+
+	rw := newJobReadWriter(
+		t,
+		&models.Job{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful},
+		&models.Job{Jid: "1", ClientID: "cl2", Status: models.JobStatusSuccessful},
+	)
+	sc := &ScriptsController{
+		ExecutionHelper: &ExecutionHelper{
+			ClientSearch: &ClientSearchMock{clientsToGive: []*models.Client{
+				{ID: "cl1", Name: "web", OsKernel: "linux"},
+				{ID: "cl2", Name: "db", OsKernel: "linux"},
+			}},
+			ReadWriter:  rw,
+			JobRenderer: &JobRendererMock{},
+		},
+	}
+
+	err := sc.Start(context.Background(), options.New(options.NewMapValuesProvider(map[string]interface{}{
+		Script:    scriptPath,
+		ClientIDs: "cl1,cl2",
+		ScriptArg: []string{"hello"},
+	})))
+	require.NoError(t, err)
+
+	wsCmd := readSubmission(t, rw)
+	assert.Equal(t, []string{"cl1", "cl2"}, wsCmd.ClientIDs)
+	assert.Equal(t, "sh", wsCmd.Interpreter)
+	expectedScript := wrapShScript([]byte("echo $1"), "sh", []string{"hello"}, nil)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(expectedScript), wsCmd.Script)
+	assert.True(t, rw.isClosed)
+}
+
+func TestScriptSubmissionsAreSkippedOnAbortOnError(t *testing.T) {
+	scriptPath := filepath.Join(t.TempDir(), "hello.sh")
+	require.NoError(t, ioutil.WriteFile(scriptPath, []byte("echo {{.ID}}"), 0600))
+
+	rw := newJobReadWriter(t, &models.Job{Jid: "1", ClientID: "cl1", Status: models.JobStatusFailed})
+	connections := &connectionsMock{}
+	sc := &ScriptsController{
+		ExecutionHelper: &ExecutionHelper{
+			ClientSearch: &ClientSearchMock{clientsToGive: []*models.Client{
+				{ID: "cl1", Name: "web"},
+				{ID: "cl2", Name: "db"},
+			}},
+			ReadWriter:         rw,
+			ReadWriterProvider: connections.provide,
+			JobRenderer:        &JobRendererMock{},
+			SummaryRenderer:    &JobsSummaryRendererMock{},
+		},
+	}
+
+	err := sc.Start(context.Background(), options.New(options.NewMapValuesProvider(map[string]interface{}{
+		Script:         scriptPath,
+		ClientIDs:      "cl1,cl2",
+		ScriptTemplate: true,
+		AbortOnError:   true,
+	})))
+	var jobsFailedErr *JobsFailedError
+	require.ErrorAs(t, err, &jobsFailedErr)
+	assert.Equal(t, models.JobResultFailed, jobsFailedErr.Summary.Clients[0].Result)
+	assert.Equal(t, models.JobResultUnreachable, jobsFailedErr.Summary.Clients[1].Result)
+
+	assert.Len(t, rw.writtenItems, 1)
+	assert.Equal(t, 0, connections.index, "no connection should be opened for the skipped submission")
+}
+
+func TestScriptTemplateRejectsGroupIDsAndUnknownClients(t *testing.T) {
+	scriptPath := filepath.Join(t.TempDir(), "hello.sh")
+	require.NoError(t, ioutil.WriteFile(scriptPath, []byte("echo {{.Name}}"), 0600))
+
+	rw := &ReadWriterMock{}
+	sc := &ScriptsController{
+		ExecutionHelper: &ExecutionHelper{
+			ClientSearch: &ClientSearchMock{clientsToGive: []*models.Client{{ID: "cl10", Name: "web"}}},
+			ReadWriter:   rw,
+		},
+	}
+
+	err := sc.Start(context.Background(), options.New(options.NewMapValuesProvider(map[string]interface{}{
+		Script:         scriptPath,
+		ClientIDs:      "cl1",
+		GroupIDs:       "group1",
+		ScriptTemplate: true,
+	})))
+	assert.EqualError(
+		t,
+		err,
+		"--gids cannot be combined with --template or with --arg and --env without an interpreter, as the script is prepared for each client",
+	)
+
+	err = sc.Start(context.Background(), options.New(options.NewMapValuesProvider(map[string]interface{}{
+		Script:         scriptPath,
+		ClientIDs:      "cl1",
+		ScriptTemplate: true,
+	})))
+	assert.EqualError(t, err, "unknown client 'cl1'")
+
+	assert.Len(t, rw.writtenItems, 0)
+	assert.True(t, rw.isClosed)
+}
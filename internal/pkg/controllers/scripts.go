@@ -6,15 +6,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"path/filepath"
 
 	options "github.com/breathbath/go_utils/v2/pkg/config"
-)
+	io2 "github.com/breathbath/go_utils/v2/pkg/io"
 
-var fileExtInterpreterMap = map[string]string{
-	".ps1": "powershell",
-	".bat": "cmd",
-}
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
 
 type ScriptsController struct {
 	*ExecutionHelper
@@ -26,45 +23,145 @@ func (cc *ScriptsController) Start(ctx context.Context, params *options.Paramete
 		return err
 	}
 
-	info, err := os.Stat(scriptsFilePath)
-	if os.IsNotExist(err) {
-		return fmt.Errorf("script file doesn't exist: %s", scriptsFilePath)
+	scriptContent, err := readScriptFile(scriptsFilePath)
+	if err != nil {
+		cc.closeReadWriter()
+		return err
 	}
-	if info.IsDir() {
-		return fmt.Errorf("script file %s is a directory", scriptsFilePath)
+
+	interpreters := resolveScriptInterpreters(scriptsFilePath, scriptContent, params.ReadString(Interpreter, ""))
+
+	return cc.executeScript(ctx, params, scriptContent, interpreters, nil)
+}
+
+// executeScript runs the script on the clients with the args, env variables and template options from params,
+// clients with different os get separate submissions with the interpreter for their os,
+// adjustInput changes the submissions before sending, e.g. to apply defaults of a library script
+func (eh *ExecutionHelper) executeScript(
+	ctx context.Context,
+	params *options.ParameterBag,
+	scriptContent []byte,
+	interpreters *scriptInterpreters,
+	adjustInput func(wsCmd *models.WsScriptCommand),
+) error {
+	defer eh.closeReadWriter()
+
+	opts, err := readScriptOptions(params)
+	if err != nil {
+		return err
 	}
 
-	scriptFile, err := os.Open(scriptsFilePath)
+	// the os of group members is not known, so they get the same script as the other clients
+	hasGroups := params.ReadString(GroupIDs, "") != ""
+	if hasGroups && opts.isPerClient(interpreters.fallback()) {
+		return fmt.Errorf(
+			"--%s cannot be combined with --%s or with --%s and --%s without an interpreter, as the script is prepared for each client",
+			GroupIDs,
+			ScriptTemplate,
+			ScriptArg,
+			ScriptEnv,
+		)
+	}
+
+	// the clients are looked up only if the script depends on their os or fields
+	if !interpreters.dependsOnOs() && !opts.isPerClient(interpreters.fallback()) {
+		return eh.executeScriptForAll(ctx, params, scriptContent, interpreters.fallback(), opts, adjustInput)
+	}
+
+	clients, err := eh.getClientsWithDetails(ctx, params)
 	if err != nil {
-		return fmt.Errorf("failed to read file %s: %w", scriptsFilePath, err)
+		return err
 	}
 
-	scriptContent, err := ioutil.ReadAll(scriptFile)
+	wsCmds := make([]*models.WsScriptCommand, 0, 1)
+	wsCmdsByScript := map[string]*models.WsScriptCommand{}
+	for _, cl := range clients {
+		interpreter, err := interpreters.forClient(cl)
+		if err != nil {
+			return err
+		}
+
+		clientContent, err := opts.prepare(scriptContent, interpreter, cl)
+		if err != nil {
+			return err
+		}
+
+		// clients with the same interpreter and prepared script are executed with a single submission
+		payload := base64.StdEncoding.EncodeToString(clientContent)
+		scriptKey := interpreter + "\n" + payload
+		if wsCmd, ok := wsCmdsByScript[scriptKey]; ok {
+			wsCmd.ClientIDs = append(wsCmd.ClientIDs, cl.ID)
+			continue
+		}
+
+		wsCmd := eh.buildExecInput(params, []*models.Client{cl}, payload, interpreter)
+		if adjustInput != nil {
+			adjustInput(wsCmd)
+		}
+		wsCmdsByScript[scriptKey] = wsCmd
+		wsCmds = append(wsCmds, wsCmd)
+	}
+
+	if hasGroups && len(wsCmds) > 1 {
+		return fmt.Errorf(
+			"--%s cannot be combined with clients of different os, as the script is submitted for each os separately, use --%s instead",
+			GroupIDs,
+			Interpreter,
+		)
+	}
+
+	return eh.executeInputs(ctx, params, clients, wsCmds)
+}
+
+// executeScriptForAll sends the same script to all clients with a single submission
+func (eh *ExecutionHelper) executeScriptForAll(
+	ctx context.Context,
+	params *options.ParameterBag,
+	scriptContent []byte,
+	interpreter string,
+	opts *scriptOptions,
+	adjustInput func(wsCmd *models.WsScriptCommand),
+) error {
+	clients, err := eh.getClients(ctx, params)
 	if err != nil {
-		return fmt.Errorf("failed to read file %s: %w", scriptsFilePath, err)
+		return err
 	}
 
-	scriptContentBase64 := base64.StdEncoding.EncodeToString(scriptContent)
+	scriptContent, err = opts.prepare(scriptContent, interpreter, nil)
+	if err != nil {
+		return err
+	}
 
-	interpreter := cc.resolveInterpreterByFileName(scriptsFilePath, params.ReadString(Interpreter, ""))
+	wsCmd := eh.buildExecInput(params, clients, base64.StdEncoding.EncodeToString(scriptContent), interpreter)
+	if adjustInput != nil {
+		adjustInput(wsCmd)
+	}
 
-	return cc.execute(ctx, params, scriptContentBase64, interpreter)
+	return eh.executeInput(ctx, params, clients, wsCmd)
 }
 
-func (cc *ScriptsController) resolveInterpreterByFileName(scriptFilePath, scriptsFilePathFromArgs string) string {
-	if scriptsFilePathFromArgs != "" {
-		return scriptsFilePathFromArgs
+func readScriptFile(scriptsFilePath string) ([]byte, error) {
+	info, err := os.Stat(scriptsFilePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("script file doesn't exist: %s", scriptsFilePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("script file %s is a directory", scriptsFilePath)
 	}
 
-	extension := filepath.Ext(scriptFilePath)
-	if extension == "" {
-		return ""
+	scriptFile, err := os.Open(scriptsFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", scriptsFilePath, err)
 	}
+	defer io2.CloseResourceSecure("script file", scriptFile)
 
-	interpreter, ok := fileExtInterpreterMap[extension]
-	if !ok {
-		return ""
+	scriptContent, err := ioutil.ReadAll(scriptFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", scriptsFilePath, err)
 	}
 
-	return interpreter
+	return scriptContent, nil
 }
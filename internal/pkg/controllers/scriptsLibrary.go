@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+const (
+	ScriptNewName = "new-name"
+	ScriptTag     = "tag"
+)
+
+type ScriptsLibraryAPI interface {
+	Scripts(ctx context.Context) (*api.ScriptsResponse, error)
+	Script(ctx context.Context, scriptID string) (*api.ScriptResponse, error)
+	CreateScript(ctx context.Context, script *models.Script) (*api.ScriptResponse, error)
+	UpdateScript(ctx context.Context, script *models.Script) (*api.ScriptResponse, error)
+	DeleteScript(ctx context.Context, scriptID string) error
+}
+
+type ScriptRenderer interface {
+	RenderScripts(scripts []*models.Script) error
+	RenderScript(script *models.Script) error
+	RenderStatus(s output.KvProvider) error
+}
+
+// ScriptsLibraryController manages scripts stored on the rport server and executes them by id or name
+type ScriptsLibraryController struct {
+	*ExecutionHelper
+	Rport          ScriptsLibraryAPI
+	ScriptRenderer ScriptRenderer
+}
+
+func (slc *ScriptsLibraryController) List(ctx context.Context) error {
+	scriptsResp, err := slc.Rport.Scripts(ctx)
+	if err != nil {
+		return err
+	}
+
+	return slc.ScriptRenderer.RenderScripts(scriptsResp.Data)
+}
+
+func (slc *ScriptsLibraryController) Get(ctx context.Context, idOrName string) error {
+	script, err := slc.findScript(ctx, idOrName)
+	if err != nil {
+		return err
+	}
+
+	return slc.ScriptRenderer.RenderScript(script)
+}
+
+func (slc *ScriptsLibraryController) Create(ctx context.Context, name string, params *options.ParameterBag) error {
+	scriptFilePath, err := params.ReadRequiredString(Script)
+	if err != nil {
+		return err
+	}
+
+	content, err := readScriptFile(scriptFilePath)
+	if err != nil {
+		return err
+	}
+
+	script := &models.Script{
+		Name:        name,
+		Interpreter: resolveInterpreter(scriptFilePath, content, params.ReadString(Interpreter, "")),
+		IsSudo:      params.ReadBool(IsSudo, false),
+		Cwd:         params.ReadString(Cwd, ""),
+		Tags:        parseScriptTags(params.ReadStrings(ScriptTag)),
+		Content:     string(content),
+	}
+
+	scriptResp, err := slc.Rport.CreateScript(ctx, script)
+	if err != nil {
+		return err
+	}
+
+	status := fmt.Sprintf("Script '%s' created", name)
+	if scriptResp.Data != nil && scriptResp.Data.ID != "" {
+		status += fmt.Sprintf(" with id %s", scriptResp.Data.ID)
+	}
+
+	return slc.ScriptRenderer.RenderStatus(&models.OperationStatus{Status: status})
+}
+
+// Update changes only the provided fields, all tags are replaced if at least one tag is provided
+func (slc *ScriptsLibraryController) Update(ctx context.Context, idOrName string, params *options.ParameterBag) error {
+	script, err := slc.findScript(ctx, idOrName)
+	if err != nil {
+		return err
+	}
+
+	if newName := params.ReadString(ScriptNewName, ""); newName != "" {
+		script.Name = newName
+	}
+
+	if scriptFilePath := params.ReadString(Script, ""); scriptFilePath != "" {
+		content, e := readScriptFile(scriptFilePath)
+		if e != nil {
+			return e
+		}
+		script.Content = string(content)
+	}
+
+	if interpreter, found := params.Read(Interpreter, ""); found {
+		script.Interpreter = fmt.Sprint(interpreter)
+	}
+
+	if _, found := params.Read(IsSudo, false); found {
+		script.IsSudo = params.ReadBool(IsSudo, false)
+	}
+
+	if cwd, found := params.Read(Cwd, ""); found {
+		script.Cwd = fmt.Sprint(cwd)
+	}
+
+	tags := params.ReadStrings(ScriptTag)
+	if len(tags) > 0 {
+		script.Tags = parseScriptTags(tags)
+	}
+
+	_, err = slc.Rport.UpdateScript(ctx, script)
+	if err != nil {
+		return err
+	}
+
+	return slc.ScriptRenderer.RenderStatus(&models.OperationStatus{
+		Status: fmt.Sprintf("Script '%s' updated", script.Name),
+	})
+}
+
+func (slc *ScriptsLibraryController) Delete(ctx context.Context, idOrName string) error {
+	script, err := slc.findScript(ctx, idOrName)
+	if err != nil {
+		return err
+	}
+
+	err = slc.Rport.DeleteScript(ctx, script.ID)
+	if err != nil {
+		return err
+	}
+
+	return slc.ScriptRenderer.RenderStatus(&models.OperationStatus{
+		Status: fmt.Sprintf("Script '%s' deleted", script.Name),
+	})
+}
+
+// Run executes a library script on the clients, the interpreter and cwd of the script are used unless
+// they are provided, sudo is used if either the script or the params require it
+func (slc *ScriptsLibraryController) Run(ctx context.Context, idOrName string, params *options.ParameterBag) error {
+	script, err := slc.findScript(ctx, idOrName)
+	if err != nil {
+		slc.closeReadWriter()
+		return err
+	}
+
+	interpreter := params.ReadString(Interpreter, "")
+	if interpreter == "" {
+		interpreter = script.Interpreter
+	}
+	interpreters := resolveScriptInterpreters("", []byte(script.Content), interpreter)
+
+	return slc.executeScript(ctx, params, []byte(script.Content), interpreters, func(wsCmd *models.WsScriptCommand) {
+		if wsCmd.Cwd == "" {
+			wsCmd.Cwd = script.Cwd
+		}
+		wsCmd.IsSudo = wsCmd.IsSudo || script.IsSudo
+	})
+}
+
+// findScript gives a library script with its content by id or by name, as names are not unique,
+// a name of multiple scripts is rejected
+func (slc *ScriptsLibraryController) findScript(ctx context.Context, idOrName string) (*models.Script, error) {
+	scriptsResp, err := slc.Rport.Scripts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]namedEntry, 0, len(scriptsResp.Data))
+	for _, script := range scriptsResp.Data {
+		entries = append(entries, namedEntry{ID: script.ID, Name: script.Name})
+	}
+
+	scriptID, err := findIDByIDOrName(entries, idOrName, "script")
+	if err != nil {
+		return nil, err
+	}
+
+	scriptResp, err := slc.Rport.Script(ctx, scriptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if scriptResp.Data == nil {
+		return nil, fmt.Errorf("script '%s' not found", idOrName)
+	}
+
+	return scriptResp.Data, nil
+}
+
+// parseScriptTags splits comma separated tags
+func parseScriptTags(tagExpressions []string) []string {
+	tags := make([]string, 0, len(tagExpressions))
+	for _, expr := range tagExpressions {
+		for _, tag := range strings.Split(expr, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return tags
+}
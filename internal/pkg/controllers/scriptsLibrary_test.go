@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/output"
+)
+
+type ScriptsLibraryAPIMock struct {
+	mock.Mock
+}
+
+func (slam *ScriptsLibraryAPIMock) Scripts(ctx context.Context) (*api.ScriptsResponse, error) {
+	args := slam.Called(ctx)
+
+	return args.Get(0).(*api.ScriptsResponse), args.Error(1)
+}
+
+func (slam *ScriptsLibraryAPIMock) Script(ctx context.Context, scriptID string) (*api.ScriptResponse, error) {
+	args := slam.Called(ctx, scriptID)
+
+	return args.Get(0).(*api.ScriptResponse), args.Error(1)
+}
+
+func (slam *ScriptsLibraryAPIMock) CreateScript(ctx context.Context, script *models.Script) (*api.ScriptResponse, error) {
+	args := slam.Called(ctx, script)
+
+	return args.Get(0).(*api.ScriptResponse), args.Error(1)
+}
+
+func (slam *ScriptsLibraryAPIMock) UpdateScript(ctx context.Context, script *models.Script) (*api.ScriptResponse, error) {
+	args := slam.Called(ctx, script)
+
+	return args.Get(0).(*api.ScriptResponse), args.Error(1)
+}
+
+func (slam *ScriptsLibraryAPIMock) DeleteScript(ctx context.Context, scriptID string) error {
+	args := slam.Called(ctx, scriptID)
+
+	return args.Error(0)
+}
+
+type ScriptRendererMock struct {
+	mock.Mock
+}
+
+func (srm *ScriptRendererMock) RenderScripts(scripts []*models.Script) error {
+	args := srm.Called(scripts)
+
+	return args.Error(0)
+}
+
+func (srm *ScriptRendererMock) RenderScript(script *models.Script) error {
+	args := srm.Called(script)
+
+	return args.Error(0)
+}
+
+func (srm *ScriptRendererMock) RenderStatus(s output.KvProvider) error {
+	args := srm.Called(s)
+
+	return args.Error(0)
+}
+
+func newScriptsLibraryAPIMock() *ScriptsLibraryAPIMock {
+	apiMock := &ScriptsLibraryAPIMock{}
+	apiMock.On("Scripts", mock.Anything).Return(&api.ScriptsResponse{Data: []*models.Script{
+		{ID: "1", Name: "uptime"},
+	}}, nil)
+
+	return apiMock
+}
+
+func TestCreateScript(t *testing.T) {
+	scriptFilePath := filepath.Join(t.TempDir(), "cleanup.ps1")
+	require.NoError(t, ioutil.WriteFile(scriptFilePath, []byte("Remove-Item C:\\tmp\\*"), 0600))
+
+	apiMock := &ScriptsLibraryAPIMock{}
+	apiMock.On("CreateScript", mock.Anything, mock.Anything).Return(&api.ScriptResponse{Data: &models.Script{ID: "4"}}, nil)
+
+	renderMock := &ScriptRendererMock{}
+	renderMock.On("RenderStatus", mock.Anything).Return(nil)
+
+	slc := &ScriptsLibraryController{Rport: apiMock, ScriptRenderer: renderMock}
+
+	err := slc.Create(context.Background(), "cleanup", config.FromValues(map[string]string{
+		Script:    scriptFilePath,
+		IsSudo:    "true",
+		Cwd:       "C:\\tmp",
+		ScriptTag: "windows, maintenance",
+	}))
+	require.NoError(t, err)
+
+	apiMock.AssertCalled(t, "CreateScript", mock.Anything, &models.Script{
+		Name:        "cleanup",
+		Interpreter: "powershell",
+		IsSudo:      true,
+		Cwd:         "C:\\tmp",
+		Tags:        []string{"windows", "maintenance"},
+		Content:     "Remove-Item C:\\tmp\\*",
+	})
+	renderMock.AssertCalled(t, "RenderStatus", &models.OperationStatus{Status: "Script 'cleanup' created with id 4"})
+}
+
+func TestCreateScriptWithoutFile(t *testing.T) {
+	slc := &ScriptsLibraryController{Rport: &ScriptsLibraryAPIMock{}, ScriptRenderer: &ScriptRendererMock{}}
+
+	err := slc.Create(context.Background(), "cleanup", config.FromValues(map[string]string{}))
+	assert.Error(t, err)
+}
+
+func TestUpdateScriptChangesOnlyProvidedFields(t *testing.T) {
+	apiMock := newScriptsLibraryAPIMock()
+	apiMock.On("Script", mock.Anything, "1").Return(&api.ScriptResponse{Data: &models.Script{
+		ID:          "1",
+		Name:        "uptime",
+		Interpreter: "/bin/sh",
+		Cwd:         "/tmp",
+		Tags:        []string{"linux"},
+		Content:     "uptime",
+	}}, nil)
+	apiMock.On("UpdateScript", mock.Anything, mock.Anything).Return(&api.ScriptResponse{}, nil)
+
+	renderMock := &ScriptRendererMock{}
+	renderMock.On("RenderStatus", mock.Anything).Return(nil)
+
+	slc := &ScriptsLibraryController{Rport: apiMock, ScriptRenderer: renderMock}
+
+	err := slc.Update(context.Background(), "uptime", config.FromValues(map[string]string{
+		ScriptNewName: "load",
+		IsSudo:        "true",
+	}))
+	require.NoError(t, err)
+
+	apiMock.AssertCalled(t, "UpdateScript", mock.Anything, &models.Script{
+		ID:          "1",
+		Name:        "load",
+		Interpreter: "/bin/sh",
+		IsSudo:      true,
+		Cwd:         "/tmp",
+		Tags:        []string{"linux"},
+		Content:     "uptime",
+	})
+	renderMock.AssertCalled(t, "RenderStatus", &models.OperationStatus{Status: "Script 'load' updated"})
+}
+
+func TestDeleteScript(t *testing.T) {
+	apiMock := newScriptsLibraryAPIMock()
+	apiMock.On("Script", mock.Anything, "1").Return(&api.ScriptResponse{Data: &models.Script{ID: "1", Name: "uptime"}}, nil)
+	apiMock.On("DeleteScript", mock.Anything, "1").Return(nil)
+
+	renderMock := &ScriptRendererMock{}
+	renderMock.On("RenderStatus", mock.Anything).Return(nil)
+
+	slc := &ScriptsLibraryController{Rport: apiMock, ScriptRenderer: renderMock}
+
+	err := slc.Delete(context.Background(), "uptime")
+	require.NoError(t, err)
+
+	apiMock.AssertExpectations(t)
+	renderMock.AssertCalled(t, "RenderStatus", &models.OperationStatus{Status: "Script 'uptime' deleted"})
+}
+
+func TestRunLibraryScript(t *testing.T) {
+	apiMock := newScriptsLibraryAPIMock()
+	apiMock.On("Script", mock.Anything, "1").Return(&api.ScriptResponse{Data: &models.Script{
+		ID:          "1",
+		Name:        "uptime",
+		Interpreter: "/bin/bash",
+		IsSudo:      true,
+		Cwd:         "/tmp",
+		Content:     "uptime",
+	}}, nil)
+
+	jobBytes, err := json.Marshal(models.Job{Jid: "123", ClientID: "cl1", Status: models.JobStatusSuccessful})
+	require.NoError(t, err)
+
+	rw := &ReadWriterMock{
+		itemsToRead: []ReadChunk{{Output: jobBytes}, {Err: io.EOF}},
+	}
+	jr := &JobRendererMock{}
+
+	slc := &ScriptsLibraryController{
+		ExecutionHelper: &ExecutionHelper{
+			ClientSearch: &ClientSearchMock{clientsToGive: []*models.Client{{ID: "cl1"}}},
+			ReadWriter:   rw,
+			JobRenderer:  jr,
+		},
+		Rport:          apiMock,
+		ScriptRenderer: &ScriptRendererMock{},
+	}
+
+	err = slc.Run(context.Background(), "uptime", config.FromValues(map[string]string{
+		ClientIDs: "cl1",
+		Cwd:       "/home",
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, rw.writtenItems, 1)
+	var wsCmd models.WsScriptCommand
+	require.NoError(t, json.Unmarshal([]byte(rw.writtenItems[0]), &wsCmd))
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("uptime")), wsCmd.Script)
+	assert.Equal(t, "/bin/bash", wsCmd.Interpreter)
+	assert.Equal(t, "/home", wsCmd.Cwd, "provided cwd should be used instead of the one of the script")
+	assert.True(t, wsCmd.IsSudo)
+	assert.Equal(t, []string{"cl1"}, wsCmd.ClientIDs)
+
+	assert.Equal(t, "123", jr.jobToRender.Jid)
+	assert.True(t, rw.isClosed)
+}
+
+func TestRunUnknownLibraryScript(t *testing.T) {
+	rw := &ReadWriterMock{}
+	slc := &ScriptsLibraryController{
+		ExecutionHelper: &ExecutionHelper{ReadWriter: rw},
+		Rport:           newScriptsLibraryAPIMock(),
+	}
+
+	err := slc.Run(context.Background(), "reboot", config.FromValues(map[string]string{
+		ClientIDs: "cl1",
+	}))
+	assert.EqualError(t, err, "script 'reboot' not found")
+	assert.Len(t, rw.writtenItems, 0)
+	assert.True(t, rw.isClosed)
+}
+
+func TestParseScriptTags(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, parseScriptTags([]string{"a, b", "", "c,"}))
+	assert.Equal(t, []string{}, parseScriptTags(nil))
+}
@@ -153,6 +153,10 @@ func TestScriptExecutionByClientIDsSuccess(t *testing.T) {
 			jobToGive := buildJob()
 			sc, rw, jr, err := buildScriptController(jobToGive)
 			require.NoError(t, err)
+			if tc.commandToExpect != nil {
+				// the clients are looked up to validate the interpreter of single os scripts
+				sc.ClientSearch = &ClientSearchMock{clientsToGive: []*models.Client{{ID: tc.commandToExpect.ClientIDs[0]}}}
+			}
 
 			err = sc.Start(context.Background(), paramsContainer)
 			if tc.errorToExpect != "" {
@@ -238,8 +242,9 @@ func buildScriptController(j *models.Job) (*ScriptsController, *ReadWriterMock,
 
 	return &ScriptsController{
 		ExecutionHelper: &ExecutionHelper{
-			ReadWriter:  rw,
-			JobRenderer: jr,
+			ClientSearch: &ClientSearchMock{clientsToGive: []*models.Client{{ID: j.ClientID}}},
+			ReadWriter:   rw,
+			JobRenderer:  jr,
 		},
 	}, rw, jr, nil
 }
@@ -0,0 +1,471 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+	io2 "github.com/breathbath/go_utils/v2/pkg/io"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/client"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+)
+
+const (
+	shellExitCommand   = "exit"
+	shellEscapePrefix  = "!"
+	shellChangeDirName = "cd"
+	shellHelp          = `exit, !exit         leave the shell
+!help               show this help
+!history            show the entered commands
+!sudo on|off        execute the next commands with sudo or without it
+!timeout <SECONDS>  set the timeout of the next commands
+!<COMMAND>          execute the command on the local machine
+`
+)
+
+var (
+	errShellCommandInterrupted = errors.New("command interrupted")
+	errShellCommandAborted     = errors.New("command aborted")
+)
+
+type ShellLineReader interface {
+	ReadLine(prompt string) (string, error)
+}
+
+type ReadWriterProvider func(ctx context.Context) (ReadWriter, error)
+
+type LocalCommandRunner func(ctx context.Context, command string) error
+
+// ShellController executes commands entered one by one on a client keeping the working directory between them,
+// rport closes the websocket after sending the job result, so the connection for the next command is opened
+// while the user enters it
+type ShellController struct {
+	*ExecutionHelper
+	Rport              JobsAPI
+	ReadWriterProvider ReadWriterProvider
+	LineReader         ShellLineReader
+	LocalCommandRunner LocalCommandRunner
+	Interrupts         <-chan os.Signal
+	Writer             io.Writer
+	nextConnection     <-chan *shellConnection
+}
+
+type shellConnection struct {
+	rw  ReadWriter
+	err error
+}
+
+type shellSession struct {
+	client      *models.Client
+	cwd         string
+	isSudo      bool
+	timeoutSec  int
+	interpreter string
+	history     []string
+}
+
+type wsReadResult struct {
+	msg []byte
+	err error
+}
+
+// Start opens a shell on the client identified by the id or name given in the term or in the params
+func (sc *ShellController) Start(ctx context.Context, clientTerm string, params *options.ParameterBag) error {
+	if clientTerm == "" {
+		clientTerm = params.ReadString(ClientNameFlag, "")
+	}
+	if clientTerm == "" && !client.HasFilters(params) {
+		return errors.New("no client id nor name nor filter provided")
+	}
+
+	cl, err := sc.ClientSearch.FindOne(ctx, clientTerm, params)
+	if err != nil {
+		return err
+	}
+
+	session := &shellSession{
+		client:      cl,
+		cwd:         params.ReadString(Cwd, ""),
+		isSudo:      params.ReadBool(IsSudo, false),
+		timeoutSec:  params.ReadInt(Timeout, DefaultCmdTimeoutSeconds),
+		interpreter: params.ReadString(Interpreter, ""),
+	}
+
+	sc.nextConnection = sc.connect(ctx)
+	defer sc.closeNextConnection()
+
+	_, err = fmt.Fprintf(sc.Writer, "Connected to client %s, type !help for help or exit to leave\n", session.clientTitle())
+	if err != nil {
+		return err
+	}
+
+	if session.cwd == "" {
+		err = sc.updateCwd(ctx, session, session.printCwdCommand())
+		if err != nil {
+			logrus.Warnf("failed to read the working directory: %v", err)
+		}
+	}
+
+	for {
+		line, err := sc.LineReader.ReadLine(session.prompt())
+		if err == utils.ErrLineInterrupted {
+			continue
+		}
+		if err == io.EOF {
+			_, err = fmt.Fprintln(sc.Writer)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		session.history = append(session.history, line)
+
+		isExit, err := sc.handleLine(ctx, session, line)
+		if err != nil {
+			_, err = fmt.Fprintln(sc.Writer, err)
+			if err != nil {
+				return err
+			}
+		}
+		if isExit {
+			return nil
+		}
+	}
+}
+
+func (sc *ShellController) handleLine(ctx context.Context, s *shellSession, line string) (isExit bool, err error) {
+	if line == shellExitCommand {
+		return true, nil
+	}
+
+	if strings.HasPrefix(line, shellEscapePrefix) {
+		return sc.handleEscape(ctx, s, strings.TrimSpace(strings.TrimPrefix(line, shellEscapePrefix)))
+	}
+
+	fields := strings.Fields(line)
+	if fields[0] == shellChangeDirName {
+		target := strings.TrimSpace(strings.TrimPrefix(line, shellChangeDirName))
+		return false, sc.updateCwd(ctx, s, s.changeDirCommand(target))
+	}
+
+	job, err := sc.executeCommand(ctx, s, line)
+	if err != nil {
+		return false, err
+	}
+
+	return false, sc.JobRenderer.RenderJob(job)
+}
+
+func (sc *ShellController) handleEscape(ctx context.Context, s *shellSession, escape string) (isExit bool, err error) {
+	fields := strings.Fields(escape)
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	switch fields[0] {
+	case shellExitCommand, "quit":
+		return true, nil
+	case "help":
+		_, err = io.WriteString(sc.Writer, shellHelp)
+	case "history":
+		for i, line := range s.history {
+			_, err = fmt.Fprintf(sc.Writer, "%5d  %s\n", i+1, line)
+			if err != nil {
+				return false, err
+			}
+		}
+	case "sudo":
+		if len(fields) != 2 || (fields[1] != "on" && fields[1] != "off") {
+			return false, errors.New("usage: !sudo on|off")
+		}
+		s.isSudo = fields[1] == "on"
+	case "timeout":
+		var timeoutSec int
+		if len(fields) == 2 {
+			timeoutSec, err = strconv.Atoi(fields[1])
+		}
+		if len(fields) != 2 || err != nil || timeoutSec <= 0 {
+			return false, errors.New("usage: !timeout <SECONDS>")
+		}
+		s.timeoutSec = timeoutSec
+	default:
+		err = sc.LocalCommandRunner(ctx, escape)
+	}
+
+	return false, err
+}
+
+// updateCwd runs a command which changes and prints the working directory and keeps it for the next commands
+func (sc *ShellController) updateCwd(ctx context.Context, s *shellSession, command string) error {
+	job, err := sc.executeCommand(ctx, s, command)
+	if err != nil {
+		return err
+	}
+
+	if job.Status != models.JobStatusSuccessful {
+		return sc.JobRenderer.RenderJob(job)
+	}
+
+	lines := strings.Split(strings.TrimSpace(job.Result.Stdout), "\n")
+	cwd := strings.TrimSpace(lines[len(lines)-1])
+	if cwd == "" {
+		return fmt.Errorf("no working directory received from the client")
+	}
+	s.cwd = cwd
+
+	return nil
+}
+
+// executeCommand runs the command on the client, Ctrl-C aborts it by killing its process on the client
+func (sc *ShellController) executeCommand(ctx context.Context, s *shellSession, command string) (*models.Job, error) {
+	job, err := sc.runCommand(ctx, s, command)
+	if err == errShellCommandInterrupted {
+		return nil, sc.abortCommand(ctx, s, command, job)
+	}
+
+	return job, err
+}
+
+// abortCommand kills the process of the interrupted command, as rport has no api to cancel a running job,
+// the pid is taken from the running job sent by rport or from the running jobs of the client
+func (sc *ShellController) abortCommand(ctx context.Context, s *shellSession, command string, runningJob *models.Job) error {
+	pid := 0
+	if runningJob != nil {
+		pid = runningJob.Pid
+	}
+	if pid == 0 {
+		var err error
+		pid, err = sc.findRunningPid(ctx, s, command)
+		if err != nil {
+			return fmt.Errorf("failed to abort the command: %v", err)
+		}
+	}
+
+	killJob, err := sc.runCommand(ctx, s, s.killCommand(pid))
+	if err != nil {
+		return fmt.Errorf("failed to abort the command: %v", err)
+	}
+	if killJob.Status != models.JobStatusSuccessful {
+		return fmt.Errorf("failed to abort the command, killing its process %d finished with status %s", pid, killJob.Status)
+	}
+
+	return errShellCommandAborted
+}
+
+// findRunningPid gives the pid of the latest running job of the command on the client
+func (sc *ShellController) findRunningPid(ctx context.Context, s *shellSession, command string) (int, error) {
+	jobsResp, err := sc.Rport.ClientJobs(ctx, s.client.ID, models.JobStatusRunning)
+	if err != nil {
+		return 0, err
+	}
+
+	var latestJob *models.Job
+	for _, job := range jobsResp.Data {
+		if job.Command != command || job.Pid == 0 {
+			continue
+		}
+		if latestJob == nil || job.StartedAt.After(latestJob.StartedAt) {
+			latestJob = job
+		}
+	}
+	if latestJob == nil {
+		return 0, errors.New("no running job of the command found on the client")
+	}
+
+	return latestJob.Pid, nil
+}
+
+// runCommand sends the command on the prepared connection and waits for its result, on Ctrl-C it stops waiting
+// and gives the last running job received for the command if any
+func (sc *ShellController) runCommand(ctx context.Context, s *shellSession, command string) (*models.Job, error) {
+	if sc.nextConnection == nil {
+		sc.nextConnection = sc.connect(ctx)
+	}
+	conn := <-sc.nextConnection
+	sc.nextConnection = nil
+	if conn.err != nil {
+		return nil, conn.err
+	}
+
+	sc.ReadWriter = conn.rw
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		io2.CloseResourceSecure("read writer", conn.rw)
+		sc.ReadWriter = nil
+		sc.nextConnection = sc.connect(ctx)
+	}()
+
+	sc.drainInterrupts()
+
+	err := sc.sendCommand(&models.WsScriptCommand{
+		ClientIDs:   []string{s.client.ID},
+		Command:     command,
+		Cwd:         s.cwd,
+		IsSudo:      s.isSudo,
+		TimeoutSec:  s.timeoutSec,
+		Interpreter: s.interpreter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan wsReadResult)
+	go func() {
+		for {
+			msg, err := conn.rw.Read()
+			select {
+			case results <- wsReadResult{msg: msg, err: err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var runningJob *models.Job
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-sc.Interrupts:
+			return runningJob, errShellCommandInterrupted
+		case res := <-results:
+			if res.err == io.EOF {
+				return nil, errors.New("connection to rport closed before the command finished")
+			}
+			if res.err != nil {
+				return nil, res.err
+			}
+
+			job, err := parseJobMessage(res.msg)
+			if err != nil {
+				return nil, err
+			}
+			if job.Status != models.JobStatusRunning {
+				return job, nil
+			}
+			runningJob = job
+		}
+	}
+}
+
+func (sc *ShellController) connect(ctx context.Context) <-chan *shellConnection {
+	connChan := make(chan *shellConnection, 1)
+	go func() {
+		rw, err := sc.ReadWriterProvider(ctx)
+		connChan <- &shellConnection{rw: rw, err: err}
+	}()
+
+	return connChan
+}
+
+func (sc *ShellController) closeNextConnection() {
+	if sc.nextConnection == nil {
+		return
+	}
+
+	go func(connChan <-chan *shellConnection) {
+		conn := <-connChan
+		if conn.err == nil {
+			io2.CloseResourceSecure("read writer", conn.rw)
+		}
+	}(sc.nextConnection)
+	sc.nextConnection = nil
+}
+
+// drainInterrupts skips Ctrl-C signals which were received before the command e.g. by a local command
+func (sc *ShellController) drainInterrupts() {
+	for {
+		select {
+		case <-sc.Interrupts:
+		default:
+			return
+		}
+	}
+}
+
+func (s *shellSession) clientTitle() string {
+	if s.client.Name == "" {
+		return s.client.ID
+	}
+
+	return fmt.Sprintf("%s (%s)", s.client.Name, s.client.ID)
+}
+
+func (s *shellSession) prompt() string {
+	name := s.client.Name
+	if name == "" {
+		name = s.client.ID
+	}
+
+	if s.isWindows() {
+		return fmt.Sprintf("%s %s> ", name, s.cwd)
+	}
+
+	if s.cwd == "" {
+		return name + "$ "
+	}
+
+	return fmt.Sprintf("%s:%s$ ", name, s.cwd)
+}
+
+func (s *shellSession) isWindows() bool {
+	return strings.EqualFold(s.client.OsKernel, "windows") || strings.EqualFold(s.client.OsFamily, "windows")
+}
+
+func (s *shellSession) isPowerShell() bool {
+	return strings.HasPrefix(strings.ToLower(s.interpreter), "powershell") || strings.EqualFold(s.interpreter, "pwsh")
+}
+
+func (s *shellSession) printCwdCommand() string {
+	switch {
+	case s.isPowerShell():
+		return "(Get-Location).Path"
+	case s.isWindows():
+		return "cd"
+	default:
+		return "pwd"
+	}
+}
+
+// killCommand gives the command which terminates the process with the pid and its child processes
+func (s *shellSession) killCommand(pid int) string {
+	if s.isWindows() {
+		return fmt.Sprintf("taskkill /F /T /PID %d", pid)
+	}
+
+	return fmt.Sprintf("pkill -TERM -P %d; kill -TERM %d", pid, pid)
+}
+
+func (s *shellSession) changeDirCommand(target string) string {
+	if target == "" && s.isWindows() {
+		return s.printCwdCommand()
+	}
+
+	switch {
+	case s.isPowerShell():
+		return fmt.Sprintf("Set-Location %s; (Get-Location).Path", target)
+	case s.isWindows():
+		return fmt.Sprintf("cd /d %s && cd", target)
+	case target == "":
+		return "cd && pwd"
+	default:
+		return fmt.Sprintf("cd %s && pwd", target)
+	}
+}
@@ -0,0 +1,316 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+)
+
+type ShellLineReaderMock struct {
+	lines   []string
+	prompts []string
+}
+
+func (slr *ShellLineReaderMock) ReadLine(prompt string) (string, error) {
+	slr.prompts = append(slr.prompts, prompt)
+	if len(slr.lines) == 0 {
+		return "", io.EOF
+	}
+
+	line := slr.lines[0]
+	slr.lines = slr.lines[1:]
+	if line == "^C" {
+		return "", utils.ErrLineInterrupted
+	}
+
+	return line, nil
+}
+
+// shellConnectionsMock gives a new connection for each command, which replies with the next job
+type shellConnectionsMock struct {
+	jobs        []*models.Job
+	connections []*ReadWriterMock
+	mu          sync.Mutex
+}
+
+func (scm *shellConnectionsMock) provide(ctx context.Context) (ReadWriter, error) {
+	scm.mu.Lock()
+	defer scm.mu.Unlock()
+
+	rw := &ReadWriterMock{itemsToRead: []ReadChunk{{Err: io.EOF}}}
+	if len(scm.jobs) > 0 {
+		jobBytes, err := json.Marshal(scm.jobs[0])
+		if err != nil {
+			return nil, err
+		}
+		scm.jobs = scm.jobs[1:]
+		rw.itemsToRead = []ReadChunk{{Output: jobBytes}, {Err: io.EOF}}
+	}
+	scm.connections = append(scm.connections, rw)
+
+	return rw, nil
+}
+
+func (scm *shellConnectionsMock) sentCommands(t *testing.T) []*models.WsScriptCommand {
+	scm.mu.Lock()
+	defer scm.mu.Unlock()
+
+	var commands []*models.WsScriptCommand
+	for _, rw := range scm.connections {
+		for _, item := range rw.writtenItems {
+			wsCmd := &models.WsScriptCommand{}
+			err := json.Unmarshal([]byte(item), wsCmd)
+			require.NoError(t, err)
+			commands = append(commands, wsCmd)
+		}
+	}
+
+	return commands
+}
+
+// blockingReadWriterMock gives the running job if any, then simulates Ctrl-C and waits for the result until it's closed
+type blockingReadWriterMock struct {
+	interrupts   chan os.Signal
+	closed       chan struct{}
+	runningJob   []byte
+	writtenItems []string
+}
+
+func (brw *blockingReadWriterMock) Read() ([]byte, error) {
+	if brw.runningJob != nil {
+		runningJob := brw.runningJob
+		brw.runningJob = nil
+		return runningJob, nil
+	}
+
+	brw.interrupts <- syscall.SIGINT
+	<-brw.closed
+	return nil, io.EOF
+}
+
+func (brw *blockingReadWriterMock) Write(msg []byte) (int, error) {
+	brw.writtenItems = append(brw.writtenItems, string(msg))
+	return len(msg), nil
+}
+
+func (brw *blockingReadWriterMock) Close() error {
+	close(brw.closed)
+	return nil
+}
+
+func TestShellSession(t *testing.T) {
+	connections := &shellConnectionsMock{
+		jobs: []*models.Job{
+			{Jid: "j1", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "/home/user\n"}},
+			{Jid: "j2", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "/tmp\n"}},
+			{Jid: "j3", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "file1\nfile2\n"}},
+			{Jid: "j4", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "root\n"}},
+		},
+	}
+	lineReader := &ShellLineReaderMock{
+		lines: []string{"cd /tmp", "", "^C", "ls", "!sudo on", "!timeout 60", "whoami", "!history", "!echo local", "exit"},
+	}
+	jr := &JobRendererMock{}
+	var localCommand string
+	buf := &bytes.Buffer{}
+
+	sc := &ShellController{
+		ExecutionHelper: &ExecutionHelper{
+			JobRenderer: jr,
+			ClientSearch: &ClientSearchMock{
+				clientsToGive: []*models.Client{{ID: "cl1", Name: "server1", OsKernel: "linux"}},
+			},
+		},
+		ReadWriterProvider: connections.provide,
+		LineReader:         lineReader,
+		LocalCommandRunner: func(ctx context.Context, command string) error {
+			localCommand = command
+			return nil
+		},
+		Writer: buf,
+	}
+
+	err := sc.Start(context.Background(), "server1", config.FromValues(map[string]string{}))
+	require.NoError(t, err)
+
+	sentCommands := connections.sentCommands(t)
+	require.Len(t, sentCommands, 4)
+
+	assert.Equal(t, "pwd", sentCommands[0].Command)
+	assert.Equal(t, []string{"cl1"}, sentCommands[0].ClientIDs)
+	assert.Equal(t, "", sentCommands[0].Cwd)
+	assert.Equal(t, DefaultCmdTimeoutSeconds, sentCommands[0].TimeoutSec)
+
+	assert.Equal(t, "cd /tmp && pwd", sentCommands[1].Command)
+	assert.Equal(t, "/home/user", sentCommands[1].Cwd)
+
+	assert.Equal(t, "ls", sentCommands[2].Command)
+	assert.Equal(t, "/tmp", sentCommands[2].Cwd)
+	assert.False(t, sentCommands[2].IsSudo)
+
+	assert.Equal(t, "whoami", sentCommands[3].Command)
+	assert.Equal(t, "/tmp", sentCommands[3].Cwd)
+	assert.True(t, sentCommands[3].IsSudo)
+	assert.Equal(t, 60, sentCommands[3].TimeoutSec)
+
+	assert.Equal(t, "root\n", jr.jobToRender.Result.Stdout)
+	assert.Equal(t, "echo local", localCommand)
+	assert.Equal(t, "server1:/home/user$ ", lineReader.prompts[0])
+	assert.Equal(t, "server1:/tmp$ ", lineReader.prompts[1])
+
+	expectedOutput := `Connected to client server1 (cl1), type !help for help or exit to leave
+    1  cd /tmp
+    2  ls
+    3  !sudo on
+    4  !timeout 60
+    5  whoami
+    6  !history
+`
+	assert.Equal(t, expectedOutput, buf.String())
+}
+
+func TestShellWindowsChangeDir(t *testing.T) {
+	connections := &shellConnectionsMock{
+		jobs: []*models.Job{
+			{Jid: "j5", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "C:\\Users\\admin\r\n"}},
+			{Jid: "j6", Status: models.JobStatusFailed, Result: models.JobResult{Stderr: "The system cannot find the path specified."}},
+		},
+	}
+	lineReader := &ShellLineReaderMock{lines: []string{"cd missing"}}
+	jr := &JobRendererMock{}
+
+	sc := &ShellController{
+		ExecutionHelper: &ExecutionHelper{
+			JobRenderer: jr,
+			ClientSearch: &ClientSearchMock{
+				clientsToGive: []*models.Client{{ID: "cl2", Name: "win1", OsFamily: "windows"}},
+			},
+		},
+		ReadWriterProvider: connections.provide,
+		LineReader:         lineReader,
+		Writer:             &bytes.Buffer{},
+	}
+
+	err := sc.Start(context.Background(), "win1", config.FromValues(map[string]string{}))
+	require.NoError(t, err)
+
+	sentCommands := connections.sentCommands(t)
+	require.Len(t, sentCommands, 2)
+	assert.Equal(t, "cd", sentCommands[0].Command)
+	assert.Equal(t, "cd /d missing && cd", sentCommands[1].Command)
+
+	assert.Equal(t, models.JobStatusFailed, jr.jobToRender.Status)
+	assert.Equal(t, []string{`win1 C:\Users\admin> `, `win1 C:\Users\admin> `}, lineReader.prompts)
+}
+
+func TestShellAbortCommand(t *testing.T) {
+	testCases := []struct {
+		name            string
+		runningJob      *models.Job
+		runningJobs     []*models.Job
+		expectedKillCmd string
+	}{
+		{
+			name:            "pid of the running job message",
+			runningJob:      &models.Job{Jid: "j1", Status: models.JobStatusRunning, Pid: 123},
+			expectedKillCmd: "pkill -TERM -P 123; kill -TERM 123",
+		},
+		{
+			name: "pid of the running jobs of the client",
+			runningJobs: []*models.Job{
+				{Jid: "j0", Command: "sleep 100", Pid: 100, StartedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+				{Jid: "j1", Command: "sleep 100", Pid: 124, StartedAt: time.Date(2021, 1, 1, 0, 1, 0, 0, time.UTC)},
+				{Jid: "j2", Command: "top", Pid: 125, StartedAt: time.Date(2021, 1, 1, 0, 2, 0, 0, time.UTC)},
+			},
+			expectedKillCmd: "pkill -TERM -P 124; kill -TERM 124",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			interrupts := make(chan os.Signal, 1)
+			lineReader := &ShellLineReaderMock{lines: []string{"sleep 100", "exit"}}
+			buf := &bytes.Buffer{}
+
+			commandConn := &blockingReadWriterMock{interrupts: interrupts, closed: make(chan struct{})}
+			if tc.runningJob != nil {
+				runningJob, err := json.Marshal(tc.runningJob)
+				require.NoError(t, err)
+				commandConn.runningJob = runningJob
+			}
+			killConnections := &shellConnectionsMock{
+				jobs: []*models.Job{{Jid: "j3", Status: models.JobStatusSuccessful}},
+			}
+			connectionsCount := 0
+			var mu sync.Mutex
+
+			jobsAPI := &JobsAPIMock{}
+			if tc.runningJobs != nil {
+				jobsAPI.On("ClientJobs", mock.Anything, "cl1", models.JobStatusRunning).
+					Return(&api.JobsResponse{Data: tc.runningJobs}, nil)
+			}
+
+			sc := &ShellController{
+				ExecutionHelper: &ExecutionHelper{
+					JobRenderer: &JobRendererMock{},
+					ClientSearch: &ClientSearchMock{
+						clientsToGive: []*models.Client{{ID: "cl1", Name: "server1"}},
+					},
+				},
+				Rport: jobsAPI,
+				ReadWriterProvider: func(ctx context.Context) (ReadWriter, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					connectionsCount++
+					if connectionsCount == 1 {
+						return commandConn, nil
+					}
+					return killConnections.provide(ctx)
+				},
+				LineReader: lineReader,
+				Interrupts: interrupts,
+				Writer:     buf,
+			}
+
+			err := sc.Start(context.Background(), "", config.FromValues(map[string]string{
+				ClientNameFlag: "server1",
+				Cwd:            "/root",
+			}))
+			require.NoError(t, err)
+
+			assert.Equal(t, "Connected to client server1 (cl1), type !help for help or exit to leave\n"+
+				"command aborted\n", buf.String())
+			assert.Equal(t, []string{"server1:/root$ ", "server1:/root$ "}, lineReader.prompts)
+			jobsAPI.AssertExpectations(t)
+
+			require.Len(t, commandConn.writtenItems, 1)
+			killCommands := killConnections.sentCommands(t)
+			require.Len(t, killCommands, 1)
+			assert.Equal(t, tc.expectedKillCmd, killCommands[0].Command)
+			assert.Equal(t, []string{"cl1"}, killCommands[0].ClientIDs)
+		})
+	}
+}
+
+func TestShellNoClient(t *testing.T) {
+	sc := &ShellController{}
+
+	err := sc.Start(context.Background(), "", config.FromValues(map[string]string{}))
+	assert.EqualError(t, err, "no client id nor name nor filter provided")
+}
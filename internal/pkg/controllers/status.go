@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	options "github.com/breathbath/go_utils/v2/pkg/config"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/auth"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+type StatusAPI interface {
+	Status(ctx context.Context) (st api.StatusResponse, err error)
+	Me(ctx context.Context) (user api.UserResponse, err error)
+}
+
+type StatusRenderer interface {
+	RenderStatus(st *models.StatusInfo) error
+}
+
+type StatusController struct {
+	Rport          StatusAPI
+	StatusRenderer StatusRenderer
+	CLIVersion     string
+}
+
+// Status renders the settings known locally even if the server cannot be reached or the user is not authorized,
+// the error of the server is rendered as well and returned afterwards
+func (sc *StatusController) Status(ctx context.Context, params *options.ParameterBag) error {
+	serverURL := params.ReadString(config.ServerURL, "")
+	if serverURL == "" {
+		serverURL = config.DefaultServerURL
+	}
+
+	st := &models.StatusInfo{
+		CLIVersion:     sc.CLIVersion,
+		ServerURL:      serverURL,
+		TokenExpiresAt: readTokenExpiry(params.ReadString(config.Token, "")),
+	}
+
+	err := sc.readServerStatus(ctx, st)
+	if err != nil {
+		st.Error = err.Error()
+	}
+
+	renderErr := sc.StatusRenderer.RenderStatus(st)
+	if renderErr != nil {
+		return renderErr
+	}
+
+	return err
+}
+
+func (sc *StatusController) readServerStatus(ctx context.Context, st *models.StatusInfo) error {
+	statusResp, err := sc.Rport.Status(ctx)
+	if err != nil {
+		return err
+	}
+	st.Server = &statusResp.Data
+
+	userResp, err := sc.Rport.Me(ctx)
+	if err != nil {
+		return err
+	}
+	st.User = &userResp.Data
+
+	return nil
+}
+
+func readTokenExpiry(token string) *time.Time {
+	if token == "" {
+		return nil
+	}
+
+	claims, err := auth.ParseUnverifiedClaims(token)
+	if err != nil {
+		logrus.Debugf("failed to read claims of the stored token: %v", err)
+		return nil
+	}
+
+	if claims.ExpiresAt == 0 {
+		return nil
+	}
+
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+
+	return &expiresAt
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/auth"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/config"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+type StatusAPIMock struct {
+	MeAPIMock
+}
+
+func (sam *StatusAPIMock) Status(ctx context.Context) (st api.StatusResponse, err error) {
+	args := sam.Called(ctx)
+
+	return args.Get(0).(api.StatusResponse), args.Error(1)
+}
+
+type StatusRendererMock struct {
+	statusGiven *models.StatusInfo
+}
+
+func (srm *StatusRendererMock) RenderStatus(st *models.StatusInfo) error {
+	srm.statusGiven = st
+	return nil
+}
+
+func TestStatus(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		Username: "admin",
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}).SignedString([]byte("some secret"))
+	require.NoError(t, err)
+
+	apiMock := &StatusAPIMock{}
+	apiMock.On("Status", ctx).Return(api.StatusResponse{Data: models.Status{Version: "0.4.0", ClientsConnected: 2}}, nil)
+	apiMock.On("Me", ctx).Return(api.UserResponse{Data: models.Me{Username: "admin"}}, nil)
+	renderMock := &StatusRendererMock{}
+
+	sc := &StatusController{
+		Rport:          apiMock,
+		StatusRenderer: renderMock,
+		CLIVersion:     "1.0.0",
+	}
+
+	err = sc.Status(ctx, config.FromValues(map[string]string{
+		config.ServerURL: "http://some.rport.com",
+		config.Token:     token,
+	}))
+	require.NoError(t, err)
+
+	require.NotNil(t, renderMock.statusGiven)
+	assert.Equal(t, "1.0.0", renderMock.statusGiven.CLIVersion)
+	assert.Equal(t, "http://some.rport.com", renderMock.statusGiven.ServerURL)
+	assert.Equal(t, &models.Me{Username: "admin"}, renderMock.statusGiven.User)
+	assert.Equal(t, &models.Status{Version: "0.4.0", ClientsConnected: 2}, renderMock.statusGiven.Server)
+	require.NotNil(t, renderMock.statusGiven.TokenExpiresAt)
+	assert.True(t, expiresAt.Equal(*renderMock.statusGiven.TokenExpiresAt))
+}
+
+func TestStatusWithoutToken(t *testing.T) {
+	ctx := context.Background()
+	apiMock := &StatusAPIMock{}
+	apiMock.On("Status", ctx).Return(api.StatusResponse{}, nil)
+	apiMock.On("Me", ctx).Return(api.UserResponse{}, nil)
+	renderMock := &StatusRendererMock{}
+
+	sc := &StatusController{
+		Rport:          apiMock,
+		StatusRenderer: renderMock,
+	}
+
+	err := sc.Status(ctx, config.FromValues(map[string]string{}))
+	require.NoError(t, err)
+
+	require.NotNil(t, renderMock.statusGiven)
+	assert.Equal(t, config.DefaultServerURL, renderMock.statusGiven.ServerURL)
+	assert.Nil(t, renderMock.statusGiven.TokenExpiresAt)
+}
+
+func TestStatusServerUnreachable(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}).SignedString([]byte("some secret"))
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	serverURL := srv.URL
+	srv.Close()
+
+	renderMock := &StatusRendererMock{}
+	sc := &StatusController{
+		Rport:          api.New(serverURL, nil),
+		StatusRenderer: renderMock,
+		CLIVersion:     "1.0.0",
+	}
+
+	err = sc.Status(ctx, config.FromValues(map[string]string{
+		config.ServerURL: serverURL,
+		config.Token:     token,
+	}))
+	require.Error(t, err)
+
+	require.NotNil(t, renderMock.statusGiven)
+	assert.Equal(t, "1.0.0", renderMock.statusGiven.CLIVersion)
+	assert.Equal(t, serverURL, renderMock.statusGiven.ServerURL)
+	require.NotNil(t, renderMock.statusGiven.TokenExpiresAt)
+	assert.True(t, expiresAt.Equal(*renderMock.statusGiven.TokenExpiresAt))
+	assert.Equal(t, err.Error(), renderMock.statusGiven.Error)
+	assert.Nil(t, renderMock.statusGiven.Server)
+	assert.Nil(t, renderMock.statusGiven.User)
+}
+
+func TestStatusUnauthorized(t *testing.T) {
+	ctx := context.Background()
+	apiMock := &StatusAPIMock{}
+	apiMock.On("Status", ctx).Return(api.StatusResponse{Data: models.Status{Version: "0.4.0"}}, nil)
+	apiMock.On("Me", ctx).Return(api.UserResponse{}, assert.AnError)
+	renderMock := &StatusRendererMock{}
+
+	sc := &StatusController{
+		Rport:          apiMock,
+		StatusRenderer: renderMock,
+	}
+
+	err := sc.Status(ctx, config.FromValues(map[string]string{}))
+	assert.Equal(t, assert.AnError, err)
+
+	require.NotNil(t, renderMock.statusGiven)
+	assert.Equal(t, &models.Status{Version: "0.4.0"}, renderMock.statusGiven.Server)
+	assert.Nil(t, renderMock.statusGiven.User)
+	assert.Equal(t, assert.AnError.Error(), renderMock.statusGiven.Error)
+}
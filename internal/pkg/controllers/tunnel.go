@@ -20,6 +20,7 @@ import (
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/api"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/client"
 )
 
 const (
@@ -87,7 +88,7 @@ func (tc *TunnelController) Tunnels(ctx context.Context, params *options.Paramet
 		}
 	} else {
 		var clResp *api.ClientsResponse
-		clResp, err = tc.Rport.Clients(ctx)
+		clResp, err = tc.Rport.ListClients(ctx, &api.ClientsListOptions{Fields: []string{"id", "name", "tunnels"}})
 		if err != nil {
 			return err
 		}
@@ -153,8 +154,8 @@ func (tc *TunnelController) getClientIDAndClientName(
 ) (clientID, clientName string, err error) {
 	clientID = params.ReadString(ClientID, "")
 	clientName = params.ReadString(ClientNameFlag, "")
-	if clientID == "" && clientName == "" {
-		err = errors.New("no client id nor name provided")
+	if clientID == "" && clientName == "" && !client.HasFilters(params) {
+		err = errors.New("no client id nor name nor filter provided")
 		return
 	}
 
@@ -162,12 +163,12 @@ func (tc *TunnelController) getClientIDAndClientName(
 		return
 	}
 
-	client, err := tc.ClientSearch.FindOne(ctx, clientName, params)
+	cl, err := tc.ClientSearch.FindOne(ctx, clientName, params)
 	if err != nil {
 		return
 	}
 
-	return client.ID, clientName, nil
+	return cl.ID, clientName, nil
 }
 
 func (tc *TunnelController) Create(ctx context.Context, params *options.ParameterBag) error {
@@ -494,7 +494,7 @@ func TestInvalidInputForTunnelCreate(t *testing.T) {
 		CheckPort:      "1",
 	})
 	err := tController.Create(context.Background(), params)
-	assert.EqualError(t, err, "no client id nor name provided")
+	assert.EqualError(t, err, "no client id nor name nor filter provided")
 }
 
 func TestTunnelCreateNotFoundClientName(t *testing.T) {
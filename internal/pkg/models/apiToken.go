@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/breathbath/go_utils/v2/pkg/testing"
+)
+
+const (
+	APITokenScopeRead      = "read"
+	APITokenScopeReadWrite = "read+write"
+)
+
+// APIToken is a long-lived token of the current user which is used as password for basic auth,
+// the token value is returned only once when the token is created
+type APIToken struct {
+	Prefix    string     `json:"prefix,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Scope     string     `json:"scope"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Token     string     `json:"token,omitempty"`
+}
+
+func (at *APIToken) Headers() []string {
+	return []string{
+		"PREFIX",
+		"NAME",
+		"SCOPE",
+		"CREATED AT",
+		"EXPIRES AT",
+	}
+}
+
+func (at *APIToken) Row() []string {
+	return []string{
+		at.Prefix,
+		at.Name,
+		at.Scope,
+		formatOptionalTime(at.CreatedAt),
+		formatOptionalTime(at.ExpiresAt),
+	}
+}
+
+func (at *APIToken) KeyValues() []testing.KeyValueStr {
+	return []testing.KeyValueStr{
+		{
+			Key:   "Prefix",
+			Value: at.Prefix,
+		},
+		{
+			Key:   "Name",
+			Value: at.Name,
+		},
+		{
+			Key:   "Scope",
+			Value: at.Scope,
+		},
+		{
+			Key:   "Created At",
+			Value: formatOptionalTime(at.CreatedAt),
+		},
+		{
+			Key:   "Expires At",
+			Value: formatOptionalTime(at.ExpiresAt),
+		},
+		{
+			Key:   "Token",
+			Value: at.Token,
+		},
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
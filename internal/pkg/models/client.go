@@ -9,8 +9,6 @@ import (
 	"github.com/dustin/go-humanize"
 
 	"github.com/breathbath/go_utils/v2/pkg/testing"
-
-	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
 )
 
 type UpdatesStatus struct {
@@ -110,31 +108,13 @@ type Client struct {
 }
 
 func (c *Client) Headers() []string {
-	return []string{
-		"ID",
-		"NAME",
-		"TUNNELS",
-		"REMOTE ADDRESS",
-		"HOSTNAME",
-		"OS_KERNEL",
-		"S",
-	}
+	fields, _ := ClientFields.Select(nil)
+	return fields.Headers()
 }
 
 func (c *Client) Row() []string {
-	connState := ""
-	if len(c.ConnState) > 0 {
-		connState = strings.ToUpper(c.ConnState[0:1])
-	}
-	return []string{
-		c.ID,
-		c.Name,
-		strconv.Itoa(len(c.Tunnels)),
-		utils.RemovePortFromURL(c.Address),
-		c.Hostname,
-		c.OsKernel,
-		connState,
-	}
+	fields, _ := ClientFields.Select(nil)
+	return fields.Row(c)
 }
 
 func (c *Client) KeyValues() []testing.KeyValueStr {
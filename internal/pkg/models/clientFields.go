@@ -0,0 +1,66 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/utils"
+)
+
+const listSep = ", "
+
+// ClientFields are table columns of clients
+var ClientFields = NewFieldRegistry(
+	[]string{"id", "name", "tunnels", "address", "hostname", "os_kernel", "connection_state"},
+	clientField("id", "ID", func(c *Client) string { return c.ID }),
+	clientField("name", "NAME", func(c *Client) string { return c.Name }),
+	clientField("tunnels", "TUNNELS", func(c *Client) string { return strconv.Itoa(len(c.Tunnels)) }),
+	clientField("address", "REMOTE ADDRESS", func(c *Client) string { return utils.RemovePortFromURL(c.Address) }),
+	clientField("hostname", "HOSTNAME", func(c *Client) string { return c.Hostname }),
+	clientField("os_kernel", "OS_KERNEL", func(c *Client) string { return c.OsKernel }),
+	clientField("connection_state", "S", func(c *Client) string {
+		if c.ConnState == "" {
+			return ""
+		}
+		return strings.ToUpper(c.ConnState[0:1])
+	}),
+	clientField("os", "OS", func(c *Client) string { return c.Os }),
+	clientField("os_arch", "OS_ARCH", func(c *Client) string { return c.OsArch }),
+	clientField("os_family", "OS_FAMILY", func(c *Client) string { return c.OsFamily }),
+	clientField("os_full_name", "OS_FULL_NAME", func(c *Client) string { return c.OSFullName }),
+	clientField("os_version", "OS_VERSION", func(c *Client) string { return c.OSVersion }),
+	clientField("os_virtualization_system", "OS_VIRTUALIZATION_SYSTEM", func(c *Client) string {
+		return c.OSVirtualizationSystem
+	}),
+	clientField("os_virtualization_role", "OS_VIRTUALIZATION_ROLE", func(c *Client) string {
+		return c.OSVirtualizationRole
+	}),
+	clientField("ipv4", "IPV4", func(c *Client) string { return strings.Join(c.Ipv4, listSep) }),
+	clientField("ipv6", "IPV6", func(c *Client) string { return strings.Join(c.Ipv6, listSep) }),
+	clientField("tags", "TAGS", func(c *Client) string { return strings.Join(c.Tags, listSep) }),
+	clientField("version", "VERSION", func(c *Client) string { return c.Version }),
+	clientField("disconnected_at", "DISCONNECTED_AT", func(c *Client) string { return c.DisconnectedAt }),
+	clientField("client_auth_id", "CLIENT_AUTH_ID", func(c *Client) string { return c.ClientAuthID }),
+	clientField("cpu_family", "CPU_FAMILY", func(c *Client) string { return c.CPUFamily }),
+	clientField("cpu_model", "CPU_MODEL", func(c *Client) string { return c.CPUModel }),
+	clientField("cpu_model_name", "CPU_MODEL_NAME", func(c *Client) string { return c.CPUModelName }),
+	clientField("cpu_vendor", "CPU_VENDOR", func(c *Client) string { return c.CPUVendor }),
+	clientField("num_cpus", "NUM_CPUS", func(c *Client) string { return strconv.Itoa(c.NumCPUs) }),
+	clientField("mem_total", "MEM_TOTAL", func(c *Client) string { return humanize.Bytes(c.MemoryTotal) }),
+	clientField("timezone", "TIMEZONE", func(c *Client) string { return c.Timezone }),
+	clientField("allowed_user_groups", "ALLOWED_USER_GROUPS", func(c *Client) string {
+		return strings.Join(c.AllowedUserGroups, listSep)
+	}),
+)
+
+func clientField(name, header string, value func(c *Client) string) *Field {
+	return &Field{
+		Name:   name,
+		Header: header,
+		Value: func(item interface{}) string {
+			return value(item.(*Client))
+		},
+	}
+}
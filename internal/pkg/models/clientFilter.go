@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ClientFilter matches clients which have at least one value of the field matching one of the filter values,
+// values can contain wildcards e.g. 'web*'
+type ClientFilter struct {
+	Field  string
+	Values []string
+}
+
+func clientFilterFields(c *Client) map[string][]string {
+	return map[string][]string{
+		"id":                       {c.ID},
+		"name":                     {c.Name},
+		"os":                       {c.Os},
+		"os_arch":                  {c.OsArch},
+		"os_family":                {c.OsFamily},
+		"os_kernel":                {c.OsKernel},
+		"os_full_name":             {c.OSFullName},
+		"os_version":               {c.OSVersion},
+		"os_virtualization_system": {c.OSVirtualizationSystem},
+		"os_virtualization_role":   {c.OSVirtualizationRole},
+		"hostname":                 {c.Hostname},
+		"connection_state":         {c.ConnState},
+		"disconnected_at":          {c.DisconnectedAt},
+		"client_auth_id":           {c.ClientAuthID},
+		"ipv4":                     c.Ipv4,
+		"ipv6":                     c.Ipv6,
+		"tags":                     c.Tags,
+		"version":                  {c.Version},
+		"address":                  {c.Address},
+		"cpu_family":               {c.CPUFamily},
+		"cpu_model":                {c.CPUModel},
+		"cpu_model_name":           {c.CPUModelName},
+		"cpu_vendor":               {c.CPUVendor},
+		"timezone":                 {c.Timezone},
+		"allowed_user_groups":      c.AllowedUserGroups,
+	}
+}
+
+// ClientFilterFields returns sorted names of client fields which can be used in filters
+func ClientFilterFields() []string {
+	fields := clientFilterFields(&Client{})
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseClientFilters converts expressions like 'os_family=linux' to filters, values of the same field are merged
+func ParseClientFilters(expressions []string) ([]*ClientFilter, error) {
+	supportedFields := clientFilterFields(&Client{})
+
+	filters := make([]*ClientFilter, 0, len(expressions))
+	filtersByField := make(map[string]*ClientFilter, len(expressions))
+	for _, expr := range expressions {
+		exprParts := strings.SplitN(expr, "=", 2)
+		if len(exprParts) != 2 || strings.TrimSpace(exprParts[0]) == "" || strings.TrimSpace(exprParts[1]) == "" {
+			return nil, fmt.Errorf("invalid filter '%s', expected format is field=value1,value2", expr)
+		}
+
+		field := strings.TrimSpace(exprParts[0])
+		if _, ok := supportedFields[field]; !ok {
+			return nil, fmt.Errorf(
+				"unknown client filter field '%s', supported fields are %s",
+				field,
+				strings.Join(ClientFilterFields(), ", "),
+			)
+		}
+
+		values := strings.Split(exprParts[1], ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+
+		if filter, ok := filtersByField[field]; ok {
+			filter.Values = append(filter.Values, values...)
+			continue
+		}
+
+		filter := &ClientFilter{Field: field, Values: values}
+		filtersByField[field] = filter
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// Match checks case insensitively if any value of the client field matches any of the filter values
+func (cf *ClientFilter) Match(c *Client) bool {
+	clientValues := clientFilterFields(c)[cf.Field]
+	for _, filterValue := range cf.Values {
+		valueRegex := wildcardToRegexp(filterValue)
+		for _, clientValue := range clientValues {
+			if valueRegex.MatchString(clientValue) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// FilterClients gives clients matching all filters
+func FilterClients(clients []*Client, filters []*ClientFilter) []*Client {
+	if len(filters) == 0 {
+		return clients
+	}
+
+	foundClients := make([]*Client, 0, len(clients))
+	for _, c := range clients {
+		isMatched := true
+		for _, filter := range filters {
+			if !filter.Match(c) {
+				isMatched = false
+				break
+			}
+		}
+		if isMatched {
+			foundClients = append(foundClients, c)
+		}
+	}
+
+	return foundClients
+}
+
+func wildcardToRegexp(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+
+	return regexp.MustCompile("(?i)^" + strings.Join(parts, ".*") + "$")
+}
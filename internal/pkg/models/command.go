@@ -2,11 +2,20 @@ package models
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/breathbath/go_utils/v2/pkg/testing"
 )
 
+const (
+	JobStatusRunning    = "running"
+	JobStatusSuccessful = "successful"
+	JobStatusFailed     = "failed"
+	// JobStatusUnknown is given to jobs which didn't finish within their timeout
+	JobStatusUnknown = "unknown"
+)
+
 type JobResult struct {
 	Stdout string `json:"stdout"`
 	Stderr string `json:"stderr"`
@@ -32,6 +41,38 @@ type Job struct {
 	Interpreter string    `json:"interpreter"`
 }
 
+type MultiJob struct {
+	Jid         string    `json:"jid"`
+	StartedAt   time.Time `json:"started_at"`
+	CreatedBy   string    `json:"created_by"`
+	ClientIDs   []string  `json:"client_ids"`
+	GroupIDs    []string  `json:"group_ids"`
+	Command     string    `json:"command"`
+	Interpreter string    `json:"interpreter"`
+	Cwd         string    `json:"cwd"`
+	IsSudo      bool      `json:"is_sudo"`
+	TimeoutSec  int       `json:"timeout_sec"`
+	Concurrent  bool      `json:"concurrent"`
+	AbortOnErr  bool      `json:"abort_on_err"`
+	IsScript    bool      `json:"is_script"`
+	// FinishedAt is set by servers which track the end of multi client jobs
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Jobs       []*Job     `json:"jobs,omitempty"`
+}
+
+type JobStarted struct {
+	Jid string `json:"jid"`
+}
+
+func (js *JobStarted) KeyValues() []testing.KeyValueStr {
+	return []testing.KeyValueStr{
+		{
+			Key:   "Multi Job ID",
+			Value: js.Jid,
+		},
+	}
+}
+
 type WsScriptCommand struct {
 	ClientIDs           []string `json:"client_ids"`
 	GroupIDs            []string `json:"group_ids,omitempty"`
@@ -101,3 +142,158 @@ func (j *Job) KeyValues() []testing.KeyValueStr {
 		},
 	}
 }
+
+// JobFields are table columns of jobs
+var JobFields = NewFieldRegistry(
+	[]string{"jid", "client", "status", "started_at", "finished_at", "command"},
+	jobField("jid", "JID", nil, func(j *Job) string { return j.Jid }),
+	jobField("client", "CLIENT", []string{"client_name", "client_id"}, func(j *Job) string {
+		if j.ClientName != "" {
+			return j.ClientName
+		}
+		return j.ClientID
+	}),
+	jobField("status", "STATUS", nil, func(j *Job) string { return j.Status }),
+	jobField("started_at", "STARTED AT", nil, func(j *Job) string { return formatJobTime(j.StartedAt) }),
+	jobField("finished_at", "FINISHED AT", nil, func(j *Job) string { return formatJobTime(j.FinishedAt) }),
+	jobField("command", "COMMAND", nil, func(j *Job) string { return j.Command }),
+	jobField("client_id", "CLIENT ID", nil, func(j *Job) string { return j.ClientID }),
+	jobField("client_name", "CLIENT NAME", nil, func(j *Job) string { return j.ClientName }),
+	jobField("multi_job_id", "MULTI JOB ID", nil, func(j *Job) string { return j.MultiJobID }),
+	jobField("created_by", "CREATED BY", nil, func(j *Job) string { return j.CreatedBy }),
+	jobField("interpreter", "INTERPRETER", nil, func(j *Job) string { return j.Interpreter }),
+	jobField("cwd", "CWD", nil, func(j *Job) string { return j.Cwd }),
+	jobField("is_sudo", "SUDO", nil, func(j *Job) string { return strconv.FormatBool(j.IsSudo) }),
+	jobField("is_script", "SCRIPT", nil, func(j *Job) string { return strconv.FormatBool(j.IsScript) }),
+	jobField("timeout_sec", "TIMEOUT SEC", nil, func(j *Job) string { return strconv.Itoa(j.TimeoutSec) }),
+	jobField("pid", "PID", nil, func(j *Job) string { return strconv.Itoa(j.Pid) }),
+	jobField("error", "ERROR", nil, func(j *Job) string { return j.Error }),
+	jobField("stdout", "STDOUT", []string{"result"}, func(j *Job) string { return j.Result.Stdout }),
+	jobField("stderr", "STDERR", []string{"result"}, func(j *Job) string { return j.Result.Stderr }),
+)
+
+func jobField(name, header string, apiFields []string, value func(j *Job) string) *Field {
+	return &Field{
+		Name:      name,
+		Header:    header,
+		APIFields: apiFields,
+		Value: func(item interface{}) string {
+			return value(item.(*Job))
+		},
+	}
+}
+
+func (j *Job) Headers() []string {
+	fields, _ := JobFields.Select(nil)
+	return fields.Headers()
+}
+
+func (j *Job) Row() []string {
+	fields, _ := JobFields.Select(nil)
+	return fields.Row(j)
+}
+
+// MultiJobFields are table columns of multi client jobs
+var MultiJobFields = NewFieldRegistry(
+	[]string{"jid", "started_at", "created_by", "clients", "command"},
+	multiJobField("jid", "JID", nil, func(mj *MultiJob) string { return mj.Jid }),
+	multiJobField("started_at", "STARTED AT", nil, func(mj *MultiJob) string { return formatJobTime(mj.StartedAt) }),
+	multiJobField("created_by", "CREATED BY", nil, func(mj *MultiJob) string { return mj.CreatedBy }),
+	multiJobField("clients", "CLIENTS", []string{"client_ids"}, func(mj *MultiJob) string {
+		return strconv.Itoa(len(mj.ClientIDs))
+	}),
+	multiJobField("command", "COMMAND", nil, func(mj *MultiJob) string { return mj.Command }),
+	multiJobField("client_ids", "CLIENT IDS", nil, func(mj *MultiJob) string { return strings.Join(mj.ClientIDs, ", ") }),
+	multiJobField("group_ids", "GROUP IDS", nil, func(mj *MultiJob) string { return strings.Join(mj.GroupIDs, ", ") }),
+	multiJobField("interpreter", "INTERPRETER", nil, func(mj *MultiJob) string { return mj.Interpreter }),
+	multiJobField("cwd", "CWD", nil, func(mj *MultiJob) string { return mj.Cwd }),
+	multiJobField("is_sudo", "SUDO", nil, func(mj *MultiJob) string { return strconv.FormatBool(mj.IsSudo) }),
+	multiJobField("is_script", "SCRIPT", nil, func(mj *MultiJob) string { return strconv.FormatBool(mj.IsScript) }),
+	multiJobField("timeout_sec", "TIMEOUT SEC", nil, func(mj *MultiJob) string { return strconv.Itoa(mj.TimeoutSec) }),
+	multiJobField("concurrent", "CONCURRENT", nil, func(mj *MultiJob) string { return strconv.FormatBool(mj.Concurrent) }),
+	multiJobField("abort_on_err", "ABORT ON ERROR", nil, func(mj *MultiJob) string {
+		return strconv.FormatBool(mj.AbortOnErr)
+	}),
+)
+
+func multiJobField(name, header string, apiFields []string, value func(mj *MultiJob) string) *Field {
+	return &Field{
+		Name:      name,
+		Header:    header,
+		APIFields: apiFields,
+		Value: func(item interface{}) string {
+			return value(item.(*MultiJob))
+		},
+	}
+}
+
+func (mj *MultiJob) Headers() []string {
+	fields, _ := MultiJobFields.Select(nil)
+	return fields.Headers()
+}
+
+func (mj *MultiJob) Row() []string {
+	fields, _ := MultiJobFields.Select(nil)
+	return fields.Row(mj)
+}
+
+func (mj *MultiJob) KeyValues() []testing.KeyValueStr {
+	return []testing.KeyValueStr{
+		{
+			Key:   "Multi Job ID",
+			Value: mj.Jid,
+		},
+		{
+			Key:   "Started at",
+			Value: formatJobTime(mj.StartedAt),
+		},
+		{
+			Key:   "Created By",
+			Value: mj.CreatedBy,
+		},
+		{
+			Key:   "Client IDs",
+			Value: strings.Join(mj.ClientIDs, ", "),
+		},
+		{
+			Key:   "Group IDs",
+			Value: strings.Join(mj.GroupIDs, ", "),
+		},
+		{
+			Key:   "Command",
+			Value: mj.Command,
+		},
+		{
+			Key:   "Interpreter",
+			Value: mj.Interpreter,
+		},
+		{
+			Key:   "Cwd",
+			Value: mj.Cwd,
+		},
+		{
+			Key:   "Is sudo",
+			Value: strconv.FormatBool(mj.IsSudo),
+		},
+		{
+			Key:   "Timeout sec",
+			Value: strconv.Itoa(mj.TimeoutSec),
+		},
+		{
+			Key:   "Concurrent",
+			Value: strconv.FormatBool(mj.Concurrent),
+		},
+		{
+			Key:   "Abort on error",
+			Value: strconv.FormatBool(mj.AbortOnErr),
+		},
+	}
+}
+
+func formatJobTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
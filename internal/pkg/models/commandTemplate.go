@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/breathbath/go_utils/v2/pkg/testing"
+)
+
+// commandTemplateParamRegex matches placeholders like {{service}} or {{ service }}
+var commandTemplateParamRegex = regexp.MustCompile(`{{\s*([\w-]+)\s*}}`)
+
+// CommandTemplate is a stored command with {{param}} placeholders which are filled before the execution
+type CommandTemplate struct {
+	ID        string     `json:"id,omitempty" yaml:"id,omitempty"`
+	Name      string     `json:"name" yaml:"name"`
+	Cmd       string     `json:"cmd" yaml:"cmd"`
+	Tags      []string   `json:"tags" yaml:"tags,omitempty"`
+	CreatedBy string     `json:"created_by,omitempty" yaml:"created_by,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+}
+
+// Params gives names of all placeholders in the order of their first occurrence
+func (ct *CommandTemplate) Params() []string {
+	params := []string{}
+	seen := map[string]bool{}
+	for _, match := range commandTemplateParamRegex.FindAllStringSubmatch(ct.Cmd, -1) {
+		if seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		params = append(params, match[1])
+	}
+
+	return params
+}
+
+// Render replaces the placeholders with the given values, values are inserted as they are without any quoting
+func (ct *CommandTemplate) Render(values map[string]string) (string, error) {
+	for _, param := range ct.Params() {
+		if _, ok := values[param]; !ok {
+			return "", fmt.Errorf("no value provided for param '%s'", param)
+		}
+	}
+
+	return commandTemplateParamRegex.ReplaceAllStringFunc(ct.Cmd, func(placeholder string) string {
+		return values[commandTemplateParamRegex.FindStringSubmatch(placeholder)[1]]
+	}), nil
+}
+
+func (ct *CommandTemplate) Headers() []string {
+	return []string{
+		"ID",
+		"NAME",
+		"COMMAND",
+		"TAGS",
+	}
+}
+
+func (ct *CommandTemplate) Row() []string {
+	return []string{
+		ct.ID,
+		ct.Name,
+		ct.Cmd,
+		strings.Join(ct.Tags, ", "),
+	}
+}
+
+func (ct *CommandTemplate) KeyValues() []testing.KeyValueStr {
+	return []testing.KeyValueStr{
+		{
+			Key:   "ID",
+			Value: ct.ID,
+		},
+		{
+			Key:   "Name",
+			Value: ct.Name,
+		},
+		{
+			Key:   "Command",
+			Value: ct.Cmd,
+		},
+		{
+			Key:   "Params",
+			Value: strings.Join(ct.Params(), ", "),
+		},
+		{
+			Key:   "Tags",
+			Value: strings.Join(ct.Tags, ", "),
+		},
+		{
+			Key:   "Created By",
+			Value: ct.CreatedBy,
+		},
+		{
+			Key:   "Created At",
+			Value: formatOptionalTime(ct.CreatedAt),
+		},
+		{
+			Key:   "Updated At",
+			Value: formatOptionalTime(ct.UpdatedAt),
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field is a table column of a model, the name is used to select the column and matches the api field name
+// if APIFields are not given
+type Field struct {
+	Name      string
+	Header    string
+	APIFields []string
+	Value     func(item interface{}) string
+}
+
+type Fields []*Field
+
+func (fs Fields) Headers() []string {
+	headers := make([]string, 0, len(fs))
+	for _, f := range fs {
+		headers = append(headers, f.Header)
+	}
+
+	return headers
+}
+
+func (fs Fields) Row(item interface{}) []string {
+	row := make([]string, 0, len(fs))
+	for _, f := range fs {
+		row = append(row, f.Value(item))
+	}
+
+	return row
+}
+
+// APIFields gives unique names of api fields which are needed to calculate values of the fields
+func (fs Fields) APIFields() []string {
+	apiFields := make([]string, 0, len(fs))
+	known := make(map[string]bool, len(fs))
+	for _, f := range fs {
+		fieldAPIFields := f.APIFields
+		if fieldAPIFields == nil {
+			fieldAPIFields = []string{f.Name}
+		}
+		for _, apiField := range fieldAPIFields {
+			if known[apiField] {
+				continue
+			}
+			known[apiField] = true
+			apiFields = append(apiFields, apiField)
+		}
+	}
+
+	return apiFields
+}
+
+// FieldRegistry keeps all table columns of a model and the names of the columns shown by default
+type FieldRegistry struct {
+	fields        Fields
+	defaultFields []string
+}
+
+func NewFieldRegistry(defaultFields []string, fields ...*Field) *FieldRegistry {
+	return &FieldRegistry{
+		fields:        fields,
+		defaultFields: defaultFields,
+	}
+}
+
+func (fr *FieldRegistry) Names() []string {
+	names := make([]string, 0, len(fr.fields))
+	for _, f := range fr.fields {
+		names = append(names, f.Name)
+	}
+
+	return names
+}
+
+// Select gives fields by names in the given order, the default fields are given if no names are provided
+func (fr *FieldRegistry) Select(names []string) (Fields, error) {
+	if len(names) == 0 {
+		names = fr.defaultFields
+	}
+
+	selectedFields := make(Fields, 0, len(names))
+	for _, name := range names {
+		f := fr.find(name)
+		if f == nil {
+			return nil, fmt.Errorf("unknown column '%s', supported columns are %s", name, strings.Join(fr.Names(), ", "))
+		}
+		selectedFields = append(selectedFields, f)
+	}
+
+	return selectedFields, nil
+}
+
+func (fr *FieldRegistry) find(name string) *Field {
+	for _, f := range fr.fields {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return nil
+}
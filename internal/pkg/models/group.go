@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/breathbath/go_utils/v2/pkg/testing"
+)
+
+// ClientGroupParams defines rules for the client group membership, values can contain wildcards e.g. 'web*'
+type ClientGroupParams struct {
+	ClientID     []string `json:"client_id,omitempty" yaml:"client_id,omitempty"`
+	Name         []string `json:"name,omitempty" yaml:"name,omitempty"`
+	OS           []string `json:"os,omitempty" yaml:"os,omitempty"`
+	OSArch       []string `json:"os_arch,omitempty" yaml:"os_arch,omitempty"`
+	OSFamily     []string `json:"os_family,omitempty" yaml:"os_family,omitempty"`
+	OSKernel     []string `json:"os_kernel,omitempty" yaml:"os_kernel,omitempty"`
+	Hostname     []string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	IPv4         []string `json:"ipv4,omitempty" yaml:"ipv4,omitempty"`
+	IPv6         []string `json:"ipv6,omitempty" yaml:"ipv6,omitempty"`
+	Tag          []string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	Version      []string `json:"version,omitempty" yaml:"version,omitempty"`
+	Address      []string `json:"address,omitempty" yaml:"address,omitempty"`
+	ClientAuthID []string `json:"client_auth_id,omitempty" yaml:"client_auth_id,omitempty"`
+}
+
+func (cgp *ClientGroupParams) fields() map[string]*[]string {
+	return map[string]*[]string{
+		"client_id":      &cgp.ClientID,
+		"name":           &cgp.Name,
+		"os":             &cgp.OS,
+		"os_arch":        &cgp.OSArch,
+		"os_family":      &cgp.OSFamily,
+		"os_kernel":      &cgp.OSKernel,
+		"hostname":       &cgp.Hostname,
+		"ipv4":           &cgp.IPv4,
+		"ipv6":           &cgp.IPv6,
+		"tag":            &cgp.Tag,
+		"version":        &cgp.Version,
+		"address":        &cgp.Address,
+		"client_auth_id": &cgp.ClientAuthID,
+	}
+}
+
+// Keys returns sorted names of all supported params
+func (cgp *ClientGroupParams) Keys() []string {
+	fields := cgp.fields()
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Add appends values to the param with the given name
+func (cgp *ClientGroupParams) Add(key string, values ...string) error {
+	field, ok := cgp.fields()[key]
+	if !ok {
+		return fmt.Errorf("unknown client group param '%s', supported params are %s", key, strings.Join(cgp.Keys(), ", "))
+	}
+
+	*field = append(*field, values...)
+
+	return nil
+}
+
+func (cgp *ClientGroupParams) KeyValues() []testing.KeyValueStr {
+	fields := cgp.fields()
+	kvs := make([]testing.KeyValueStr, 0, len(fields))
+	for _, key := range cgp.Keys() {
+		values := *fields[key]
+		if len(values) == 0 {
+			continue
+		}
+		kvs = append(kvs, testing.KeyValueStr{
+			Key:   key,
+			Value: strings.Join(values, ", "),
+		})
+	}
+
+	return kvs
+}
+
+type ClientGroup struct {
+	ID          string             `json:"id" yaml:"id"`
+	Description string             `json:"description" yaml:"description"`
+	Params      *ClientGroupParams `json:"params" yaml:"params"`
+	ClientIDs   []string           `json:"client_ids,omitempty" yaml:"client_ids,omitempty"`
+}
+
+func (cg *ClientGroup) Headers() []string {
+	return []string{
+		"ID",
+		"DESCRIPTION",
+		"CLIENTS",
+	}
+}
+
+func (cg *ClientGroup) Row() []string {
+	return []string{
+		cg.ID,
+		cg.Description,
+		strconv.Itoa(len(cg.ClientIDs)),
+	}
+}
+
+func (cg *ClientGroup) KeyValues() []testing.KeyValueStr {
+	return []testing.KeyValueStr{
+		{
+			Key:   "ID",
+			Value: cg.ID,
+		},
+		{
+			Key:   "Description",
+			Value: cg.Description,
+		},
+		{
+			Key:   "Clients count",
+			Value: strconv.Itoa(len(cg.ClientIDs)),
+		},
+	}
+}
+
+// ClientGroupDetails is a client group together with its resolved member clients
+type ClientGroupDetails struct {
+	ClientGroup `yaml:",inline"`
+	Clients     []*Client `json:"clients" yaml:"clients"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// JobOutputGroup is a distinct stdout and stderr of a command or script with the clients which produced it,
+// the diff is a unified diff against the output of the majority of clients
+type JobOutputGroup struct {
+	Clients    []string `json:"clients"`
+	Stdout     string   `json:"stdout"`
+	Stderr     string   `json:"stderr"`
+	IsMajority bool     `json:"is_majority"`
+	Diff       string   `json:"diff,omitempty"`
+}
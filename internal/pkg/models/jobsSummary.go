@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	JobResultSucceeded   = "succeeded"
+	JobResultFailed      = "failed"
+	JobResultTimedOut    = "timed out"
+	JobResultUnreachable = "unreachable"
+)
+
+// JobsSummary is the outcome of a command or script executed on multiple clients
+type JobsSummary struct {
+	Clients  []*JobSummaryClient `json:"clients"`
+	Duration time.Duration       `json:"duration"`
+}
+
+// JobSummaryClient is the outcome on one client, unreachable clients sent no result, so they have no duration
+type JobSummaryClient struct {
+	ClientID   string        `json:"client_id"`
+	ClientName string        `json:"client_name,omitempty"`
+	Result     string        `json:"result"`
+	Duration   time.Duration `json:"duration"`
+	Error      string        `json:"error,omitempty"`
+}
+
+func (js *JobsSummary) Count(result string) int {
+	count := 0
+	for _, cl := range js.Clients {
+		if cl.Result == result {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Title gives counts of each result e.g. 3 of 4 clients succeeded, 1 failed, 0 timed out, 0 unreachable in 2.5s
+func (js *JobsSummary) Title() string {
+	title := fmt.Sprintf(
+		"%d of %d clients %s, %d %s, %d %s, %d %s",
+		js.Count(JobResultSucceeded),
+		len(js.Clients),
+		JobResultSucceeded,
+		js.Count(JobResultFailed),
+		JobResultFailed,
+		js.Count(JobResultTimedOut),
+		JobResultTimedOut,
+		js.Count(JobResultUnreachable),
+		JobResultUnreachable,
+	)
+	if js.Duration > 0 {
+		title += " in " + formatJobDuration(js.Duration)
+	}
+
+	return title
+}
+
+func (jsc *JobSummaryClient) Headers() []string {
+	return []string{
+		"RESULT",
+		"CLIENT ID",
+		"CLIENT NAME",
+		"DURATION",
+		"ERROR",
+	}
+}
+
+func (jsc *JobSummaryClient) Row() []string {
+	duration := ""
+	if jsc.Result != JobResultUnreachable {
+		duration = formatJobDuration(jsc.Duration)
+	}
+
+	return []string{
+		jsc.Result,
+		jsc.ClientID,
+		jsc.ClientName,
+		duration,
+		jsc.Error,
+	}
+}
+
+func formatJobDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
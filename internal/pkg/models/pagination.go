@@ -0,0 +1,18 @@
+package models
+
+// Pagination describes which part of a list is shown, total is the count of all list items
+type Pagination struct {
+	Offset int
+	Limit  int
+	Total  int
+}
+
+// NextOffset gives the offset of the next page or 0 if the shown page is the last one
+func (p *Pagination) NextOffset(shownCount int) int {
+	nextOffset := p.Offset + shownCount
+	if shownCount == 0 || nextOffset >= p.Total {
+		return 0
+	}
+
+	return nextOffset
+}
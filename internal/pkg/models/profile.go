@@ -0,0 +1,28 @@
+package models
+
+type Profile struct {
+	Name      string `json:"name" yaml:"name"`
+	ServerURL string `json:"server" yaml:"server"`
+	IsCurrent bool   `json:"is_current" yaml:"is_current"`
+}
+
+func (p *Profile) Headers() []string {
+	return []string{
+		"CURRENT",
+		"NAME",
+		"SERVER",
+	}
+}
+
+func (p *Profile) Row() []string {
+	current := ""
+	if p.IsCurrent {
+		current = "*"
+	}
+
+	return []string{
+		current,
+		p.Name,
+		p.ServerURL,
+	}
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/breathbath/go_utils/v2/pkg/testing"
+)
+
+const (
+	ScheduleTypeCommand = "command"
+	ScheduleTypeScript  = "script"
+)
+
+// Schedule is a command or a script executed by the rport server on clients at times given by a cron expression,
+// details have the same fields as a multi client job, so a script is base64 encoded
+type Schedule struct {
+	ID        string          `json:"id,omitempty" yaml:"id,omitempty"`
+	Name      string          `json:"name" yaml:"name"`
+	Schedule  string          `json:"schedule" yaml:"schedule"`
+	Type      string          `json:"type" yaml:"type"`
+	Details   WsScriptCommand `json:"details" yaml:"details"`
+	CreatedBy string          `json:"created_by,omitempty" yaml:"created_by,omitempty"`
+	CreatedAt *time.Time      `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	// NextRuns is a preview calculated by the cli from the cron expression, it's not sent to the server
+	NextRuns []time.Time `json:"next_runs,omitempty" yaml:"next_runs,omitempty"`
+}
+
+// ScriptContent gives the decoded script of the schedule or the raw value if it's not base64 encoded
+func (s *Schedule) ScriptContent() string {
+	content, err := base64.StdEncoding.DecodeString(s.Details.Script)
+	if err != nil {
+		return s.Details.Script
+	}
+
+	return string(content)
+}
+
+func (s *Schedule) Headers() []string {
+	return []string{
+		"ID",
+		"NAME",
+		"SCHEDULE",
+		"TYPE",
+		"CLIENT IDS",
+		"GROUP IDS",
+		"NEXT RUN",
+	}
+}
+
+func (s *Schedule) Row() []string {
+	nextRun := ""
+	if len(s.NextRuns) > 0 {
+		nextRun = formatOptionalTime(&s.NextRuns[0])
+	}
+
+	return []string{
+		s.ID,
+		s.Name,
+		s.Schedule,
+		s.Type,
+		strings.Join(s.Details.ClientIDs, ", "),
+		strings.Join(s.Details.GroupIDs, ", "),
+		nextRun,
+	}
+}
+
+func (s *Schedule) KeyValues() []testing.KeyValueStr {
+	kvs := []testing.KeyValueStr{
+		{
+			Key:   "ID",
+			Value: s.ID,
+		},
+		{
+			Key:   "Name",
+			Value: s.Name,
+		},
+		{
+			Key:   "Schedule",
+			Value: s.Schedule,
+		},
+		{
+			Key:   "Type",
+			Value: s.Type,
+		},
+	}
+
+	if s.Type != ScheduleTypeScript {
+		kvs = append(kvs, testing.KeyValueStr{
+			Key:   "Command",
+			Value: s.Details.Command,
+		})
+	}
+
+	return append(kvs, []testing.KeyValueStr{
+		{
+			Key:   "Client IDs",
+			Value: strings.Join(s.Details.ClientIDs, ", "),
+		},
+		{
+			Key:   "Group IDs",
+			Value: strings.Join(s.Details.GroupIDs, ", "),
+		},
+		{
+			Key:   "Interpreter",
+			Value: s.Details.Interpreter,
+		},
+		{
+			Key:   "Cwd",
+			Value: s.Details.Cwd,
+		},
+		{
+			Key:   "Is sudo",
+			Value: strconv.FormatBool(s.Details.IsSudo),
+		},
+		{
+			Key:   "Timeout sec",
+			Value: strconv.Itoa(s.Details.TimeoutSec),
+		},
+		{
+			Key:   "Concurrent",
+			Value: strconv.FormatBool(s.Details.ExecuteConcurrently),
+		},
+		{
+			Key:   "Abort on error",
+			Value: strconv.FormatBool(s.Details.AbortOnError),
+		},
+		{
+			Key:   "Created By",
+			Value: s.CreatedBy,
+		},
+		{
+			Key:   "Created At",
+			Value: formatOptionalTime(s.CreatedAt),
+		},
+	}...)
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/breathbath/go_utils/v2/pkg/testing"
+)
+
+// Script is an entry of the script library on the rport server, the content is kept as plain text
+type Script struct {
+	ID          string     `json:"id,omitempty" yaml:"id,omitempty"`
+	Name        string     `json:"name" yaml:"name"`
+	Interpreter string     `json:"interpreter" yaml:"interpreter"`
+	IsSudo      bool       `json:"is_sudo" yaml:"is_sudo"`
+	Cwd         string     `json:"cwd" yaml:"cwd"`
+	Tags        []string   `json:"tags" yaml:"tags"`
+	Content     string     `json:"script" yaml:"script"`
+	CreatedBy   string     `json:"created_by,omitempty" yaml:"created_by,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+}
+
+func (s *Script) Headers() []string {
+	return []string{
+		"ID",
+		"NAME",
+		"INTERPRETER",
+		"SUDO",
+		"CWD",
+		"TAGS",
+		"UPDATED AT",
+	}
+}
+
+func (s *Script) Row() []string {
+	return []string{
+		s.ID,
+		s.Name,
+		s.Interpreter,
+		strconv.FormatBool(s.IsSudo),
+		s.Cwd,
+		strings.Join(s.Tags, ", "),
+		formatOptionalTime(s.UpdatedAt),
+	}
+}
+
+func (s *Script) KeyValues() []testing.KeyValueStr {
+	return []testing.KeyValueStr{
+		{
+			Key:   "ID",
+			Value: s.ID,
+		},
+		{
+			Key:   "Name",
+			Value: s.Name,
+		},
+		{
+			Key:   "Interpreter",
+			Value: s.Interpreter,
+		},
+		{
+			Key:   "Is sudo",
+			Value: strconv.FormatBool(s.IsSudo),
+		},
+		{
+			Key:   "Cwd",
+			Value: s.Cwd,
+		},
+		{
+			Key:   "Tags",
+			Value: strings.Join(s.Tags, ", "),
+		},
+		{
+			Key:   "Created By",
+			Value: s.CreatedBy,
+		},
+		{
+			Key:   "Created At",
+			Value: formatOptionalTime(s.CreatedAt),
+		},
+		{
+			Key:   "Updated At",
+			Value: formatOptionalTime(s.UpdatedAt),
+		},
+	}
+}
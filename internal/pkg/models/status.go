@@ -1,5 +1,13 @@
 package models
 
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/breathbath/go_utils/v2/pkg/testing"
+)
+
 type Status struct {
 	ClientsConnected    int    `json:"clients_connected"`
 	ClientsDisconnected int    `json:"clients_disconnected"`
@@ -7,3 +15,85 @@ type Status struct {
 	Fingerprint         string `json:"fingerprint"`
 	ConnectURL          string `json:"connect_url"`
 }
+
+// StatusInfo combines the server status with the connection settings of the cli
+type StatusInfo struct {
+	CLIVersion     string     `json:"cli_version"`
+	ServerURL      string     `json:"server_url"`
+	User           *Me        `json:"user"`
+	TokenExpiresAt *time.Time `json:"token_expires_at,omitempty"`
+	Server         *Status    `json:"server"`
+	Error          string     `json:"error,omitempty"`
+}
+
+func (si *StatusInfo) KeyValues() []testing.KeyValueStr {
+	user := &Me{}
+	if si.User != nil {
+		user = si.User
+	}
+	server := &Status{}
+	if si.Server != nil {
+		server = si.Server
+	}
+
+	keyValues := []testing.KeyValueStr{
+		{
+			Key:   "CLI Version",
+			Value: si.CLIVersion,
+		},
+		{
+			Key:   "Server URL",
+			Value: si.ServerURL,
+		},
+		{
+			Key:   "Server Version",
+			Value: server.Version,
+		},
+		{
+			Key:   "Fingerprint",
+			Value: server.Fingerprint,
+		},
+		{
+			Key:   "Connect URL",
+			Value: server.ConnectURL,
+		},
+		{
+			Key:   "Clients Connected",
+			Value: strconv.Itoa(server.ClientsConnected),
+		},
+		{
+			Key:   "Clients Disconnected",
+			Value: strconv.Itoa(server.ClientsDisconnected),
+		},
+		{
+			Key:   "User",
+			Value: user.Username,
+		},
+		{
+			Key:   "Groups",
+			Value: strings.Join(user.Groups, ", "),
+		},
+		{
+			Key:   "Token Expires At",
+			Value: si.tokenExpiry(),
+		},
+	}
+	if si.Error != "" {
+		keyValues = append(keyValues, testing.KeyValueStr{Key: "Error", Value: si.Error})
+	}
+
+	return keyValues
+}
+
+func (si *StatusInfo) tokenExpiry() string {
+	if si.TokenExpiresAt == nil {
+		return ""
+	}
+
+	expiry := si.TokenExpiresAt.Format(time.RFC3339)
+	if si.TokenExpiresAt.Before(time.Now()) {
+		expiry += " (expired)"
+	}
+
+	return expiry
+}
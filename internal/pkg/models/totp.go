@@ -4,6 +4,8 @@ type TotPSecretOutput struct {
 	Secret  string `json:"secret"`
 	Comment string `json:"comment"`
 	File    string `json:"file"`
+	// QR is the qr code drawn with text to show it in the terminal
+	QR string `json:"-" yaml:"-"`
 }
 
 type TotPSecretResp struct {
@@ -34,36 +34,52 @@ type Tunnel struct {
 	IdleTimeoutMins int    `json:"idle_timeout_minutes" yaml:"idle_timeout_minutes"`
 }
 
-func (t *Tunnel) Headers() []string {
-	return []string{
-		"ID",
-		"CLIENT_ID",
-		"CLIENT_NAME",
-		"LOCAL_HOST",
-		"LOCAL_PORT",
-		"REMOTE_HOST",
-		"REMOTE_PORT",
-		"LOCAL_PORT_RAND",
-		"SCHEME",
-		"ACL",
-		"TIMEOUT",
+// TunnelFields are table columns of tunnels
+var TunnelFields = NewFieldRegistry(
+	[]string{
+		"id",
+		"client_id",
+		"client_name",
+		"lhost",
+		"lport",
+		"rhost",
+		"rport",
+		"lport_random",
+		"scheme",
+		"acl",
+		"idle_timeout_minutes",
+	},
+	tunnelField("id", "ID", func(t *Tunnel) string { return t.ID }),
+	tunnelField("client_id", "CLIENT_ID", func(t *Tunnel) string { return t.ClientID }),
+	tunnelField("client_name", "CLIENT_NAME", func(t *Tunnel) string { return t.ClientName }),
+	tunnelField("lhost", "LOCAL_HOST", func(t *Tunnel) string { return t.Lhost }),
+	tunnelField("lport", "LOCAL_PORT", func(t *Tunnel) string { return t.Lport }),
+	tunnelField("rhost", "REMOTE_HOST", func(t *Tunnel) string { return t.Rhost }),
+	tunnelField("rport", "REMOTE_PORT", func(t *Tunnel) string { return t.Rport }),
+	tunnelField("lport_random", "LOCAL_PORT_RAND", func(t *Tunnel) string { return fmt.Sprint(t.LportRandom) }),
+	tunnelField("scheme", "SCHEME", func(t *Tunnel) string { return t.Scheme }),
+	tunnelField("acl", "ACL", func(t *Tunnel) string { return t.ACL }),
+	tunnelField("idle_timeout_minutes", "TIMEOUT", func(t *Tunnel) string { return strconv.Itoa(t.IdleTimeoutMins) }),
+)
+
+func tunnelField(name, header string, value func(t *Tunnel) string) *Field {
+	return &Field{
+		Name:   name,
+		Header: header,
+		Value: func(item interface{}) string {
+			return value(item.(*Tunnel))
+		},
 	}
 }
 
+func (t *Tunnel) Headers() []string {
+	fields, _ := TunnelFields.Select(nil)
+	return fields.Headers()
+}
+
 func (t *Tunnel) Row() []string {
-	return []string{
-		t.ID,
-		t.ClientID,
-		t.ClientName,
-		t.Lhost,
-		t.Lport,
-		t.Rhost,
-		t.Rport,
-		fmt.Sprint(t.LportRandom),
-		t.Scheme,
-		t.ACL,
-		strconv.Itoa(t.IdleTimeoutMins),
-	}
+	fields, _ := TunnelFields.Select(nil)
+	return fields.Row(t)
 }
 
 func (t *Tunnel) KeyValues() []testing.KeyValueStr {
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"io"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+type APITokenRenderer struct {
+	ColCountCalculator CalcTerminalColumnsCount
+	Writer             io.Writer
+	Format             string
+}
+
+func (atr *APITokenRenderer) RenderAPITokens(tokens []*models.APIToken) error {
+	return RenderByFormat(
+		atr.Format,
+		atr.Writer,
+		tokens,
+		func() error {
+			err := RenderHeader(atr.Writer, "API tokens")
+			if err != nil {
+				return err
+			}
+
+			rowProviders := make([]RowData, 0, len(tokens))
+			for _, t := range tokens {
+				rowProviders = append(rowProviders, t)
+			}
+
+			return RenderTable(atr.Writer, &models.APIToken{}, rowProviders, atr.ColCountCalculator)
+		},
+	)
+}
+
+func (atr *APITokenRenderer) RenderAPIToken(token *models.APIToken) error {
+	return RenderByFormat(
+		atr.Format,
+		atr.Writer,
+		token,
+		func() error {
+			err := RenderHeader(atr.Writer, "API token")
+			if err != nil {
+				return err
+			}
+
+			RenderKeyValues(atr.Writer, token)
+
+			return RenderHeader(atr.Writer, "\nPlease copy the token now, it cannot be shown again")
+		},
+	)
+}
+
+func (atr *APITokenRenderer) RenderStatus(s KvProvider) error {
+	return RenderByFormat(
+		atr.Format,
+		atr.Writer,
+		s,
+		func() error {
+			RenderKeyValues(atr.Writer, s)
+			return nil
+		},
+	)
+}
@@ -0,0 +1,88 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestRenderAPITokens(t *testing.T) {
+	createdAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	tokens := []*models.APIToken{
+		{
+			Prefix:    "ab12cd34",
+			Name:      "ci",
+			Scope:     models.APITokenScopeRead,
+			CreatedAt: &createdAt,
+		},
+	}
+
+	testCases := []struct {
+		Format         string
+		ExpectedOutput string
+	}{
+		{
+			Format: FormatHuman,
+			ExpectedOutput: `API tokens
+PREFIX   NAME SCOPE CREATED AT           EXPIRES AT 
+ab12cd34 ci   read  2022-01-02T03:04:05Z            
+`,
+		},
+		{
+			Format: FormatJSON,
+			ExpectedOutput: `[{"prefix":"ab12cd34","name":"ci","scope":"read","created_at":"2022-01-02T03:04:05Z"}]
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			atr := &APITokenRenderer{
+				ColCountCalculator: func() int {
+					return 150
+				},
+				Writer: buf,
+				Format: tc.Format,
+			}
+
+			err := atr.RenderAPITokens(tokens)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.ExpectedOutput, buf.String())
+		})
+	}
+}
+
+func TestRenderAPIToken(t *testing.T) {
+	buf := &bytes.Buffer{}
+	atr := &APITokenRenderer{
+		Writer: buf,
+		Format: FormatHuman,
+	}
+
+	err := atr.RenderAPIToken(&models.APIToken{
+		Prefix: "ab12cd34",
+		Scope:  models.APITokenScopeReadWrite,
+		Token:  "ab12cd34secret",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `API token
+KEY         VALUE          
+Prefix:     ab12cd34       
+Name:                      
+Scope:      read+write     
+Created At:                
+Expires At:                
+Token:      ab12cd34secret 
+
+Please copy the token now, it cannot be shown again
+`, buf.String())
+}
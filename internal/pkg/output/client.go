@@ -11,31 +11,83 @@ type ClientRenderer struct {
 	ColCountCalculator CalcTerminalColumnsCount
 	Writer             io.Writer
 	Format             string
+	Columns            []string
 }
 
-func (cr *ClientRenderer) RenderClients(clients []*models.Client) error {
+// ClientListFields gives the api fields which are needed to render a clients list, nil means all fields
+func (cr *ClientRenderer) ClientListFields() ([]string, error) {
+	if !IsTableFormat(cr.Format) {
+		return nil, nil
+	}
+
+	fields, err := models.ClientFields.Select(cr.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields.APIFields(), nil
+}
+
+// RenderClients renders a list of clients, if pagination is given, a pagination footer is added in human format
+func (cr *ClientRenderer) RenderClients(clients []*models.Client, pagination *models.Pagination) error {
+	if IsDelimitedFormat(cr.Format) {
+		return RenderFieldsDelimited(cr.Writer, cr.Format, models.ClientFields, cr.Columns, clientsToItems(clients))
+	}
+
 	return RenderByFormat(
 		cr.Format,
 		cr.Writer,
 		clients,
 		func() error {
-			return cr.renderClientsToHumanFormat(clients)
+			err := cr.renderClientsToHumanFormat(clients)
+			if err != nil || pagination == nil {
+				return err
+			}
+
+			return cr.renderPaginationFooter(len(clients), pagination)
 		},
 	)
 }
 
+func (cr *ClientRenderer) renderPaginationFooter(shownCount int, pagination *models.Pagination) error {
+	if shownCount == 0 {
+		_, err := fmt.Fprintf(cr.Writer, "\nNo clients found at offset %d, total clients count: %d\n", pagination.Offset, pagination.Total)
+		return err
+	}
+
+	footer := fmt.Sprintf(
+		"\nShowing clients %d-%d of %d",
+		pagination.Offset+1,
+		pagination.Offset+shownCount,
+		pagination.Total,
+	)
+
+	nextOffset := pagination.NextOffset(shownCount)
+	if nextOffset > 0 {
+		footer += fmt.Sprintf(", use --offset %d to see the next page", nextOffset)
+	}
+
+	_, err := fmt.Fprintln(cr.Writer, footer)
+
+	return err
+}
+
 func (cr *ClientRenderer) renderClientsToHumanFormat(clients []*models.Client) error {
 	err := RenderHeader(cr.Writer, "GetClients")
 	if err != nil {
 		return err
 	}
 
-	rowProviders := make([]RowData, 0, len(clients))
+	return RenderFieldsTable(cr.Writer, models.ClientFields, cr.Columns, clientsToItems(clients), cr.ColCountCalculator)
+}
+
+func clientsToItems(clients []*models.Client) []interface{} {
+	items := make([]interface{}, 0, len(clients))
 	for _, cl := range clients {
-		rowProviders = append(rowProviders, cl)
+		items = append(items, cl)
 	}
 
-	return RenderTable(cr.Writer, &models.Client{}, rowProviders, cr.ColCountCalculator)
+	return items
 }
 
 func (cr *ClientRenderer) RenderClient(client *models.Client, renderDetails bool) error {
@@ -183,7 +183,7 @@ ID  NAME          TUNNELS REMOTE ADDRESS HOSTNAME OS KERNEL S
 				Format: tc.Format,
 			}
 
-			err := cr.RenderClients(clients)
+			err := cr.RenderClients(clients, nil)
 			assert.NoError(t, err)
 			if err != nil {
 				return
@@ -198,6 +198,85 @@ ID  NAME          TUNNELS REMOTE ADDRESS HOSTNAME OS KERNEL S
 	}
 }
 
+func TestRenderClientsWithPagination(t *testing.T) {
+	clients := []*models.Client{
+		{
+			ID:        "123",
+			Name:      "SomeName",
+			ConnState: "connected",
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		format         string
+		clients        []*models.Client
+		pagination     *models.Pagination
+		expectedOutput string
+	}{
+		{
+			name:       "has next page",
+			format:     FormatHuman,
+			clients:    clients,
+			pagination: &models.Pagination{Offset: 10, Limit: 1, Total: 12},
+			expectedOutput: `GetClients
+ID  NAME     TUNNELS REMOTE ADDRESS HOSTNAME OS KERNEL S 
+123 SomeName 0                                         C 
+
+Showing clients 11-11 of 12, use --offset 11 to see the next page
+`,
+		},
+		{
+			name:       "last page",
+			format:     FormatHuman,
+			clients:    clients,
+			pagination: &models.Pagination{Offset: 11, Limit: 1, Total: 12},
+			expectedOutput: `GetClients
+ID  NAME     TUNNELS REMOTE ADDRESS HOSTNAME OS KERNEL S 
+123 SomeName 0                                         C 
+
+Showing clients 12-12 of 12
+`,
+		},
+		{
+			name:       "empty page",
+			format:     FormatHuman,
+			clients:    []*models.Client{},
+			pagination: &models.Pagination{Offset: 20, Limit: 1, Total: 12},
+			expectedOutput: `GetClients
+ID NAME TUNNELS REMOTE ADDRESS HOSTNAME OS KERNEL S 
+
+No clients found at offset 20, total clients count: 12
+`,
+		},
+		{
+			name:           "no footer in json",
+			format:         FormatJSON,
+			clients:        []*models.Client{},
+			pagination:     &models.Pagination{Offset: 20, Limit: 1, Total: 12},
+			expectedOutput: "[]\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			cr := &ClientRenderer{
+				ColCountCalculator: func() int {
+					return 150
+				},
+				Writer: buf,
+				Format: tc.format,
+			}
+
+			err := cr.RenderClients(tc.clients, tc.pagination)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, buf.String())
+		})
+	}
+}
+
 func TestRenderClient(t *testing.T) {
 	testCases := []struct {
 		Format         string
@@ -0,0 +1,77 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+type CommandTemplateRenderer struct {
+	ColCountCalculator CalcTerminalColumnsCount
+	Writer             io.Writer
+	Format             string
+}
+
+func (ctr *CommandTemplateRenderer) RenderCommandTemplates(templates []*models.CommandTemplate) error {
+	return RenderByFormat(
+		ctr.Format,
+		ctr.Writer,
+		templates,
+		func() error {
+			return ctr.renderCommandTemplatesInHumanFormat(templates)
+		},
+	)
+}
+
+func (ctr *CommandTemplateRenderer) renderCommandTemplatesInHumanFormat(templates []*models.CommandTemplate) error {
+	err := RenderHeader(ctr.Writer, "Command templates")
+	if err != nil {
+		return err
+	}
+
+	rowProviders := make([]RowData, 0, len(templates))
+	for _, t := range templates {
+		rowProviders = append(rowProviders, t)
+	}
+
+	return RenderTable(ctr.Writer, &models.CommandTemplate{}, rowProviders, ctr.ColCountCalculator)
+}
+
+func (ctr *CommandTemplateRenderer) RenderCommandTemplate(template *models.CommandTemplate) error {
+	return RenderByFormat(
+		ctr.Format,
+		ctr.Writer,
+		template,
+		func() error {
+			return ctr.renderCommandTemplateInHumanFormat(template)
+		},
+	)
+}
+
+func (ctr *CommandTemplateRenderer) renderCommandTemplateInHumanFormat(template *models.CommandTemplate) error {
+	if template == nil {
+		return nil
+	}
+
+	err := RenderHeader(ctr.Writer, fmt.Sprintf("Command template [%s]\n", template.ID))
+	if err != nil {
+		return err
+	}
+
+	RenderKeyValues(ctr.Writer, template)
+
+	return nil
+}
+
+func (ctr *CommandTemplateRenderer) RenderStatus(os KvProvider) error {
+	return RenderByFormat(
+		ctr.Format,
+		ctr.Writer,
+		os,
+		func() error {
+			RenderKeyValues(ctr.Writer, os)
+			return nil
+		},
+	)
+}
@@ -0,0 +1,88 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCommandTemplates(t *testing.T) {
+	templates := []*models.CommandTemplate{
+		{
+			ID:   "123",
+			Name: "restart",
+			Cmd:  "systemctl restart {{service}}",
+			Tags: []string{"linux", "services"},
+		},
+	}
+
+	testCases := []struct {
+		Format         string
+		ExpectedOutput string
+	}{
+		{
+			Format: FormatHuman,
+			ExpectedOutput: `Command templates
+ID  NAME    COMMAND                       TAGS            
+123 restart systemctl restart {{service}} linux, services 
+`,
+		},
+		{
+			Format: FormatJSON,
+			ExpectedOutput: `[{"id":"123","name":"restart","cmd":"systemctl restart {{service}}","tags":["linux","services"]}]
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			ctr := &CommandTemplateRenderer{
+				ColCountCalculator: func() int {
+					return 150
+				},
+				Writer: buf,
+				Format: tc.Format,
+			}
+
+			err := ctr.RenderCommandTemplates(templates)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.ExpectedOutput, buf.String())
+		})
+	}
+}
+
+func TestRenderCommandTemplate(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctr := &CommandTemplateRenderer{
+		Writer: buf,
+		Format: FormatHuman,
+	}
+
+	err := ctr.RenderCommandTemplate(&models.CommandTemplate{
+		ID:        "123",
+		Name:      "restart",
+		Cmd:       "systemctl {{action}} {{service}}",
+		Tags:      []string{"linux"},
+		CreatedBy: "admin",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `Command template [123]
+
+KEY         VALUE                            
+ID:         123                              
+Name:       restart                          
+Command:    systemctl {{action}} {{service}} 
+Params:     action, service                  
+Tags:       linux                            
+Created By: admin                            
+Created At:                                  
+Updated At:                                  
+`, buf.String())
+}
@@ -0,0 +1,86 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+// IsDelimitedFormat tells if data is rendered as delimiter separated values, e.g. for spreadsheets
+func IsDelimitedFormat(format string) bool {
+	return format == FormatCSV || format == FormatTSV
+}
+
+// newDelimitedWriter creates a writer which quotes values containing delimiters, quotes or line breaks
+func newDelimitedWriter(w io.Writer, format string) *csv.Writer {
+	dw := csv.NewWriter(w)
+	if format == FormatTSV {
+		dw.Comma = '\t'
+	}
+
+	return dw
+}
+
+// RenderDelimited renders a header line from the columns data followed by one line per row
+func RenderDelimited(w io.Writer, format string, col ColumnsData, rowProviders []RowData) error {
+	dw := newDelimitedWriter(w, format)
+
+	err := dw.Write(col.Headers())
+	if err != nil {
+		return err
+	}
+
+	for _, rowProvider := range rowProviders {
+		err = dw.Write(rowProvider.Row())
+		if err != nil {
+			return err
+		}
+	}
+
+	dw.Flush()
+
+	return dw.Error()
+}
+
+// RenderFieldsDelimited renders the given columns of items or the default ones if no columns are given
+func RenderFieldsDelimited(
+	w io.Writer,
+	format string,
+	registry *models.FieldRegistry,
+	columns []string,
+	items []interface{},
+) error {
+	fields, err := registry.Select(columns)
+	if err != nil {
+		return err
+	}
+
+	rowProviders := make([]RowData, 0, len(items))
+	for _, item := range items {
+		rowProviders = append(rowProviders, &fieldsRow{fields: fields, item: item})
+	}
+
+	return RenderDelimited(w, format, fields, rowProviders)
+}
+
+// RenderKeyValuesDelimited renders key values as two columns
+func RenderKeyValuesDelimited(w io.Writer, format string, kvP KvProvider) error {
+	dw := newDelimitedWriter(w, format)
+
+	err := dw.Write([]string{"KEY", "VALUE"})
+	if err != nil {
+		return err
+	}
+
+	for _, kv := range kvP.KeyValues() {
+		err = dw.Write([]string{kv.Key, kv.Value})
+		if err != nil {
+			return err
+		}
+	}
+
+	dw.Flush()
+
+	return dw.Error()
+}
@@ -0,0 +1,69 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestRenderDelimited(t *testing.T) {
+	testCases := []struct {
+		format         string
+		expectedOutput string
+	}{
+		{
+			format:         FormatCSV,
+			expectedOutput: "col1,col2,col3\nval1,val2,val3\n",
+		},
+		{
+			format:         FormatTSV,
+			expectedOutput: "col1\tcol2\tcol3\nval1\tval2\tval3\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := RenderDelimited(buf, tc.format, ColumnsDataStub{}, []RowData{RowDataStub{}})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, buf.String())
+		})
+	}
+}
+
+func TestRenderFieldsDelimited(t *testing.T) {
+	clients := []interface{}{
+		&models.Client{ID: "123", Name: "Some, Name", Tags: []string{"prod", "web"}},
+	}
+
+	buf := &bytes.Buffer{}
+	err := RenderFieldsDelimited(buf, FormatCSV, models.ClientFields, []string{"id", "name", "tags"}, clients)
+	require.NoError(t, err)
+	assert.Equal(t, "ID,NAME,TAGS\n123,\"Some, Name\",\"prod, web\"\n", buf.String())
+
+	err = RenderFieldsDelimited(buf, FormatCSV, models.ClientFields, []string{"color"}, clients)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown column 'color'")
+}
+
+func TestRenderKeyValuesDelimited(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := RenderKeyValuesDelimited(buf, FormatTSV, KVProviderStub{})
+	require.NoError(t, err)
+	assert.Equal(t, "KEY\tVALUE\none\t1\ntwo\t2\n", buf.String())
+}
+
+func TestRenderByFormatDelimited(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := RenderByFormat(FormatCSV, buf, KVProviderStub{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "KEY,VALUE\none,1\ntwo,2\n", buf.String())
+
+	err = RenderByFormat(FormatCSV, buf, []string{"one"}, nil)
+	assert.EqualError(t, err, "csv format is not supported for this output")
+}
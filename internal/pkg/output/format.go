@@ -13,13 +13,24 @@ const (
 	FormatJSON       = "json"
 	FormatJSONPretty = "json-pretty"
 	FormatYAML       = "yaml"
+	FormatCSV        = "csv"
+	FormatTSV        = "tsv"
 )
 
+// IsTableFormat tells if only table columns are rendered in the given format
+func IsTableFormat(format string) bool {
+	return format == "" || format == FormatHuman || IsDelimitedFormat(format)
+}
+
 func RenderByFormat(format string, w io.Writer, source interface{}, renderCallback func() error) error {
 	if format == "" {
 		format = FormatHuman
 	}
 
+	if IsTemplateFormat(format) {
+		return RenderTemplateFormat(w, format, source)
+	}
+
 	switch format {
 	case FormatHuman:
 		return renderCallback()
@@ -30,6 +41,12 @@ func RenderByFormat(format string, w io.Writer, source interface{}, renderCallba
 	case FormatYAML:
 		yamlEncoder := yaml.NewEncoder(w)
 		return yamlEncoder.Encode(source)
+	case FormatCSV, FormatTSV:
+		kvP, ok := source.(KvProvider)
+		if !ok {
+			return fmt.Errorf("%s format is not supported for this output", format)
+		}
+		return RenderKeyValuesDelimited(w, format, kvP)
 	}
 
 	return fmt.Errorf("unknown rendering format: %s", format)
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+type ClientGroupRenderer struct {
+	ColCountCalculator CalcTerminalColumnsCount
+	Writer             io.Writer
+	Format             string
+}
+
+func (cgr *ClientGroupRenderer) RenderClientGroups(groups []*models.ClientGroup) error {
+	return RenderByFormat(
+		cgr.Format,
+		cgr.Writer,
+		groups,
+		func() error {
+			return cgr.renderClientGroupsInHumanFormat(groups)
+		},
+	)
+}
+
+func (cgr *ClientGroupRenderer) renderClientGroupsInHumanFormat(groups []*models.ClientGroup) error {
+	err := RenderHeader(cgr.Writer, "Client groups")
+	if err != nil {
+		return err
+	}
+
+	rowProviders := make([]RowData, 0, len(groups))
+	for _, g := range groups {
+		rowProviders = append(rowProviders, g)
+	}
+
+	return RenderTable(cgr.Writer, &models.ClientGroup{}, rowProviders, cgr.ColCountCalculator)
+}
+
+func (cgr *ClientGroupRenderer) RenderClientGroup(group *models.ClientGroupDetails) error {
+	return RenderByFormat(
+		cgr.Format,
+		cgr.Writer,
+		group,
+		func() error {
+			return cgr.renderClientGroupInHumanFormat(group)
+		},
+	)
+}
+
+func (cgr *ClientGroupRenderer) renderClientGroupInHumanFormat(group *models.ClientGroupDetails) error {
+	if group == nil {
+		return nil
+	}
+
+	err := RenderHeader(cgr.Writer, fmt.Sprintf("Client group [%s]\n", group.ID))
+	if err != nil {
+		return err
+	}
+
+	RenderKeyValues(cgr.Writer, &group.ClientGroup)
+
+	if group.Params != nil {
+		err = RenderHeader(cgr.Writer, "\nParams")
+		if err != nil {
+			return err
+		}
+		RenderKeyValues(cgr.Writer, group.Params)
+	}
+
+	if len(group.Clients) == 0 {
+		return nil
+	}
+
+	err = RenderHeader(cgr.Writer, "\nClients")
+	if err != nil {
+		return err
+	}
+
+	rowProviders := make([]RowData, 0, len(group.Clients))
+	for _, cl := range group.Clients {
+		rowProviders = append(rowProviders, cl)
+	}
+
+	return RenderTable(cgr.Writer, &models.Client{}, rowProviders, cgr.ColCountCalculator)
+}
+
+func (cgr *ClientGroupRenderer) RenderStatus(os KvProvider) error {
+	return RenderByFormat(
+		cgr.Format,
+		cgr.Writer,
+		os,
+		func() error {
+			RenderKeyValues(cgr.Writer, os)
+			return nil
+		},
+	)
+}
@@ -0,0 +1,128 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderClientGroups(t *testing.T) {
+	groups := []*models.ClientGroup{
+		{
+			ID:          "web",
+			Description: "Web servers",
+			Params:      &models.ClientGroupParams{Name: []string{"web*"}},
+			ClientIDs:   []string{"cl1", "cl2"},
+		},
+	}
+
+	testCases := []struct {
+		Format         string
+		ExpectedOutput string
+	}{
+		{
+			Format: FormatHuman,
+			ExpectedOutput: `Client groups
+ID  DESCRIPTION CLIENTS 
+web Web servers 2       
+`,
+		},
+		{
+			Format: FormatJSON,
+			ExpectedOutput: `[{"id":"web","description":"Web servers","params":{"name":["web*"]},"client_ids":["cl1","cl2"]}]
+`,
+		},
+		{
+			Format: FormatYAML,
+			ExpectedOutput: `- id: web
+  description: Web servers
+  params:
+    name:
+    - web*
+  client_ids:
+  - cl1
+  - cl2
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			cgr := &ClientGroupRenderer{
+				ColCountCalculator: func() int {
+					return 150
+				},
+				Writer: buf,
+				Format: tc.Format,
+			}
+
+			err := cgr.RenderClientGroups(groups)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.ExpectedOutput, buf.String())
+		})
+	}
+}
+
+func TestRenderClientGroup(t *testing.T) {
+	group := &models.ClientGroupDetails{
+		ClientGroup: models.ClientGroup{
+			ID:          "web",
+			Description: "Web servers",
+			Params:      &models.ClientGroupParams{Name: []string{"web*", "www*"}, OSFamily: []string{"linux"}},
+			ClientIDs:   []string{"cl1"},
+		},
+		Clients: []*models.Client{
+			{ID: "cl1", Name: "web1", ConnState: "connected", Address: "127.0.0.1:1234"},
+		},
+	}
+
+	testCases := []struct {
+		Format         string
+		ExpectedOutput string
+	}{
+		{
+			Format: FormatHuman,
+			ExpectedOutput: `Client group [web]
+
+KEY            VALUE       
+ID:            web         
+Description:   Web servers 
+Clients count: 1           
+
+Params
+KEY        VALUE      
+name:      web*, www* 
+os_family: linux      
+
+Clients
+ID  NAME TUNNELS REMOTE ADDRESS HOSTNAME OS KERNEL S 
+cl1 web1 0       127.0.0.1                         C 
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			cgr := &ClientGroupRenderer{
+				ColCountCalculator: func() int {
+					return 150
+				},
+				Writer: buf,
+				Format: tc.Format,
+			}
+
+			err := cgr.RenderClientGroup(group)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.ExpectedOutput, buf.String())
+		})
+	}
+}
@@ -10,12 +10,43 @@ import (
 )
 
 type JobRenderer struct {
-	Writer       io.Writer
-	Format       string
-	IsFullOutput bool
+	ColCountCalculator CalcTerminalColumnsCount
+	Writer             io.Writer
+	Format             string
+	IsFullOutput       bool
+	Columns            []string
+	// GroupByOutput collects finished jobs and renders each distinct output once on Flush
+	GroupByOutput    bool
+	isHeaderRendered bool
+	collectedJobs    []*models.Job
+}
+
+// jobResultColumns are shown by default when job results are rendered as delimiter separated values
+var jobResultColumns = []string{
+	"jid",
+	"client_id",
+	"client_name",
+	"status",
+	"started_at",
+	"finished_at",
+	"command",
+	"stdout",
+	"stderr",
+	"error",
 }
 
 func (jr *JobRenderer) RenderJob(j *models.Job) error {
+	if jr.GroupByOutput {
+		if j.Status != models.JobStatusRunning {
+			jr.collectedJobs = append(jr.collectedJobs, j)
+		}
+		return nil
+	}
+
+	if IsDelimitedFormat(jr.Format) {
+		return jr.renderJobDelimited(j)
+	}
+
 	return RenderByFormat(
 		jr.Format,
 		jr.Writer,
@@ -26,6 +57,174 @@ func (jr *JobRenderer) RenderJob(j *models.Job) error {
 	)
 }
 
+// Flush renders the jobs collected in the group by output mode, each distinct output is rendered once with the list
+// of clients which produced it and a diff against the majority output
+func (jr *JobRenderer) Flush() error {
+	if !jr.GroupByOutput {
+		return nil
+	}
+
+	groups, err := jr.groupJobsByOutput(jr.collectedJobs)
+	if err != nil {
+		return err
+	}
+	jr.collectedJobs = nil
+
+	return RenderByFormat(
+		jr.Format,
+		jr.Writer,
+		groups,
+		func() error {
+			return jr.renderOutputGroupsInHumanFormat(groups)
+		},
+	)
+}
+
+// renderJobDelimited renders one line per job, the header is rendered only once as jobs come one by one
+func (jr *JobRenderer) renderJobDelimited(j *models.Job) error {
+	columns := jr.Columns
+	if len(columns) == 0 {
+		columns = jobResultColumns
+	}
+
+	fields, err := models.JobFields.Select(columns)
+	if err != nil {
+		return err
+	}
+
+	dw := newDelimitedWriter(jr.Writer, jr.Format)
+	if !jr.isHeaderRendered {
+		err = dw.Write(fields.Headers())
+		if err != nil {
+			return err
+		}
+		jr.isHeaderRendered = true
+	}
+
+	err = dw.Write(fields.Row(j))
+	if err != nil {
+		return err
+	}
+
+	dw.Flush()
+
+	return dw.Error()
+}
+
+func (jr *JobRenderer) RenderJobStarted(js *models.JobStarted) error {
+	return RenderByFormat(
+		jr.Format,
+		jr.Writer,
+		js,
+		func() error {
+			RenderKeyValues(jr.Writer, js)
+			_, err := fmt.Fprintf(jr.Writer, "\nThe job runs in background, use 'rportcli command wait %s' to get its results\n", js.Jid)
+			return err
+		},
+	)
+}
+
+func (jr *JobRenderer) RenderJobs(jobs []*models.Job) error {
+	if IsDelimitedFormat(jr.Format) {
+		items := make([]interface{}, 0, len(jobs))
+		for _, j := range jobs {
+			items = append(items, j)
+		}
+
+		return RenderFieldsDelimited(jr.Writer, jr.Format, models.JobFields, jr.Columns, items)
+	}
+
+	return RenderByFormat(
+		jr.Format,
+		jr.Writer,
+		jobs,
+		func() error {
+			items := make([]interface{}, 0, len(jobs))
+			for _, j := range jobs {
+				items = append(items, j)
+			}
+
+			return jr.renderTableInHumanFormat("Jobs", models.JobFields, items)
+		},
+	)
+}
+
+func (jr *JobRenderer) RenderMultiJobs(multiJobs []*models.MultiJob) error {
+	if IsDelimitedFormat(jr.Format) {
+		items := make([]interface{}, 0, len(multiJobs))
+		for _, mj := range multiJobs {
+			items = append(items, mj)
+		}
+
+		return RenderFieldsDelimited(jr.Writer, jr.Format, models.MultiJobFields, jr.Columns, items)
+	}
+
+	return RenderByFormat(
+		jr.Format,
+		jr.Writer,
+		multiJobs,
+		func() error {
+			items := make([]interface{}, 0, len(multiJobs))
+			for _, mj := range multiJobs {
+				items = append(items, mj)
+			}
+
+			return jr.renderTableInHumanFormat("Multi client jobs", models.MultiJobFields, items)
+		},
+	)
+}
+
+func (jr *JobRenderer) RenderMultiJob(multiJob *models.MultiJob) error {
+	return RenderByFormat(
+		jr.Format,
+		jr.Writer,
+		multiJob,
+		func() error {
+			return jr.renderMultiJobInHumanFormat(multiJob)
+		},
+	)
+}
+
+func (jr *JobRenderer) renderTableInHumanFormat(header string, registry *models.FieldRegistry, items []interface{}) error {
+	err := RenderHeader(jr.Writer, header)
+	if err != nil {
+		return err
+	}
+
+	return RenderFieldsTable(jr.Writer, registry, jr.Columns, items, jr.ColCountCalculator)
+}
+
+func (jr *JobRenderer) renderMultiJobInHumanFormat(multiJob *models.MultiJob) error {
+	if multiJob == nil {
+		return nil
+	}
+
+	err := RenderHeader(jr.Writer, fmt.Sprintf("Multi client job [%s]\n", multiJob.Jid))
+	if err != nil {
+		return err
+	}
+
+	RenderKeyValues(jr.Writer, multiJob)
+
+	if len(multiJob.Jobs) == 0 {
+		return nil
+	}
+
+	err = RenderHeader(jr.Writer, "\nJobs")
+	if err != nil {
+		return err
+	}
+
+	for _, j := range multiJob.Jobs {
+		err = jr.renderJobInHumanFormat(j)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (jr *JobRenderer) genShiftedMultilineStr(input, shiftStr string) string {
 	input = strings.Trim(input, "\n")
 	input = strings.TrimSpace(input)
@@ -0,0 +1,140 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+const (
+	DefaultJobFileNameTemplate = "{{.Client}}/{{.Jid}}"
+	jobStdoutFileExt           = ".stdout"
+	jobStderrFileExt           = ".stderr"
+	jobMetadataFileExt         = ".json"
+	jobFilesPermissions        = 0600
+	jobDirsPermissions         = 0755
+)
+
+var unsafeFileNameCharsRegex = regexp.MustCompile(`[^\w.@-]+`)
+
+// JobFilesWriter saves stdout, stderr and metadata of each job to files in the given directory,
+// the file name without extension is rendered from a template, e.g. {{.Client}}/{{.Jid}}
+type JobFilesWriter struct {
+	dir      string
+	fileName *template.Template
+}
+
+// jobFileNameData are values available in the file name template, they are sanitized to be safe path segments
+type jobFileNameData struct {
+	Client     string
+	ClientID   string
+	ClientName string
+	Jid        string
+	MultiJobID string
+	Status     string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+func NewJobFilesWriter(dir, fileNameTemplate string) (*JobFilesWriter, error) {
+	if fileNameTemplate == "" {
+		fileNameTemplate = DefaultJobFileNameTemplate
+	}
+
+	tmpl, err := template.New("file name").Parse(fileNameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file name template '%s': %v", fileNameTemplate, err)
+	}
+
+	// unknown fields are detected only when the template is executed
+	err = tmpl.Execute(ioutil.Discard, &jobFileNameData{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render file name template: %v", err)
+	}
+
+	return &JobFilesWriter{
+		dir:      dir,
+		fileName: tmpl,
+	}, nil
+}
+
+func (jfw *JobFilesWriter) SaveJob(j *models.Job) error {
+	fileName, err := jfw.buildFileName(j)
+	if err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(jfw.dir, fileName)
+	err = os.MkdirAll(filepath.Dir(filePath), jobDirsPermissions)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	for ext, content := range map[string][]byte{
+		jobStdoutFileExt:   []byte(j.Result.Stdout),
+		jobStderrFileExt:   []byte(j.Result.Stderr),
+		jobMetadataFileExt: append(metadata, '\n'),
+	} {
+		err = ioutil.WriteFile(filePath+ext, content, jobFilesPermissions)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (jfw *JobFilesWriter) buildFileName(j *models.Job) (string, error) {
+	client := j.ClientName
+	if client == "" {
+		client = j.ClientID
+	}
+
+	data := &jobFileNameData{
+		Client:     sanitizeFileName(client),
+		ClientID:   sanitizeFileName(j.ClientID),
+		ClientName: sanitizeFileName(j.ClientName),
+		Jid:        sanitizeFileName(j.Jid),
+		MultiJobID: sanitizeFileName(j.MultiJobID),
+		Status:     sanitizeFileName(j.Status),
+		StartedAt:  j.StartedAt,
+		FinishedAt: j.FinishedAt,
+	}
+
+	buf := &bytes.Buffer{}
+	err := jfw.fileName.Execute(buf, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render file name template: %v", err)
+	}
+
+	fileName := filepath.Clean(filepath.FromSlash(strings.TrimSpace(buf.String())))
+	if fileName == "." || filepath.IsAbs(fileName) || fileName == ".." || strings.HasPrefix(fileName, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file name template gives '%s' which is not a file name inside the output directory", buf.String())
+	}
+
+	return fileName, nil
+}
+
+// sanitizeFileName replaces characters which might be interpreted as path separators or are not portable
+func sanitizeFileName(name string) string {
+	name = unsafeFileNameCharsRegex.ReplaceAllString(name, "_")
+	if name == "." || name == ".." {
+		return "_"
+	}
+
+	return name
+}
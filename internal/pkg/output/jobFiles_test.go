@@ -0,0 +1,121 @@
+package output
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestSaveJobFiles(t *testing.T) {
+	dir := t.TempDir()
+	job := &models.Job{
+		Jid:        "1620000000000",
+		Status:     models.JobStatusSuccessful,
+		ClientID:   "cl1",
+		ClientName: "web/server 1",
+		Command:    "uptime",
+		StartedAt:  time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC),
+		Result: models.JobResult{
+			Stdout: "up 10 days",
+			Stderr: "some warning",
+		},
+	}
+
+	jfw, err := NewJobFilesWriter(dir, "")
+	require.NoError(t, err)
+
+	err = jfw.SaveJob(job)
+	require.NoError(t, err)
+
+	basePath := filepath.Join(dir, "web_server_1", "1620000000000")
+
+	stdout, err := ioutil.ReadFile(basePath + ".stdout")
+	require.NoError(t, err)
+	assert.Equal(t, "up 10 days", string(stdout))
+
+	stderr, err := ioutil.ReadFile(basePath + ".stderr")
+	require.NoError(t, err)
+	assert.Equal(t, "some warning", string(stderr))
+
+	rawMetadata, err := ioutil.ReadFile(basePath + ".json")
+	require.NoError(t, err)
+	metadata := &models.Job{}
+	err = json.Unmarshal(rawMetadata, metadata)
+	require.NoError(t, err)
+	assert.Equal(t, job, metadata)
+}
+
+func TestJobFileNameTemplate(t *testing.T) {
+	testCases := []struct {
+		name             string
+		template         string
+		job              *models.Job
+		expectedFileName string
+		expectedError    string
+	}{
+		{
+			name:             "client id without name",
+			template:         DefaultJobFileNameTemplate,
+			job:              &models.Job{Jid: "j1", ClientID: "cl1"},
+			expectedFileName: filepath.Join("cl1", "j1"),
+		},
+		{
+			name:             "custom template",
+			template:         `{{.StartedAt.Format "2006-01-02"}}/{{.Status}}-{{.ClientID}}`,
+			job:              &models.Job{Jid: "j1", ClientID: "cl1", Status: "failed", StartedAt: time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC)},
+			expectedFileName: filepath.Join("2021-05-03", "failed-cl1"),
+		},
+		{
+			name:             "values are not path segments",
+			template:         "{{.ClientName}}",
+			job:              &models.Job{Jid: "j1", ClientID: "cl1", ClientName: ".."},
+			expectedFileName: "_",
+		},
+		{
+			name:          "outside of the directory",
+			template:      "../{{.Jid}}",
+			job:           &models.Job{Jid: "j1", ClientID: "cl1"},
+			expectedError: "file name template gives '../j1' which is not a file name inside the output directory",
+		},
+		{
+			name:          "empty file name",
+			template:      "{{.MultiJobID}}",
+			job:           &models.Job{Jid: "j1", ClientID: "cl1"},
+			expectedError: "file name template gives '' which is not a file name inside the output directory",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			jfw, err := NewJobFilesWriter("results", tc.template)
+			require.NoError(t, err)
+
+			fileName, err := jfw.buildFileName(tc.job)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedFileName, fileName)
+		})
+	}
+}
+
+func TestInvalidJobFileNameTemplate(t *testing.T) {
+	_, err := NewJobFilesWriter("results", "{{.Unknown}}")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to render file name template")
+
+	_, err = NewJobFilesWriter("results", "{{.Jid")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid file name template '{{.Jid'")
+}
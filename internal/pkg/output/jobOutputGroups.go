@@ -0,0 +1,203 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/fatih/color"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const jobOutputDiffContextLines = 3
+
+type jobOutput struct {
+	stdout string
+	stderr string
+}
+
+// groupJobsByOutput buckets jobs with identical stdout and stderr, the groups are sorted by the number of clients,
+// so the first one is the majority output, on a tie the output which was received first wins
+func (jr *JobRenderer) groupJobsByOutput(jobs []*models.Job) ([]*models.JobOutputGroup, error) {
+	groups := make([]*models.JobOutputGroup, 0)
+	groupsByOutput := map[jobOutput]*models.JobOutputGroup{}
+	for _, j := range jobs {
+		key := jobOutput{stdout: j.Result.Stdout, stderr: j.Result.Stderr}
+		group, ok := groupsByOutput[key]
+		if !ok {
+			group = &models.JobOutputGroup{
+				Stdout: j.Result.Stdout,
+				Stderr: j.Result.Stderr,
+			}
+			groupsByOutput[key] = group
+			groups = append(groups, group)
+		}
+		group.Clients = append(group.Clients, jr.extractClientNameOrID(j))
+	}
+
+	if len(groups) == 0 {
+		return groups, nil
+	}
+
+	sort.SliceStable(groups, func(i, k int) bool {
+		return len(groups[i].Clients) > len(groups[k].Clients)
+	})
+
+	majority := groups[0]
+	majority.IsMajority = true
+	for _, group := range groups[1:] {
+		diff, err := diffJobOutputs(majority, group)
+		if err != nil {
+			return nil, err
+		}
+		group.Diff = diff
+	}
+
+	return groups, nil
+}
+
+// diffJobOutputs gives unified diffs of stdout and stderr which differ from the majority output
+func diffJobOutputs(majority, group *models.JobOutputGroup) (string, error) {
+	streams := []struct {
+		name     string
+		majority string
+		output   string
+	}{
+		{name: "stdout", majority: majority.Stdout, output: group.Stdout},
+		{name: "stderr", majority: majority.Stderr, output: group.Stderr},
+	}
+
+	diff := ""
+	for _, stream := range streams {
+		if stream.majority == stream.output {
+			continue
+		}
+
+		streamDiff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitOutputLines(stream.majority),
+			B:        splitOutputLines(stream.output),
+			FromFile: "majority " + stream.name,
+			ToFile:   stream.name,
+			Context:  jobOutputDiffContextLines,
+		})
+		if err != nil {
+			return "", err
+		}
+		diff += streamDiff
+	}
+
+	return diff, nil
+}
+
+// splitOutputLines splits the output to lines ending with a new line, as the diff expects it, unlike difflib.SplitLines
+// an empty output gives no lines rather than an empty one
+func splitOutputLines(output string) []string {
+	if output == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(output, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+
+	return lines
+}
+
+func (jr *JobRenderer) renderOutputGroupsInHumanFormat(groups []*models.JobOutputGroup) error {
+	for i, group := range groups {
+		if i > 0 {
+			_, err := fmt.Fprintln(jr.Writer)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := jr.renderOutputGroupInHumanFormat(group, i+1, len(groups))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (jr *JobRenderer) renderOutputGroupInHumanFormat(group *models.JobOutputGroup, number, total int) error {
+	title := fmt.Sprintf("Output %d of %d", number, total)
+	if group.IsMajority && total > 1 {
+		title += " (majority)"
+	}
+	clientsCount := fmt.Sprintf("%d clients", len(group.Clients))
+	if len(group.Clients) == 1 {
+		clientsCount = "1 client"
+	}
+
+	err := RenderHeader(jr.Writer, fmt.Sprintf("%s, %s: %s", title, clientsCount, strings.Join(group.Clients, ", ")))
+	if err != nil {
+		return err
+	}
+
+	stdOut := jr.genShiftedMultilineStr(group.Stdout, "    ")
+	stdErr := jr.genShiftedMultilineStr(group.Stderr, "    ")
+	if stdOut == "" && stdErr == "" {
+		_, err = fmt.Fprintln(jr.Writer, "    no output")
+		if err != nil {
+			return err
+		}
+	}
+	if stdOut != "" {
+		_, err = color.New(color.FgGreen).Fprintln(jr.Writer, stdOut)
+		if err != nil {
+			return err
+		}
+	}
+	if stdErr != "" {
+		_, err = color.New(color.FgRed).Fprintln(jr.Writer, stdErr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if group.Diff == "" {
+		return nil
+	}
+
+	_, err = fmt.Fprintln(jr.Writer, "  Diff against the majority output:")
+	if err != nil {
+		return err
+	}
+
+	return jr.renderDiff(group.Diff, "    ")
+}
+
+// renderDiff colors added lines green, removed lines red and hunk headers cyan
+func (jr *JobRenderer) renderDiff(diff, shiftStr string) error {
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		var co *color.Color
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			co = color.New(color.Bold)
+		case strings.HasPrefix(line, "@@"):
+			co = color.New(color.FgCyan)
+		case strings.HasPrefix(line, "+"):
+			co = color.New(color.FgGreen)
+		case strings.HasPrefix(line, "-"):
+			co = color.New(color.FgRed)
+		}
+
+		line = shiftStr + strings.TrimSuffix(line, "\r")
+		var err error
+		if co == nil {
+			_, err = fmt.Fprintln(jr.Writer, line)
+		} else {
+			_, err = co.Fprintln(jr.Writer, line)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderGroupedJobs(t *testing.T, format string, jobs []*models.Job) string {
+	buf := &bytes.Buffer{}
+	jr := &JobRenderer{
+		Writer:        buf,
+		Format:        format,
+		GroupByOutput: true,
+	}
+
+	for _, j := range jobs {
+		require.NoError(t, jr.RenderJob(j))
+	}
+	assert.Equal(t, "", buf.String(), "jobs should be rendered only on flush")
+
+	require.NoError(t, jr.Flush())
+
+	return buf.String()
+}
+
+func TestRenderJobsGroupedByOutput(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = noColor
+	}()
+
+	jobs := []*models.Job{
+		{Jid: "1", ClientID: "cl1", ClientName: "srv1", Status: models.JobStatusRunning},
+		{Jid: "1", ClientID: "cl1", ClientName: "srv1", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "a\nb\nc\n"}},
+		{Jid: "2", ClientID: "cl2", Status: models.JobStatusFailed, Result: models.JobResult{Stdout: "a\nx\nc\n", Stderr: "warning\n"}},
+		{Jid: "3", ClientID: "cl3", ClientName: "srv3", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "a\nb\nc\n"}},
+		{Jid: "4", ClientID: "cl4", ClientName: "srv4", Status: models.JobStatusSuccessful},
+	}
+
+	expectedOutput := `Output 1 of 3 (majority), 2 clients: srv1, srv3
+    a
+    b
+    c
+
+Output 2 of 3, 1 client: cl2
+    a
+    x
+    c
+    warning
+  Diff against the majority output:
+    --- majority stdout
+    +++ stdout
+    @@ -1,3 +1,3 @@
+     a
+    -b
+    +x
+     c
+    --- majority stderr
+    +++ stderr
+    @@ -0,0 +1 @@
+    +warning
+
+Output 3 of 3, 1 client: srv4
+    no output
+  Diff against the majority output:
+    --- majority stdout
+    +++ stdout
+    @@ -1,3 +0,0 @@
+    -a
+    -b
+    -c
+`
+	assert.Equal(t, expectedOutput, renderGroupedJobs(t, FormatHuman, jobs))
+}
+
+func TestRenderJobsGroupedByOutputJSON(t *testing.T) {
+	jobs := []*models.Job{
+		{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "v2"}},
+		{Jid: "2", ClientID: "cl2", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "v1"}},
+		{Jid: "3", ClientID: "cl3", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "v1"}},
+	}
+
+	expectedOutput := `[{"clients":["cl2","cl3"],"stdout":"v1","stderr":"","is_majority":true},` +
+		`{"clients":["cl1"],"stdout":"v2","stderr":"","is_majority":false,` +
+		`"diff":"--- majority stdout\n+++ stdout\n@@ -1 +1 @@\n-v1\n+v2\n"}]
+`
+	assert.Equal(t, expectedOutput, renderGroupedJobs(t, FormatJSON, jobs))
+}
+
+func TestRenderJobsGroupedByOutputSingleOutput(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = noColor
+	}()
+
+	jobs := []*models.Job{
+		{Jid: "1", ClientID: "cl1", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "ok"}},
+		{Jid: "2", ClientID: "cl2", Status: models.JobStatusSuccessful, Result: models.JobResult{Stdout: "ok"}},
+	}
+
+	assert.Equal(t, "Output 1 of 1, 2 clients: cl1, cl2\n    ok\n", renderGroupedJobs(t, FormatHuman, jobs))
+}
+
+func TestSplitOutputLines(t *testing.T) {
+	assert.Nil(t, splitOutputLines(""))
+	assert.Equal(t, []string{"a\n", "b\n"}, splitOutputLines("a\nb\n"))
+	assert.Equal(t, []string{"a\n", "b\n"}, splitOutputLines("a\nb"))
+	assert.Equal(t, []string{"\n"}, splitOutputLines("\n"))
+}
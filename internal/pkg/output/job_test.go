@@ -7,6 +7,7 @@ import (
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRenderJob(t *testing.T) {
@@ -155,3 +156,173 @@ interpreter: cmd
 		})
 	}
 }
+
+func TestRenderJobs(t *testing.T) {
+	timeToCheck, err := time.Parse(time.RFC3339, "2021-01-01T00:00:01Z")
+	require.NoError(t, err)
+
+	jobs := []*models.Job{
+		{
+			Jid:        "123",
+			Status:     "successful",
+			ClientName: "some cl name",
+			ClientID:   "cl123",
+			StartedAt:  timeToCheck,
+			FinishedAt: timeToCheck,
+			Command:    "ls",
+		},
+		{
+			Jid:       "124",
+			Status:    "running",
+			ClientID:  "cl124",
+			StartedAt: timeToCheck,
+			Command:   "pwd",
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	jr := &JobRenderer{
+		ColCountCalculator: func() int {
+			return 150
+		},
+		Writer: buf,
+		Format: FormatHuman,
+	}
+
+	err = jr.RenderJobs(jobs)
+	require.NoError(t, err)
+
+	assert.Equal(t, `Jobs
+JID CLIENT       STATUS     STARTED AT           FINISHED AT          COMMAND 
+123 some cl name successful 2021-01-01T00:00:01Z 2021-01-01T00:00:01Z ls      
+124 cl124        running    2021-01-01T00:00:01Z                      pwd     
+`, buf.String())
+}
+
+func TestRenderMultiJobsWithColumns(t *testing.T) {
+	multiJobs := []*models.MultiJob{
+		{
+			Jid:         "mj1",
+			ClientIDs:   []string{"cl1", "cl2"},
+			Interpreter: "powershell",
+			IsSudo:      true,
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	jr := &JobRenderer{
+		ColCountCalculator: func() int {
+			return 150
+		},
+		Writer:  buf,
+		Format:  FormatHuman,
+		Columns: []string{"jid", "client_ids", "interpreter", "is_sudo"},
+	}
+
+	err := jr.RenderMultiJobs(multiJobs)
+	require.NoError(t, err)
+
+	assert.Equal(t, `Multi client jobs
+JID CLIENT IDS INTERPRETER SUDO 
+mj1 cl1, cl2   powershell  true 
+`, buf.String())
+}
+
+func TestRenderMultiJob(t *testing.T) {
+	timeToCheck, err := time.Parse(time.RFC3339, "2021-01-01T00:00:01Z")
+	require.NoError(t, err)
+
+	multiJob := &models.MultiJob{
+		Jid:        "mj1",
+		StartedAt:  timeToCheck,
+		CreatedBy:  "admin",
+		ClientIDs:  []string{"cl1"},
+		Command:    "ls",
+		TimeoutSec: 60,
+		Jobs: []*models.Job{
+			{
+				ClientName: "client 1",
+				Result: models.JobResult{
+					Stdout: "file1",
+				},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	jr := &JobRenderer{
+		Writer: buf,
+		Format: FormatHuman,
+	}
+
+	err = jr.RenderMultiJob(multiJob)
+	require.NoError(t, err)
+
+	assert.Equal(t, `Multi client job [mj1]
+
+KEY             VALUE                
+Multi Job ID:   mj1                  
+Started at:     2021-01-01T00:00:01Z 
+Created By:     admin                
+Client IDs:     cl1                  
+Group IDs:                           
+Command:        ls                   
+Interpreter:                         
+Cwd:                                 
+Is sudo:        false                
+Timeout sec:    60                   
+Concurrent:     false                
+Abort on error: false                
+
+Jobs
+client 1
+    file1
+`, buf.String())
+}
+
+func TestRenderJobResultsDelimited(t *testing.T) {
+	jobs := []*models.Job{
+		{
+			Jid:        "123",
+			Status:     "success",
+			ClientID:   "cl1",
+			ClientName: "first",
+			Command:    "ls",
+			Result: models.JobResult{
+				Stdout: "file1\nfile2",
+			},
+		},
+		{
+			Jid:        "124",
+			Status:     "failed",
+			ClientID:   "cl2",
+			ClientName: "second",
+			Command:    "ls",
+			Error:      "exit code 1",
+			Result: models.JobResult{
+				Stderr: "no \"such\" dir",
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	jr := &JobRenderer{
+		Writer:  buf,
+		Format:  FormatCSV,
+		Columns: []string{"jid", "client_name", "status", "stdout", "stderr", "error"},
+	}
+	for _, j := range jobs {
+		err := jr.RenderJob(j)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(
+		t,
+		`JID,CLIENT NAME,STATUS,STDOUT,STDERR,ERROR
+123,first,success,"file1
+file2",,
+124,second,failed,,"no ""such"" dir",exit code 1
+`,
+		buf.String(),
+	)
+}
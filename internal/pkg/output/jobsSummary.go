@@ -0,0 +1,28 @@
+package output
+
+import (
+	"io"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+// JobsSummaryRenderer renders results of a multi client job in human format, it's written next to the job results,
+// so it doesn't depend on the output format
+type JobsSummaryRenderer struct {
+	ColCountCalculator CalcTerminalColumnsCount
+	Writer             io.Writer
+}
+
+func (jsr *JobsSummaryRenderer) RenderJobsSummary(s *models.JobsSummary) error {
+	err := RenderHeader(jsr.Writer, "\nSummary: "+s.Title())
+	if err != nil {
+		return err
+	}
+
+	rowProviders := make([]RowData, 0, len(s.Clients))
+	for _, cl := range s.Clients {
+		rowProviders = append(rowProviders, cl)
+	}
+
+	return RenderTable(jsr.Writer, &models.JobSummaryClient{}, rowProviders, jsr.ColCountCalculator)
+}
@@ -0,0 +1,38 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestRenderJobsSummary(t *testing.T) {
+	buf := &bytes.Buffer{}
+	jsr := &JobsSummaryRenderer{Writer: buf}
+
+	err := jsr.RenderJobsSummary(&models.JobsSummary{
+		Clients: []*models.JobSummaryClient{
+			{ClientID: "cl1", ClientName: "srv1", Result: models.JobResultSucceeded, Duration: 1500 * time.Millisecond},
+			{ClientID: "cl2", Result: models.JobResultFailed, Duration: 200 * time.Millisecond, Error: "exit status 1"},
+			{ClientID: "cl3", Result: models.JobResultTimedOut, Duration: 30 * time.Second},
+			{ClientID: "cl4", ClientName: "srv4", Result: models.JobResultUnreachable},
+		},
+		Duration: 31 * time.Second,
+	})
+	require.NoError(t, err)
+
+	expectedOutput := `
+Summary: 1 of 4 clients succeeded, 1 failed, 1 timed out, 1 unreachable in 31s
+RESULT      CLIENT ID CLIENT NAME DURATION ERROR         
+succeeded   cl1       srv1        1.5s                   
+failed      cl2                   200ms    exit status 1 
+timed out   cl3                   30s                    
+unreachable cl4       srv4                               
+`
+	assert.Equal(t, expectedOutput, buf.String())
+}
@@ -0,0 +1,254 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ohler55/ojg/jp"
+)
+
+// jsonPathNode is a part of a jsonpath template: a plain text, a {"quoted"} text, a {.path} expression
+// or a {range .path}...{end} block
+type jsonPathNode struct {
+	text     string
+	expr     jp.Expr
+	isRange  bool
+	children []*jsonPathNode
+}
+
+// parseJSONPath parses a kubectl like jsonpath template, e.g. {.[*].id} or {range .[*]}{.id}{"\n"}{end},
+// missing keys and indexes are rendered as empty values
+func parseJSONPath(tmpl string) (templateRenderFunc, error) {
+	nodes, rest, err := parseJSONPathNodes(tmpl, false)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, errors.New("{end} is not in range")
+	}
+
+	return func(w io.Writer, source interface{}) error {
+		data, err := toJSONData(source)
+		if err != nil {
+			return err
+		}
+
+		return renderJSONPathNodes(w, nodes, data)
+	}, nil
+}
+
+// parseJSONPathNodes parses the template till its end or till {end} if it's the body of a range,
+// the template following {end} is returned as well
+func parseJSONPathNodes(tmpl string, isRangeBody bool) (nodes []*jsonPathNode, rest string, err error) {
+	for tmpl != "" {
+		start := strings.IndexByte(tmpl, '{')
+		if start < 0 {
+			nodes = append(nodes, &jsonPathNode{text: tmpl})
+			break
+		}
+		if start > 0 {
+			nodes = append(nodes, &jsonPathNode{text: tmpl[:start]})
+		}
+
+		end := findActionEnd(tmpl, start+1)
+		if end < 0 {
+			return nil, "", errors.New("unclosed action")
+		}
+		action := strings.TrimSpace(tmpl[start+1 : end])
+		tmpl = tmpl[end+1:]
+
+		switch {
+		case action == "end":
+			if !isRangeBody {
+				return nil, "", errors.New("{end} is not in range")
+			}
+			return nodes, tmpl, nil
+		case strings.HasPrefix(action, "range "):
+			node := &jsonPathNode{isRange: true}
+			node.expr, err = parseJSONPathExpr(strings.TrimPrefix(action, "range "))
+			if err != nil {
+				return nil, "", err
+			}
+			node.children, tmpl, err = parseJSONPathNodes(tmpl, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, node)
+		case strings.HasPrefix(action, `"`):
+			text, err := strconv.Unquote(action)
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid text %s: %v", action, err)
+			}
+			nodes = append(nodes, &jsonPathNode{text: text})
+		default:
+			expr, err := parseJSONPathExpr(action)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, &jsonPathNode{expr: expr})
+		}
+	}
+
+	if isRangeBody {
+		return nil, "", errors.New("range is not closed with {end}")
+	}
+
+	return nodes, "", nil
+}
+
+// findActionEnd gives the position of the closing brace of an action, braces in quoted texts are skipped
+func findActionEnd(tmpl string, pos int) int {
+	var quote byte
+	for i := pos; i < len(tmpl); i++ {
+		switch {
+		case quote != 0 && tmpl[i] == '\\':
+			i++
+		case quote != 0 && tmpl[i] == quote:
+			quote = 0
+		case quote == 0 && (tmpl[i] == '"' || tmpl[i] == '\''):
+			quote = tmpl[i]
+		case quote == 0 && tmpl[i] == '}':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parseJSONPathExpr parses a path relative to the current value like .[*].id, ..name or @
+func parseJSONPathExpr(path string) (jp.Expr, error) {
+	switch {
+	case strings.HasPrefix(path, "@"):
+		path = "$" + path[1:]
+	case strings.HasPrefix(path, ".["):
+		path = "$" + path[1:]
+	case strings.HasPrefix(path, "."):
+		path = "$" + path
+	}
+
+	expr, err := jp.ParseString(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path '%s': %v", path, err)
+	}
+
+	return expr, nil
+}
+
+// getJSONPathValues gives the values of the path in the order of the data, the path is evaluated fragment by fragment,
+// as jp gives the values of filters followed by other fragments in reverse order
+func getJSONPathValues(expr jp.Expr, data interface{}) []interface{} {
+	values := []interface{}{data}
+	for _, frag := range expr {
+		switch frag.(type) {
+		case jp.Root, jp.At, jp.Bracket:
+			continue
+		}
+
+		nextValues := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			nextValues = append(nextValues, jp.Expr{frag}.Get(value)...)
+		}
+		values = nextValues
+	}
+
+	return values
+}
+
+func renderJSONPathNodes(w io.Writer, nodes []*jsonPathNode, data interface{}) error {
+	for _, node := range nodes {
+		var err error
+		switch {
+		case node.isRange:
+			for _, value := range getJSONPathValues(node.expr, data) {
+				err = renderJSONPathNodes(w, node.children, value)
+				if err != nil {
+					return err
+				}
+			}
+		case node.expr != nil:
+			err = renderJSONPathValues(w, getJSONPathValues(node.expr, data))
+		default:
+			_, err = io.WriteString(w, node.text)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderJSONPathValues writes the values separated by spaces, objects and lists are written as json
+func renderJSONPathValues(w io.Writer, values []interface{}) error {
+	for i, value := range values {
+		if i > 0 {
+			if _, err := io.WriteString(w, " "); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		switch v := value.(type) {
+		case map[string]interface{}, []interface{}:
+			var rawValue []byte
+			rawValue, err = json.Marshal(v)
+			if err == nil {
+				_, err = w.Write(rawValue)
+			}
+		case nil:
+		default:
+			_, err = fmt.Fprint(w, v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toJSONData converts the source to generic json values, so templates refer to the json field names,
+// numbers are kept as int64 or float64, so integers are not rendered in the exponent format
+func toJSONData(source interface{}) (interface{}, error) {
+	rawData, err := json.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(rawData))
+	decoder.UseNumber()
+
+	var data interface{}
+	err = decoder.Decode(&data)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertJSONNumbers(data), nil
+}
+
+func convertJSONNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = convertJSONNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertJSONNumbers(item)
+		}
+	case json.Number:
+		if intValue, err := v.Int64(); err == nil {
+			return intValue
+		}
+		floatValue, _ := v.Float64()
+		return floatValue
+	}
+
+	return value
+}
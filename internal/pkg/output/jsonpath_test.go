@@ -0,0 +1,112 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonPathTestItem struct {
+	ID      string            `json:"id"`
+	Count   int               `json:"count"`
+	Enabled bool              `json:"enabled"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+}
+
+func TestJSONPath(t *testing.T) {
+	source := []jsonPathTestItem{
+		{ID: "one", Count: 1, Enabled: true, Tags: []string{"a", "b"}, Labels: map[string]string{"env": "prod"}},
+		{ID: "two", Count: 2, Tags: []string{"c"}, Labels: map[string]string{"env": "dev", "team": "ops"}},
+		{ID: "three", Count: 3},
+	}
+
+	testCases := []struct {
+		template       string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			template:       "{.[*].id}",
+			expectedOutput: "one two three",
+		},
+		{
+			template:       "{.[0].count} {.[1].enabled}",
+			expectedOutput: "1 false",
+		},
+		{
+			template:       "{.[-1].id}",
+			expectedOutput: "three",
+		},
+		{
+			template:       "{.[0:2].id}",
+			expectedOutput: "one two",
+		},
+		{
+			template:       "{..env}",
+			expectedOutput: "prod dev",
+		},
+		{
+			template:       "{.[1].labels.team}{.[0].unknown}",
+			expectedOutput: "ops",
+		},
+		{
+			template:       "{.[?(@.count>0)].labels.env}",
+			expectedOutput: "prod dev",
+		},
+		{
+			template:       `{.[?(@.labels.env=="dev")].id}`,
+			expectedOutput: "two",
+		},
+		{
+			template:       `{range .[*]}{.id}{"\t"}{range .tags[*]}[{@}]{end}{"\n"}{end}`,
+			expectedOutput: "one\t[a][b]\ntwo\t[c]\nthree\t\n",
+		},
+		{
+			template:      "{.[0].id",
+			expectedError: "unclosed action",
+		},
+		{
+			template:       "{.[5].id}",
+			expectedOutput: "",
+		},
+		{
+			template:       "{.[0].tags} {@[2].id}",
+			expectedOutput: `["a","b"] three`,
+		},
+		{
+			template:       `{"{}"}{.[?(@.id=="}")].id}`,
+			expectedOutput: "{}",
+		},
+		{
+			template:      "{end}",
+			expectedError: "{end} is not in range",
+		},
+		{
+			template:      "{range .[*]}{.id}",
+			expectedError: "range is not closed with {end}",
+		},
+		{
+			template:      "{.[x]}",
+			expectedError: "invalid path '$[x]'",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.template, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := RenderTemplateFormat(buf, FormatJSONPathPrefix+tc.template, source)
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, buf.String())
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package output
+
+import (
+	"io"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+type ProfileRenderer struct {
+	ColCountCalculator CalcTerminalColumnsCount
+	Writer             io.Writer
+	Format             string
+}
+
+func (pr *ProfileRenderer) RenderProfiles(profiles []*models.Profile) error {
+	return RenderByFormat(
+		pr.Format,
+		pr.Writer,
+		profiles,
+		func() error {
+			return pr.renderProfilesInHumanFormat(profiles)
+		},
+	)
+}
+
+func (pr *ProfileRenderer) renderProfilesInHumanFormat(profiles []*models.Profile) error {
+	err := RenderHeader(pr.Writer, "Profiles")
+	if err != nil {
+		return err
+	}
+
+	rowProviders := make([]RowData, 0, len(profiles))
+	for _, p := range profiles {
+		rowProviders = append(rowProviders, p)
+	}
+
+	return RenderTable(pr.Writer, &models.Profile{}, rowProviders, pr.ColCountCalculator)
+}
+
+func (pr *ProfileRenderer) RenderStatus(os KvProvider) error {
+	return RenderByFormat(
+		pr.Format,
+		pr.Writer,
+		os,
+		func() error {
+			RenderKeyValues(pr.Writer, os)
+			return nil
+		},
+	)
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderProfiles(t *testing.T) {
+	profiles := []*models.Profile{
+		{
+			Name:      "prod",
+			ServerURL: "https://prod:3000",
+			IsCurrent: true,
+		},
+		{
+			Name:      "staging",
+			ServerURL: "https://staging:3000",
+		},
+	}
+
+	testCases := []struct {
+		Format         string
+		ExpectedOutput string
+	}{
+		{
+			Format: FormatHuman,
+			ExpectedOutput: `Profiles
+CURRENT NAME    SERVER               
+*       prod    https://prod:3000    
+        staging https://staging:3000 
+`,
+		},
+		{
+			Format: FormatJSON,
+			ExpectedOutput: `[{"name":"prod","server":"https://prod:3000","is_current":true},{"name":"staging","server":"https://staging:3000","is_current":false}]
+`,
+		},
+		{
+			Format: FormatYAML,
+			ExpectedOutput: `- name: prod
+  server: https://prod:3000
+  is_current: true
+- name: staging
+  server: https://staging:3000
+  is_current: false
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			pr := &ProfileRenderer{
+				ColCountCalculator: func() int {
+					return 150
+				},
+				Writer: buf,
+				Format: tc.Format,
+			}
+
+			err := pr.RenderProfiles(profiles)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.ExpectedOutput, buf.String())
+		})
+	}
+}
@@ -1,8 +1,22 @@
 package output
 
 import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
 	"io"
 	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+)
+
+const (
+	qrFinderPatternModules = 7
+	qrQuietZoneModules     = 2
+	qrDarknessThreshold    = 128
 )
 
 func GetQrImageFsWriter(namePattern string) (io.Writer, io.Closer, string, error) {
@@ -15,3 +29,152 @@ func GetQrImageFsWriter(namePattern string) (io.Writer, io.Closer, string, error
 
 	return tempFile, tempFile, fileName, nil
 }
+
+// DeleteQrImageFile overwrites the image with zeros before removing it, so the secret can't be restored from the disk
+func DeleteQrImageFile(name string) error {
+	f, err := os.OpenFile(name, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	fileInfo, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	_, err = f.Write(make([]byte, fileInfo.Size()))
+	if err == nil {
+		err = f.Sync()
+	}
+	closeErr := f.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	return os.Remove(name)
+}
+
+// RenderQrImageToText converts a QR code PNG image to text with Unicode half blocks, so it can be scanned
+// from the terminal, light modules are drawn as blocks to look right on the usual dark terminal background
+func RenderQrImageToText(pngData []byte) (string, error) {
+	img, err := png.Decode(bytes.NewReader(pngData))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode qr code image: %v", err)
+	}
+
+	modules, err := readQrModules(img)
+	if err != nil {
+		return "", err
+	}
+
+	return renderQrModules(modules), nil
+}
+
+// readQrModules samples dark modules of the QR code, the module size is detected by the width
+// of the top left finder pattern which is 7 modules wide
+func readQrModules(img image.Image) ([][]bool, error) {
+	bounds := img.Bounds()
+	minX, minY, maxX, maxY := bounds.Max.X, bounds.Max.Y, bounds.Min.X-1, bounds.Min.Y-1
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if !isDarkPixel(img.At(x, y)) {
+				continue
+			}
+			minX = minInt(minX, x)
+			minY = minInt(minY, y)
+			maxX = maxInt(maxX, x)
+			maxY = maxInt(maxY, y)
+		}
+	}
+	if maxX < minX {
+		return nil, fmt.Errorf("no qr code found in the image")
+	}
+
+	finderWidth := 0
+	for x := minX; x <= maxX && isDarkPixel(img.At(x, minY)); x++ {
+		finderWidth++
+	}
+	moduleSize := float64(finderWidth) / qrFinderPatternModules
+	if moduleSize < 1 {
+		return nil, fmt.Errorf("qr code image resolution is too low")
+	}
+
+	size := int(math.Round(float64(maxX-minX+1) / moduleSize))
+	if size < 21 || (size-21)%4 != 0 {
+		return nil, fmt.Errorf("unexpected qr code size of %d modules", size)
+	}
+
+	modules := make([][]bool, size)
+	for row := range modules {
+		modules[row] = make([]bool, size)
+		for col := range modules[row] {
+			x := minX + int((float64(col)+0.5)*moduleSize)
+			y := minY + int((float64(row)+0.5)*moduleSize)
+			modules[row][col] = isDarkPixel(img.At(x, y))
+		}
+	}
+
+	return modules, nil
+}
+
+// renderQrModules draws two rows of modules per text line surrounded by a quiet zone
+func renderQrModules(modules [][]bool) string {
+	isLight := func(row, col int) bool {
+		row -= qrQuietZoneModules
+		col -= qrQuietZoneModules
+		if row < 0 || row >= len(modules) || col < 0 || col >= len(modules[row]) {
+			return true
+		}
+		return !modules[row][col]
+	}
+
+	size := len(modules) + 2*qrQuietZoneModules
+	sb := strings.Builder{}
+	for row := 0; row < size; row += 2 {
+		for col := 0; col < size; col++ {
+			isTopLight := isLight(row, col)
+			isBottomLight := row+1 < size && isLight(row+1, col)
+			switch {
+			case isTopLight && isBottomLight:
+				sb.WriteString("█")
+			case isTopLight:
+				sb.WriteString("▀")
+			case isBottomLight:
+				sb.WriteString("▄")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// isDarkPixel treats transparent pixels as light background
+func isDarkPixel(c color.Color) bool {
+	_, _, _, alpha := c.RGBA()
+	if alpha < math.MaxUint16/2 {
+		return false
+	}
+
+	return color.GrayModel.Convert(c).(color.Gray).Y < qrDarknessThreshold
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
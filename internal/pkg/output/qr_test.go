@@ -0,0 +1,114 @@
+package output
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildQrModules gives a grid with the finder patterns of a QR code and some data modules
+func buildQrModules(size int) [][]bool {
+	modules := make([][]bool, size)
+	for row := range modules {
+		modules[row] = make([]bool, size)
+		for col := range modules[row] {
+			modules[row][col] = (row*7+col*3)%5 == 0
+		}
+	}
+
+	for _, corner := range [][2]int{{0, 0}, {0, size - 7}, {size - 7, 0}} {
+		for row := 0; row < 7; row++ {
+			for col := 0; col < 7; col++ {
+				isRing := row == 0 || row == 6 || col == 0 || col == 6
+				isCenter := row >= 2 && row <= 4 && col >= 2 && col <= 4
+				modules[corner[0]+row][corner[1]+col] = isRing || isCenter
+			}
+		}
+	}
+
+	return modules
+}
+
+func buildQrPNG(t *testing.T, modules [][]bool, scale, margin int) []byte {
+	imgSize := len(modules)*scale + 2*margin
+	img := image.NewGray(image.Rect(0, 0, imgSize, imgSize))
+	for y := 0; y < imgSize; y++ {
+		for x := 0; x < imgSize; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	for row := range modules {
+		for col := range modules[row] {
+			if !modules[row][col] {
+				continue
+			}
+			for y := 0; y < scale; y++ {
+				for x := 0; x < scale; x++ {
+					img.SetGray(margin+col*scale+x, margin+row*scale+y, color.Gray{Y: 0})
+				}
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	err := png.Encode(buf, img)
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+func TestReadQrModules(t *testing.T) {
+	for _, size := range []int{21, 29} {
+		modules := buildQrModules(size)
+		pngData := buildQrPNG(t, modules, 3, 10)
+
+		img, err := png.Decode(bytes.NewReader(pngData))
+		require.NoError(t, err)
+
+		actualModules, err := readQrModules(img)
+		require.NoError(t, err)
+		assert.Equal(t, modules, actualModules)
+
+		text, err := RenderQrImageToText(pngData)
+		require.NoError(t, err)
+		assert.Equal(t, renderQrModules(modules), text)
+	}
+}
+
+func TestRenderQrModules(t *testing.T) {
+	modules := [][]bool{
+		{true, false, true},
+		{false, true, false},
+		{true, true, true},
+	}
+
+	assert.Equal(t, "███████\n██▄▀▄██\n██▄▄▄██\n▀▀▀▀▀▀▀\n", renderQrModules(modules))
+}
+
+func TestRenderQrImageToTextErrors(t *testing.T) {
+	_, err := RenderQrImageToText([]byte("not an image"))
+	assert.EqualError(t, err, "failed to decode qr code image: png: invalid format: not a PNG file")
+
+	_, err = RenderQrImageToText(buildQrPNG(t, [][]bool{{false}}, 3, 10))
+	assert.EqualError(t, err, "no qr code found in the image")
+}
+
+func TestDeleteQrImageFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "qr.png")
+	err := ioutil.WriteFile(filePath, []byte("qr code"), 0600)
+	require.NoError(t, err)
+
+	err = DeleteQrImageFile(filePath)
+	require.NoError(t, err)
+
+	_, err = os.Stat(filePath)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,111 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+type ScheduleRenderer struct {
+	ColCountCalculator CalcTerminalColumnsCount
+	Writer             io.Writer
+	Format             string
+}
+
+func (sr *ScheduleRenderer) RenderSchedules(schedules []*models.Schedule) error {
+	return RenderByFormat(
+		sr.Format,
+		sr.Writer,
+		schedules,
+		func() error {
+			return sr.renderSchedulesInHumanFormat(schedules)
+		},
+	)
+}
+
+func (sr *ScheduleRenderer) renderSchedulesInHumanFormat(schedules []*models.Schedule) error {
+	err := RenderHeader(sr.Writer, "Schedules")
+	if err != nil {
+		return err
+	}
+
+	rowProviders := make([]RowData, 0, len(schedules))
+	for _, s := range schedules {
+		rowProviders = append(rowProviders, s)
+	}
+
+	return RenderTable(sr.Writer, &models.Schedule{}, rowProviders, sr.ColCountCalculator)
+}
+
+func (sr *ScheduleRenderer) RenderSchedule(schedule *models.Schedule) error {
+	return RenderByFormat(
+		sr.Format,
+		sr.Writer,
+		schedule,
+		func() error {
+			return sr.renderScheduleInHumanFormat(schedule)
+		},
+	)
+}
+
+func (sr *ScheduleRenderer) renderScheduleInHumanFormat(schedule *models.Schedule) error {
+	if schedule == nil {
+		return nil
+	}
+
+	err := RenderHeader(sr.Writer, fmt.Sprintf("Schedule [%s]\n", schedule.ID))
+	if err != nil {
+		return err
+	}
+
+	RenderKeyValues(sr.Writer, schedule)
+
+	timezone := time.UTC.String()
+	if len(schedule.NextRuns) > 0 {
+		timezone = schedule.NextRuns[0].Location().String()
+	}
+	err = RenderHeader(sr.Writer, fmt.Sprintf("\nNext runs (%s)", timezone))
+	if err != nil {
+		return err
+	}
+
+	for _, nextRun := range schedule.NextRuns {
+		_, err = fmt.Fprintln(sr.Writer, nextRun.Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+	}
+
+	if schedule.Type != models.ScheduleTypeScript {
+		return nil
+	}
+
+	err = RenderHeader(sr.Writer, "\nScript")
+	if err != nil {
+		return err
+	}
+
+	content := strings.TrimSuffix(schedule.ScriptContent(), "\n")
+	if content == "" {
+		return nil
+	}
+
+	_, err = fmt.Fprintln(sr.Writer, content)
+
+	return err
+}
+
+func (sr *ScheduleRenderer) RenderStatus(os KvProvider) error {
+	return RenderByFormat(
+		sr.Format,
+		sr.Writer,
+		os,
+		func() error {
+			RenderKeyValues(sr.Writer, os)
+			return nil
+		},
+	)
+}
@@ -0,0 +1,140 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSchedules(t *testing.T) {
+	schedules := []*models.Schedule{
+		{
+			ID:       "5",
+			Name:     "cleanup",
+			Schedule: "0 3 * * *",
+			Type:     models.ScheduleTypeCommand,
+			Details: models.WsScriptCommand{
+				ClientIDs: []string{"cl1", "cl2"},
+				Command:   "rm -rf /tmp/*",
+			},
+			NextRuns: []time.Time{time.Date(2021, 3, 4, 3, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	sr := &ScheduleRenderer{
+		ColCountCalculator: func() int {
+			return 150
+		},
+		Writer: buf,
+		Format: FormatHuman,
+	}
+
+	err := sr.RenderSchedules(schedules)
+	require.NoError(t, err)
+
+	assert.Equal(t, `Schedules
+ID NAME    SCHEDULE  TYPE    CLIENT IDS GROUP IDS NEXT RUN             
+5  cleanup 0 3 * * * command cl1, cl2             2021-03-04T03:00:00Z 
+`, buf.String())
+}
+
+func TestRenderScriptSchedule(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sr := &ScheduleRenderer{
+		Writer: buf,
+		Format: FormatHuman,
+	}
+
+	err := sr.RenderSchedule(&models.Schedule{
+		ID:       "5",
+		Name:     "cleanup",
+		Schedule: "0 3 * * *",
+		Type:     models.ScheduleTypeScript,
+		Details: models.WsScriptCommand{
+			GroupIDs:    []string{"linux"},
+			Script:      "cm0gLXJmIC90bXAvKgo=",
+			Interpreter: "/bin/bash",
+			TimeoutSec:  60,
+		},
+		CreatedBy: "admin",
+		NextRuns: []time.Time{
+			time.Date(2021, 3, 4, 3, 0, 0, 0, time.UTC),
+			time.Date(2021, 3, 5, 3, 0, 0, 0, time.UTC),
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `Schedule [5]
+
+KEY             VALUE     
+ID:             5         
+Name:           cleanup   
+Schedule:       0 3 * * * 
+Type:           script    
+Client IDs:               
+Group IDs:      linux     
+Interpreter:    /bin/bash 
+Cwd:                      
+Is sudo:        false     
+Timeout sec:    60        
+Concurrent:     false     
+Abort on error: false     
+Created By:     admin     
+Created At:               
+
+Next runs (UTC)
+2021-03-04T03:00:00Z
+2021-03-05T03:00:00Z
+
+Script
+rm -rf /tmp/*
+`, buf.String())
+}
+
+func TestRenderScheduleNextRunsInTimezone(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sr := &ScheduleRenderer{
+		Writer: buf,
+		Format: FormatHuman,
+	}
+
+	timezone := time.FixedZone("UTC+2", 2*60*60)
+	err := sr.RenderSchedule(&models.Schedule{
+		ID:       "5",
+		Name:     "cleanup",
+		Schedule: "0 3 * * *",
+		Type:     models.ScheduleTypeCommand,
+		NextRuns: []time.Time{time.Date(2021, 3, 4, 3, 0, 0, 0, timezone)},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "\nNext runs (UTC+2)\n2021-03-04T03:00:00+02:00\n")
+}
+
+func TestRenderScheduleJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sr := &ScheduleRenderer{
+		Writer: buf,
+		Format: FormatJSON,
+	}
+
+	err := sr.RenderSchedule(&models.Schedule{
+		ID:       "5",
+		Name:     "cleanup",
+		Schedule: "@daily",
+		Type:     models.ScheduleTypeCommand,
+		Details:  models.WsScriptCommand{ClientIDs: []string{"cl1"}, Command: "uptime"},
+		NextRuns: []time.Time{time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"id":"5","name":"cleanup","schedule":"@daily","type":"command","details":{"client_ids":["cl1"],"is_sudo":false,`+
+		`"execute_concurrently":false,"abort_on_error":false,"timeout_sec":0,"command":"uptime","script":"","cwd":"","interpreter":""},`+
+		`"next_runs":["2021-03-04T00:00:00Z"]}
+`, buf.String())
+}
@@ -0,0 +1,90 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+type ScriptRenderer struct {
+	ColCountCalculator CalcTerminalColumnsCount
+	Writer             io.Writer
+	Format             string
+}
+
+func (sr *ScriptRenderer) RenderScripts(scripts []*models.Script) error {
+	return RenderByFormat(
+		sr.Format,
+		sr.Writer,
+		scripts,
+		func() error {
+			return sr.renderScriptsInHumanFormat(scripts)
+		},
+	)
+}
+
+func (sr *ScriptRenderer) renderScriptsInHumanFormat(scripts []*models.Script) error {
+	err := RenderHeader(sr.Writer, "Scripts")
+	if err != nil {
+		return err
+	}
+
+	rowProviders := make([]RowData, 0, len(scripts))
+	for _, s := range scripts {
+		rowProviders = append(rowProviders, s)
+	}
+
+	return RenderTable(sr.Writer, &models.Script{}, rowProviders, sr.ColCountCalculator)
+}
+
+func (sr *ScriptRenderer) RenderScript(script *models.Script) error {
+	return RenderByFormat(
+		sr.Format,
+		sr.Writer,
+		script,
+		func() error {
+			return sr.renderScriptInHumanFormat(script)
+		},
+	)
+}
+
+func (sr *ScriptRenderer) renderScriptInHumanFormat(script *models.Script) error {
+	if script == nil {
+		return nil
+	}
+
+	err := RenderHeader(sr.Writer, fmt.Sprintf("Script [%s]\n", script.ID))
+	if err != nil {
+		return err
+	}
+
+	RenderKeyValues(sr.Writer, script)
+
+	err = RenderHeader(sr.Writer, "\nContent")
+	if err != nil {
+		return err
+	}
+
+	content := strings.TrimSuffix(script.Content, "\n")
+	if content == "" {
+		return nil
+	}
+
+	_, err = fmt.Fprintln(sr.Writer, content)
+
+	return err
+}
+
+func (sr *ScriptRenderer) RenderStatus(os KvProvider) error {
+	return RenderByFormat(
+		sr.Format,
+		sr.Writer,
+		os,
+		func() error {
+			RenderKeyValues(sr.Writer, os)
+			return nil
+		},
+	)
+}
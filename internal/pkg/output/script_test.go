@@ -0,0 +1,96 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderScripts(t *testing.T) {
+	scripts := []*models.Script{
+		{
+			ID:          "123",
+			Name:        "uptime",
+			Interpreter: "/bin/sh",
+			IsSudo:      true,
+			Tags:        []string{"linux", "monitoring"},
+		},
+	}
+
+	testCases := []struct {
+		Format         string
+		ExpectedOutput string
+	}{
+		{
+			Format: FormatHuman,
+			ExpectedOutput: `Scripts
+ID  NAME   INTERPRETER SUDO CWD TAGS              UPDATED AT 
+123 uptime /bin/sh     true     linux, monitoring            
+`,
+		},
+		{
+			Format: FormatJSON,
+			ExpectedOutput: `[{"id":"123","name":"uptime","interpreter":"/bin/sh","is_sudo":true,"cwd":"","tags":["linux","monitoring"],"script":""}]
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			sr := &ScriptRenderer{
+				ColCountCalculator: func() int {
+					return 150
+				},
+				Writer: buf,
+				Format: tc.Format,
+			}
+
+			err := sr.RenderScripts(scripts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.ExpectedOutput, buf.String())
+		})
+	}
+}
+
+func TestRenderScript(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sr := &ScriptRenderer{
+		Writer: buf,
+		Format: FormatHuman,
+	}
+
+	err := sr.RenderScript(&models.Script{
+		ID:          "123",
+		Name:        "uptime",
+		Interpreter: "/bin/sh",
+		Cwd:         "/tmp",
+		Tags:        []string{"linux"},
+		Content:     "#!/bin/sh\nuptime\n",
+		CreatedBy:   "admin",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `Script [123]
+
+KEY          VALUE   
+ID:          123     
+Name:        uptime  
+Interpreter: /bin/sh 
+Is sudo:     false   
+Cwd:         /tmp    
+Tags:        linux   
+Created By:  admin   
+Created At:          
+Updated At:          
+
+Content
+#!/bin/sh
+uptime
+`, buf.String())
+}
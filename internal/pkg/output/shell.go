@@ -0,0 +1,51 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+	"github.com/fatih/color"
+)
+
+// ShellJobRenderer renders job results as a terminal would show command outputs, without any decoration
+type ShellJobRenderer struct {
+	Writer io.Writer
+}
+
+func (sjr *ShellJobRenderer) RenderJob(j *models.Job) error {
+	err := sjr.write(j.Result.Stdout, nil)
+	if err != nil {
+		return err
+	}
+
+	err = sjr.write(j.Result.Stderr, color.New(color.FgRed))
+	if err != nil {
+		return err
+	}
+
+	return sjr.write(j.Error, color.New(color.FgRed))
+}
+
+func (sjr *ShellJobRenderer) RenderJobStarted(js *models.JobStarted) error {
+	_, err := fmt.Fprintf(sjr.Writer, "job %s started\n", js.Jid)
+	return err
+}
+
+func (sjr *ShellJobRenderer) write(text string, textColor *color.Color) error {
+	if text == "" {
+		return nil
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	if textColor != nil {
+		_, err := textColor.Fprint(sjr.Writer, text)
+		return err
+	}
+
+	_, err := io.WriteString(sjr.Writer, text)
+	return err
+}
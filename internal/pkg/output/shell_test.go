@@ -0,0 +1,37 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestRenderShellJob(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sjr := &ShellJobRenderer{Writer: buf}
+
+	err := sjr.RenderJob(&models.Job{
+		Result: models.JobResult{
+			Stdout: "file1\nfile2",
+			Stderr: "ls: cannot access 'file3'\n",
+		},
+		Error: "exit status 2",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "file1\nfile2\nls: cannot access 'file3'\nexit status 2\n", buf.String())
+}
+
+func TestRenderShellJobWithoutOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sjr := &ShellJobRenderer{Writer: buf}
+
+	err := sjr.RenderJob(&models.Job{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "", buf.String())
+}
@@ -0,0 +1,275 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	spinnerInterval = 100 * time.Millisecond
+	spinnerSuccess  = "✔"
+	spinnerError    = "✖"
+	// the mark is colored, so its length differs from the width on the screen
+	spinnerMarkWidth = 2
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// TerminalSpinner shows a live table which is redrawn in place on each update, messages consist of a title line
+// followed by lines of tab separated columns. Other output should be written through a Bypass writer, so it's
+// printed above the table instead of being overwritten by it
+type TerminalSpinner struct {
+	Writer             io.Writer
+	ColCountCalculator CalcTerminalColumnsCount
+	mu                 sync.Mutex
+	msg                string
+	frame              int
+	drawnLines         int
+	stop               chan struct{}
+	stopped            chan struct{}
+}
+
+func (ts *TerminalSpinner) Start(msg string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.stop != nil {
+		ts.clear()
+		ts.msg = msg
+		ts.draw(spinnerFrames[ts.frame])
+		return
+	}
+
+	ts.msg = msg
+	ts.frame = 0
+	ts.draw(spinnerFrames[ts.frame])
+
+	ts.stop = make(chan struct{})
+	ts.stopped = make(chan struct{})
+	go ts.animate(ts.stop, ts.stopped)
+}
+
+func (ts *TerminalSpinner) Update(msg string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.clear()
+	ts.msg = msg
+	ts.draw(spinnerFrames[ts.frame])
+}
+
+func (ts *TerminalSpinner) StopSuccess(msg string) {
+	ts.finish(msg, color.GreenString(spinnerSuccess))
+}
+
+func (ts *TerminalSpinner) StopError(msg string) {
+	ts.finish(msg, color.RedString(spinnerError))
+}
+
+// Bypass gives a writer which prints above the table, only complete lines are printed while the spinner runs
+func (ts *TerminalSpinner) Bypass(w io.Writer) io.Writer {
+	return &spinnerBypassWriter{spinner: ts, w: w}
+}
+
+func (ts *TerminalSpinner) animate(stop, stopped chan struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ts.mu.Lock()
+			ts.frame = (ts.frame + 1) % len(spinnerFrames)
+			ts.clear()
+			ts.draw(spinnerFrames[ts.frame])
+			ts.mu.Unlock()
+		}
+	}
+}
+
+func (ts *TerminalSpinner) finish(msg, mark string) {
+	ts.mu.Lock()
+	stop, stopped := ts.stop, ts.stopped
+	ts.stop, ts.stopped = nil, nil
+	ts.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-stopped
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.clear()
+	ts.msg = msg
+	ts.draw(mark)
+	// the final table stays on the screen
+	ts.drawnLines = 0
+}
+
+func (ts *TerminalSpinner) clear() {
+	if ts.drawnLines == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(ts.Writer, "\x1b[%dA\x1b[J", ts.drawnLines)
+	ts.drawnLines = 0
+}
+
+func (ts *TerminalSpinner) draw(mark string) {
+	lines := formatSpinnerMessage(ts.msg)
+	if len(lines) == 0 {
+		return
+	}
+
+	width := 0
+	if ts.ColCountCalculator != nil {
+		width = ts.ColCountCalculator()
+	}
+
+	buf := &bytes.Buffer{}
+	for i, line := range lines {
+		// wrapped lines would break clearing of the table on the next redraw
+		if width > 0 && i == 0 {
+			line = truncateToWidth(line, width-1-spinnerMarkWidth)
+		} else if width > 0 {
+			line = truncateToWidth(line, width-1)
+		}
+		if i == 0 {
+			line = mark + " " + line
+		}
+		buf.WriteString(line + "\n")
+	}
+
+	_, _ = ts.Writer.Write(buf.Bytes())
+	ts.drawnLines = len(lines)
+}
+
+type spinnerBypassWriter struct {
+	spinner *TerminalSpinner
+	w       io.Writer
+	buf     []byte
+}
+
+func (sbw *spinnerBypassWriter) Write(p []byte) (int, error) {
+	sbw.spinner.mu.Lock()
+	defer sbw.spinner.mu.Unlock()
+
+	if sbw.spinner.stop == nil {
+		if len(sbw.buf) > 0 {
+			p = append(sbw.buf, p...)
+			sbw.buf = nil
+		}
+		return sbw.w.Write(p)
+	}
+
+	sbw.buf = append(sbw.buf, p...)
+	lastNewLine := bytes.LastIndexByte(sbw.buf, '\n')
+	if lastNewLine < 0 {
+		return len(p), nil
+	}
+
+	sbw.spinner.clear()
+	_, err := sbw.w.Write(sbw.buf[:lastNewLine+1])
+	sbw.buf = sbw.buf[lastNewLine+1:]
+	sbw.spinner.draw(spinnerFrames[sbw.spinner.frame])
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// LineSpinner prints the title of the first message and then a line for each changed row, e.g. when
+// the output is not a terminal
+type LineSpinner struct {
+	Writer io.Writer
+	rows   map[string]string
+}
+
+func (ls *LineSpinner) Start(msg string) {
+	ls.rows = map[string]string{}
+	lines := strings.Split(msg, "\n")
+	_, _ = fmt.Fprintln(ls.Writer, lines[0])
+	ls.printChangedRows(lines[1:])
+}
+
+func (ls *LineSpinner) Update(msg string) {
+	ls.printChangedRows(strings.Split(msg, "\n")[1:])
+}
+
+func (ls *LineSpinner) StopSuccess(msg string) {
+	ls.stop(msg)
+}
+
+func (ls *LineSpinner) StopError(msg string) {
+	ls.stop(msg)
+}
+
+func (ls *LineSpinner) stop(msg string) {
+	lines := strings.Split(msg, "\n")
+	ls.printChangedRows(lines[1:])
+	_, _ = fmt.Fprintln(ls.Writer, lines[0])
+}
+
+func (ls *LineSpinner) printChangedRows(rows []string) {
+	if ls.rows == nil {
+		ls.rows = map[string]string{}
+	}
+
+	for _, row := range rows {
+		cols := strings.Split(row, "\t")
+		key := cols[0]
+		value := strings.Join(cols[1:], " ")
+		if previous, ok := ls.rows[key]; ok && previous == value {
+			continue
+		}
+		ls.rows[key] = value
+		_, _ = fmt.Fprintf(ls.Writer, "%s: %s\n", key, value)
+	}
+}
+
+// formatSpinnerMessage aligns the tab separated columns of the message rows
+func formatSpinnerMessage(msg string) []string {
+	if msg == "" {
+		return nil
+	}
+
+	lines := strings.Split(msg, "\n")
+	if len(lines) == 1 {
+		return lines
+	}
+
+	buf := &bytes.Buffer{}
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	for _, row := range lines[1:] {
+		_, _ = fmt.Fprintln(tw, "  "+row)
+	}
+	_ = tw.Flush()
+
+	return append(lines[:1], strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")...)
+}
+
+func truncateToWidth(line string, width int) string {
+	runes := []rune(line)
+	if width < 0 {
+		width = 0
+	}
+	if len(runes) <= width {
+		return line
+	}
+
+	return string(runes[:width])
+}
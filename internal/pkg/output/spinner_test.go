@@ -0,0 +1,82 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineSpinner(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ls := &LineSpinner{Writer: buf}
+
+	ls.Start("waiting [0/2]\nsrv1 (cl1)\tpending\nsrv2 (cl2)\tpending")
+	ls.Update("waiting [0/2]\nsrv1 (cl1)\trunning\nsrv2 (cl2)\tpending")
+	ls.Update("waiting [1/2]\nsrv1 (cl1)\tsuccess\nsrv2 (cl2)\tpending")
+	ls.StopError("finished [2/2]\nsrv1 (cl1)\tsuccess\nsrv2 (cl2)\tfailed")
+
+	expectedOutput := `waiting [0/2]
+srv1 (cl1): pending
+srv2 (cl2): pending
+srv1 (cl1): running
+srv1 (cl1): success
+srv2 (cl2): failed
+finished [2/2]
+`
+	assert.Equal(t, expectedOutput, buf.String())
+}
+
+func TestTerminalSpinnerRedrawsTable(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = noColor
+	}()
+
+	buf := &bytes.Buffer{}
+	ts := &TerminalSpinner{
+		Writer: buf,
+		ColCountCalculator: func() int {
+			return 20
+		},
+	}
+
+	ts.Update("waiting [0/1]\nsrv1\tpending")
+	assert.Equal(t, "⠋ waiting [0/1]\n  srv1  pending\n", buf.String())
+
+	buf.Reset()
+	ts.StopSuccess("finished [1/1]\nserver with a long name\tsuccess")
+	assert.Equal(t, "\x1b[2A\x1b[J✔ finished [1/1]\n  server with a lon\n", buf.String())
+
+	buf.Reset()
+	ts.Update("waiting [0/1]\nsrv1\tpending")
+	assert.Equal(t, "⠋ waiting [0/1]\n  srv1  pending\n", buf.String(), "the stopped table should not be cleared")
+}
+
+func TestTerminalSpinnerBypass(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ts := &TerminalSpinner{Writer: buf}
+	w := ts.Bypass(buf)
+
+	ts.Start("waiting [0/1]\nsrv1\tpending")
+
+	_, err := w.Write([]byte("job output\npartial"))
+	require.NoError(t, err)
+
+	output := buf.String()
+	clearIndex := strings.LastIndex(output, "\x1b[2A\x1b[J")
+	require.True(t, clearIndex >= 0)
+	assert.True(t, strings.HasPrefix(output[clearIndex:], "\x1b[2A\x1b[Jjob output\n"))
+	assert.True(t, strings.HasSuffix(output, "srv1  pending\n"))
+	assert.NotContains(t, output, "partial")
+
+	ts.StopError("finished [1/1]\nsrv1\tfailed")
+
+	_, err = w.Write([]byte(" line\n"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(buf.String(), "srv1  failed\npartial line\n"))
+}
@@ -0,0 +1,29 @@
+package output
+
+import (
+	"io"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+type StatusRenderer struct {
+	Writer io.Writer
+	Format string
+}
+
+func (sr *StatusRenderer) RenderStatus(st *models.StatusInfo) error {
+	return RenderByFormat(
+		sr.Format,
+		sr.Writer,
+		st,
+		func() error {
+			err := RenderHeader(sr.Writer, "Status")
+			if err != nil {
+				return err
+			}
+
+			RenderKeyValues(sr.Writer, st)
+			return nil
+		},
+	)
+}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestRenderStatus(t *testing.T) {
+	expiresAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	st := &models.StatusInfo{
+		CLIVersion:     "1.0.0",
+		ServerURL:      "http://localhost:3000",
+		User:           &models.Me{Username: "admin", Groups: []string{"Administrators"}},
+		TokenExpiresAt: &expiresAt,
+		Server: &models.Status{
+			ClientsConnected:    2,
+			ClientsDisconnected: 1,
+			Version:             "0.4.0",
+			Fingerprint:         "fp",
+			ConnectURL:          "http://localhost:8080",
+		},
+	}
+
+	testCases := []struct {
+		Format         string
+		ExpectedOutput string
+	}{
+		{
+			Format: FormatHuman,
+			ExpectedOutput: `Status
+KEY                   VALUE                          
+CLI Version:          1.0.0                          
+Server URL:           http://localhost:3000          
+Server Version:       0.4.0                          
+Fingerprint:          fp                             
+Connect URL:          http://localhost:8080          
+Clients Connected:    2                              
+Clients Disconnected: 1                              
+User:                 admin                          
+Groups:               Administrators                 
+Token Expires At:     2021-01-01T00:00:00Z (expired) 
+`,
+		},
+		{
+			Format: FormatJSON,
+			ExpectedOutput: `{"cli_version":"1.0.0","server_url":"http://localhost:3000","user":{"username":"admin","groups":["Administrators"],"two_fa_send_to":""},"token_expires_at":"2021-01-01T00:00:00Z","server":{"clients_connected":2,"clients_disconnected":1,"version":"0.4.0","fingerprint":"fp","connect_url":"http://localhost:8080"}}
+`,
+		},
+		{
+			Format: FormatCSV,
+			ExpectedOutput: `KEY,VALUE
+CLI Version,1.0.0
+Server URL,http://localhost:3000
+Server Version,0.4.0
+Fingerprint,fp
+Connect URL,http://localhost:8080
+Clients Connected,2
+Clients Disconnected,1
+User,admin
+Groups,Administrators
+Token Expires At,2021-01-01T00:00:00Z (expired)
+`,
+		},
+		{
+			Format:         "jsonpath={.server.version} {.user.username}",
+			ExpectedOutput: "0.4.0 admin",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			sr := &StatusRenderer{
+				Writer: buf,
+				Format: tc.Format,
+			}
+
+			err := sr.RenderStatus(st)
+			require.NoError(t, err)
+			assert.Equal(t, tc.ExpectedOutput, buf.String())
+		})
+	}
+}
+
+func TestRenderStatusWithError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sr := &StatusRenderer{
+		Writer: buf,
+		Format: FormatJSON,
+	}
+
+	err := sr.RenderStatus(&models.StatusInfo{
+		CLIVersion: "1.0.0",
+		ServerURL:  "http://localhost:3000",
+		Error:      "connection refused",
+	})
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`{"cli_version":"1.0.0","server_url":"http://localhost:3000","user":null,"server":null,"error":"connection refused"}`+"\n",
+		buf.String(),
+	)
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/breathbath/go_utils/v2/pkg/testing"
 	"github.com/olekukonko/tablewriter"
 	"github.com/sirupsen/logrus"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
 )
 
 var columnsCountToTerminalWidthMap = []tableWidthColumnsCountMapping{
@@ -55,7 +57,7 @@ func buildTable(rw io.Writer) *tablewriter.Table {
 }
 
 func calcColumnsCount(widthMapping []tableWidthColumnsCountMapping, calc CalcTerminalColumnsCount) int {
-	if len(widthMapping) == 0 {
+	if len(widthMapping) == 0 || calc == nil {
 		return 0
 	}
 
@@ -109,6 +111,41 @@ func RenderTable(rw io.Writer, col ColumnsData, rowProviders []RowData, calc Cal
 	return nil
 }
 
+type fieldsRow struct {
+	fields models.Fields
+	item   interface{}
+}
+
+func (fr *fieldsRow) Row() []string {
+	return fr.fields.Row(fr.item)
+}
+
+// RenderFieldsTable renders the given columns of items or the default ones if no columns are given,
+// explicitly given columns are never hidden because of the terminal width
+func RenderFieldsTable(
+	rw io.Writer,
+	registry *models.FieldRegistry,
+	columns []string,
+	items []interface{},
+	calc CalcTerminalColumnsCount,
+) error {
+	fields, err := registry.Select(columns)
+	if err != nil {
+		return err
+	}
+
+	if len(columns) > 0 {
+		calc = nil
+	}
+
+	rowProviders := make([]RowData, 0, len(items))
+	for _, item := range items {
+		rowProviders = append(rowProviders, &fieldsRow{fields: fields, item: item})
+	}
+
+	return RenderTable(rw, fields, rowProviders, calc)
+}
+
 func RenderHeader(rw io.Writer, header string) error {
 	_, err := rw.Write([]byte(header + "\n"))
 	if err != nil {
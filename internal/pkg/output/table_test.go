@@ -6,6 +6,9 @@ import (
 
 	testing2 "github.com/breathbath/go_utils/v2/pkg/testing"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
 )
 
 type KVProviderStub struct {
@@ -69,3 +72,50 @@ func TestRenderTable(t *testing.T) {
 	actualRenderResult := RemoveEmptySpaces(buf.String())
 	assert.Equal(t, "COL1 COL2 COL3 val1 val2 val3", actualRenderResult)
 }
+
+func TestRenderFieldsTable(t *testing.T) {
+	narrowTerminalCalc := func() int {
+		return 60
+	}
+	clients := []interface{}{
+		&models.Client{ID: "123", Name: "SomeName", Tags: []string{"prod", "web"}, OsKernel: "linux"},
+	}
+
+	testCases := []struct {
+		name           string
+		columns        []string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "default columns are limited by terminal width",
+			expectedOutput: "ID NAME 123 SomeName",
+		},
+		{
+			name:           "selected columns are always shown",
+			columns:        []string{"tags", "os_kernel", "name"},
+			expectedOutput: "TAGS OS KERNEL NAME prod, web linux SomeName",
+		},
+		{
+			name:          "unknown column",
+			columns:       []string{"id", "color"},
+			expectedError: "unknown column 'color', supported columns are id, name, tunnels",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := RenderFieldsTable(buf, models.ClientFields, tc.columns, clients, narrowTerminalCalc)
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, RemoveEmptySpaces(buf.String()))
+		})
+	}
+}
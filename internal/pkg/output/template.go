@@ -0,0 +1,78 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+const (
+	FormatGoTemplatePrefix = "go-template="
+	FormatTemplatePrefix   = "template="
+	FormatJSONPathPrefix   = "jsonpath="
+)
+
+// IsTemplateFormat tells if the format contains a go template or a jsonpath template, e.g. jsonpath={.[*].id}
+func IsTemplateFormat(format string) bool {
+	return strings.HasPrefix(format, FormatGoTemplatePrefix) ||
+		strings.HasPrefix(format, FormatTemplatePrefix) ||
+		strings.HasPrefix(format, FormatJSONPathPrefix)
+}
+
+// ValidateTemplateFormat checks if the template given in the format can be parsed
+func ValidateTemplateFormat(format string) error {
+	_, err := parseTemplateFormat(format)
+	return err
+}
+
+// RenderTemplateFormat executes the template given in the format against the source data
+func RenderTemplateFormat(w io.Writer, format string, source interface{}) error {
+	renderFunc, err := parseTemplateFormat(format)
+	if err != nil {
+		return err
+	}
+
+	return renderFunc(w, source)
+}
+
+type templateRenderFunc func(w io.Writer, source interface{}) error
+
+func parseTemplateFormat(format string) (templateRenderFunc, error) {
+	if strings.HasPrefix(format, FormatJSONPathPrefix) {
+		tmpl := strings.TrimPrefix(format, FormatJSONPathPrefix)
+		if tmpl == "" {
+			return nil, fmt.Errorf("empty template given in format '%s'", format)
+		}
+
+		renderFunc, err := parseJSONPath(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jsonpath template: %v", err)
+		}
+
+		return renderFunc, nil
+	}
+
+	tmpl := strings.TrimPrefix(format, FormatGoTemplatePrefix)
+	if tmpl == format {
+		tmpl = strings.TrimPrefix(format, FormatTemplatePrefix)
+	}
+	if tmpl == "" {
+		return nil, fmt.Errorf("empty template given in format '%s'", format)
+	}
+
+	goTemplate, err := template.New("output").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go template: %v", err)
+	}
+
+	// go templates get the same json data as jsonpath templates, so both refer to the json field names, e.g. {{.id}}
+	return func(w io.Writer, source interface{}) error {
+		data, err := toJSONData(source)
+		if err != nil {
+			return err
+		}
+
+		return goTemplate.Execute(w, data)
+	}, nil
+}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
+)
+
+func TestRenderTemplateFormats(t *testing.T) {
+	clients := []*models.Client{
+		{ID: "123", Name: "SomeName", Tags: []string{"prod", "web"}},
+		{ID: "124", Name: "SomeOtherName"},
+	}
+
+	testCases := []struct {
+		format         string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			format:         `go-template={{range .}}{{.id}} {{.name}}{{"\n"}}{{end}}`,
+			expectedOutput: "123 SomeName\n124 SomeOtherName\n",
+		},
+		{
+			format:         `go-template={{index (index . 0).tags 1}}`,
+			expectedOutput: "web",
+		},
+		{
+			format:         `template={{len .}}`,
+			expectedOutput: "2",
+		},
+		{
+			format:         `jsonpath={.[*].id}`,
+			expectedOutput: "123 124",
+		},
+		{
+			format:        `go-template={{range .}}`,
+			expectedError: "invalid go template",
+		},
+		{
+			format:        `jsonpath=`,
+			expectedError: "empty template given in format 'jsonpath='",
+		},
+		{
+			format:        `go-template={{range .}}{{.Unknown}}{{end}}`,
+			expectedError: `map has no entry for key "Unknown"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			cr := &ClientRenderer{
+				Writer: buf,
+				Format: tc.format,
+			}
+
+			err := cr.RenderClients(clients, nil)
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, buf.String())
+		})
+	}
+}
+
+func TestValidateTemplateFormat(t *testing.T) {
+	assert.True(t, IsTemplateFormat("jsonpath={.id}"))
+	assert.True(t, IsTemplateFormat("go-template={{.id}}"))
+	assert.False(t, IsTemplateFormat(FormatJSON))
+
+	assert.NoError(t, ValidateTemplateFormat("jsonpath={.id}"))
+	assert.EqualError(t, ValidateTemplateFormat("jsonpath={.id"), "invalid jsonpath template: unclosed action")
+}
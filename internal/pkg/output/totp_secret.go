@@ -1,6 +1,7 @@
 package output
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/cloudradar-monitoring/rportcli/internal/pkg/models"
@@ -46,5 +47,12 @@ func (cr *TotPSecretRenderer) renderTotPSecretToHumanFormat(key *models.TotPSecr
 		return err
 	}
 
+	if key.QR != "" {
+		_, err = fmt.Fprintf(cr.Writer, "\n%s", key.QR)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
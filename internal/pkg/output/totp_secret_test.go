@@ -82,3 +82,30 @@ file: somefile.png
 		})
 	}
 }
+
+func TestRenderTotPSecretWithQr(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tr := &TotPSecretRenderer{
+		ColCountCalculator: func() int {
+			return 150
+		},
+		Writer: buf,
+		Format: FormatHuman,
+	}
+
+	err := tr.RenderTotPSecret(&models.TotPSecretOutput{
+		Secret:  "secret123",
+		Comment: "comment123",
+		QR:      "█▀█\n",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, `One time password secret
+
+comment123
+SECRET    QR FILE 
+secret123         
+
+█▀█
+`, buf.String())
+}
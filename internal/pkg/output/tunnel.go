@@ -10,9 +10,14 @@ type TunnelRenderer struct {
 	ColCountCalculator CalcTerminalColumnsCount
 	Writer             io.Writer
 	Format             string
+	Columns            []string
 }
 
 func (tr *TunnelRenderer) RenderTunnels(tunnels []*models.Tunnel) error {
+	if IsDelimitedFormat(tr.Format) {
+		return RenderFieldsDelimited(tr.Writer, tr.Format, models.TunnelFields, tr.Columns, tunnelsToItems(tunnels))
+	}
+
 	return RenderByFormat(
 		tr.Format,
 		tr.Writer,
@@ -33,12 +38,16 @@ func (tr *TunnelRenderer) renderTunnelsInHumanFormat(tunnels []*models.Tunnel) e
 		return err
 	}
 
-	rowProviders := make([]RowData, 0, len(tunnels))
+	return RenderFieldsTable(tr.Writer, models.TunnelFields, tr.Columns, tunnelsToItems(tunnels), tr.ColCountCalculator)
+}
+
+func tunnelsToItems(tunnels []*models.Tunnel) []interface{} {
+	items := make([]interface{}, 0, len(tunnels))
 	for _, t := range tunnels {
-		rowProviders = append(rowProviders, t)
+		items = append(items, t)
 	}
 
-	return RenderTable(tr.Writer, &models.Tunnel{}, rowProviders, tr.ColCountCalculator)
+	return items
 }
 
 func (tr *TunnelRenderer) RenderTunnel(t KvProvider) error {
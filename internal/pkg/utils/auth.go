@@ -1,12 +1,18 @@
 package utils
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/auth"
 
 	http2 "github.com/breathbath/go_utils/v2/pkg/http"
 )
@@ -34,28 +40,143 @@ func (sba *StorageBasicAuth) AuthRequest(req *http.Request) error {
 	return nil
 }
 
+// TokenExpiryWarningPeriod is the period before the token expiry when the token is refreshed or a warning is shown
+const TokenExpiryWarningPeriod = 24 * time.Hour
+
+// RefreshableAuth can renew its credentials after they were rejected by the server
+type RefreshableAuth interface {
+	Auth
+	RefreshAuth(ctx context.Context) (isRefreshed bool, err error)
+}
+
+// TokenRefresher gets a new auth token, e.g. by logging in with credentials from env
+type TokenRefresher func(ctx context.Context) (token string, err error)
+
 type BearerAuth struct {
 	TokenProvider func() (string, error)
+	// TokenRefresher is optional, if it's given, expiring or rejected tokens are replaced with refreshed ones
+	TokenRefresher TokenRefresher
+	// mu guards the refreshed token, as requests can be authorized concurrently, e.g. by the reconnecting shell
+	mu              sync.Mutex
+	refreshedToken  string
+	isExpiryChecked bool
 }
 
-func (ba *BearerAuth) AuthRequest(req *http.Request) error {
+// Token gives the current auth token, it is refreshed if it expires soon and a token refresher is given,
+// the refresher also gets a token if none is stored, e.g. for profiles initialized with an api token
+func (ba *BearerAuth) Token(ctx context.Context) (string, error) {
+	ba.mu.Lock()
+	defer ba.mu.Unlock()
+
+	if ba.refreshedToken != "" {
+		return ba.refreshedToken, nil
+	}
+
 	token, err := ba.TokenProvider()
+	if err == nil && token == "" && ba.TokenRefresher != nil {
+		err = ba.refresh(ctx)
+		return ba.refreshedToken, err
+	}
+	if err != nil || token == "" || ba.isExpiryChecked {
+		return token, err
+	}
+	ba.isExpiryChecked = true
+
+	expiresIn, ok := tokenExpiresIn(token)
+	if !ok || expiresIn > TokenExpiryWarningPeriod {
+		return token, nil
+	}
+
+	if ba.TokenRefresher != nil {
+		err = ba.refresh(ctx)
+		if err == nil {
+			return ba.refreshedToken, nil
+		}
+		logrus.Warnf("failed to refresh the auth token: %v", err)
+	}
+
+	if expiresIn <= 0 {
+		logrus.Warn("the auth token has expired, please run 'rportcli init' to login again")
+	} else {
+		logrus.Warnf("the auth token expires in %s, please run 'rportcli init' to login again", expiresIn.Round(time.Minute))
+	}
+
+	return token, nil
+}
+
+func (ba *BearerAuth) AuthRequest(req *http.Request) error {
+	token, err := ba.Token(req.Context())
 	if err != nil {
 		return err
 	}
+	if token == "" {
+		return fmt.Errorf("no auth token provided")
+	}
 
 	req.Header.Add("Authorization", "Bearer "+token)
 
 	return nil
 }
 
+// RefreshAuth replaces the token rejected by the server if a token refresher is given
+func (ba *BearerAuth) RefreshAuth(ctx context.Context) (isRefreshed bool, err error) {
+	if ba.TokenRefresher == nil {
+		return false, nil
+	}
+
+	ba.mu.Lock()
+	defer ba.mu.Unlock()
+
+	err = ba.refresh(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (ba *BearerAuth) refresh(ctx context.Context) error {
+	token, err := ba.TokenRefresher(ctx)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return fmt.Errorf("no auth token received")
+	}
+
+	logrus.Debug("the auth token was refreshed")
+	ba.refreshedToken = token
+
+	return nil
+}
+
+// tokenExpiresIn gives the duration till the expiry of a jwt token, false is returned if the token has no expiry
+func tokenExpiresIn(token string) (time.Duration, bool) {
+	claims, err := auth.ParseUnverifiedClaims(token)
+	if err != nil {
+		logrus.Debugf("failed to read claims of the auth token: %v", err)
+		return 0, false
+	}
+
+	if claims.ExpiresAt == 0 {
+		return 0, false
+	}
+
+	return time.Until(time.Unix(claims.ExpiresAt, 0)), true
+}
+
 type FallbackAuth struct {
-	PrimaryAuth  Auth
-	FallbackAuth Auth
+	PrimaryAuth    Auth
+	FallbackAuth   Auth
+	mu             sync.Mutex
+	isFallbackUsed bool
 }
 
 func (fa *FallbackAuth) AuthRequest(req *http.Request) error {
 	err := fa.PrimaryAuth.AuthRequest(req)
+	fa.mu.Lock()
+	fa.isFallbackUsed = err != nil
+	fa.mu.Unlock()
 	if err == nil {
 		return nil
 	}
@@ -63,6 +184,23 @@ func (fa *FallbackAuth) AuthRequest(req *http.Request) error {
 	return fa.FallbackAuth.AuthRequest(req)
 }
 
+// RefreshAuth refreshes the auth which was used for the last request if it supports refreshing
+func (fa *FallbackAuth) RefreshAuth(ctx context.Context) (isRefreshed bool, err error) {
+	fa.mu.Lock()
+	usedAuth := fa.PrimaryAuth
+	if fa.isFallbackUsed {
+		usedAuth = fa.FallbackAuth
+	}
+	fa.mu.Unlock()
+
+	refreshableAuth, ok := usedAuth.(RefreshableAuth)
+	if !ok {
+		return false, nil
+	}
+
+	return refreshableAuth.RefreshAuth(ctx)
+}
+
 func ExtractBasicAuthLoginAndPassFromRequest(r *http.Request) (login, pass string, err error) {
 	basicAuthHeader := r.Header.Get("Authorization")
 	loginPassBase64 := strings.TrimPrefix(basicAuthHeader, "Basic ")
@@ -4,9 +4,17 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/applog"
+	"github.com/cloudradar-monitoring/rportcli/internal/pkg/auth"
 )
 
 type AuthMock struct {
@@ -125,3 +133,214 @@ func TestFallbackAuthPrimarySuccess(t *testing.T) {
 	assert.Equal(t, "/other", primaryAuth.req[0].URL.String())
 	assert.Equal(t, http.MethodPost, primaryAuth.req[0].Method)
 }
+
+func buildTestToken(t *testing.T, expiresAt time.Time) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		Username: "admin",
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}).SignedString([]byte("some secret"))
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestBearerAuthTokenRefresh(t *testing.T) {
+	validToken := buildTestToken(t, time.Now().Add(TokenExpiryWarningPeriod*2))
+	expiringToken := buildTestToken(t, time.Now().Add(time.Hour))
+	expiredToken := buildTestToken(t, time.Now().Add(-time.Hour))
+
+	testCases := []struct {
+		name             string
+		token            string
+		refresherErr     error
+		withoutRefresher bool
+		expectedToken    string
+		expectedRefresh  bool
+		expectedWarning  string
+	}{
+		{
+			name:          "valid token",
+			token:         validToken,
+			expectedToken: validToken,
+		},
+		{
+			name:          "token without expiry",
+			token:         "some token",
+			expectedToken: "some token",
+		},
+		{
+			name:            "expiring token",
+			token:           expiringToken,
+			expectedToken:   "refreshed token",
+			expectedRefresh: true,
+		},
+		{
+			name:            "expired token",
+			token:           expiredToken,
+			expectedToken:   "refreshed token",
+			expectedRefresh: true,
+		},
+		{
+			name:             "expiring token without refresher",
+			token:            expiringToken,
+			withoutRefresher: true,
+			expectedToken:    expiringToken,
+			expectedWarning:  "the auth token expires in 1h0m0s, please run 'rportcli init' to login again",
+		},
+		{
+			name:             "expired token without refresher",
+			token:            expiredToken,
+			withoutRefresher: true,
+			expectedToken:    expiredToken,
+			expectedWarning:  "the auth token has expired, please run 'rportcli init' to login again",
+		},
+		{
+			name:            "failed refresh",
+			token:           expiredToken,
+			refresherErr:    errors.New("invalid credentials"),
+			expectedToken:   expiredToken,
+			expectedRefresh: true,
+			expectedWarning: "failed to refresh the auth token: invalid credentials",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			logs := &applog.BufferedLogs{}
+			logrus.AddHook(logs)
+			defer logrus.StandardLogger().ReplaceHooks(logrus.LevelHooks{})
+
+			isRefreshed := false
+			ba := &BearerAuth{
+				TokenProvider: func() (string, error) {
+					return tc.token, nil
+				},
+			}
+			if !tc.withoutRefresher {
+				ba.TokenRefresher = func(ctx context.Context) (string, error) {
+					isRefreshed = true
+					if tc.refresherErr != nil {
+						return "", tc.refresherErr
+					}
+					return "refreshed token", nil
+				}
+			}
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+			require.NoError(t, err)
+
+			err = ba.AuthRequest(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, "Bearer "+tc.expectedToken, req.Header.Get("Authorization"))
+			assert.Equal(t, tc.expectedRefresh, isRefreshed)
+			if tc.expectedWarning != "" {
+				assert.Contains(t, logs.Messages, tc.expectedWarning)
+			}
+		})
+	}
+}
+
+func TestBearerAuthWithoutToken(t *testing.T) {
+	ba := &BearerAuth{
+		TokenProvider: func() (string, error) {
+			return "", nil
+		},
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	err = ba.AuthRequest(req)
+	assert.EqualError(t, err, "no auth token provided")
+}
+
+func TestFallbackAuthRefresh(t *testing.T) {
+	bearerAuth := &BearerAuth{
+		TokenProvider: func() (string, error) {
+			return "some token", nil
+		},
+		TokenRefresher: func(ctx context.Context) (string, error) {
+			return "refreshed token", nil
+		},
+	}
+	fa := &FallbackAuth{
+		PrimaryAuth:  bearerAuth,
+		FallbackAuth: &AuthMock{},
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	err = fa.AuthRequest(req)
+	require.NoError(t, err)
+
+	isRefreshed, err := fa.RefreshAuth(context.Background())
+	require.NoError(t, err)
+	assert.True(t, isRefreshed)
+
+	token, err := bearerAuth.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "refreshed token", token)
+
+	fa.PrimaryAuth = &AuthMock{errToGive: errors.New("no token")}
+	err = fa.AuthRequest(req)
+	require.NoError(t, err)
+
+	isRefreshed, err = fa.RefreshAuth(context.Background())
+	require.NoError(t, err)
+	assert.False(t, isRefreshed)
+}
+
+func TestBearerAuthConcurrentRefresh(t *testing.T) {
+	ba := &BearerAuth{
+		TokenProvider: func() (string, error) {
+			return "some token", nil
+		},
+		TokenRefresher: func(ctx context.Context) (string, error) {
+			return "refreshed token", nil
+		},
+	}
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := ba.Token(context.Background())
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := ba.RefreshAuth(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	token, err := ba.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "refreshed token", token)
+}
+
+func TestBearerAuthWithoutStoredToken(t *testing.T) {
+	refreshCount := 0
+	ba := &BearerAuth{
+		TokenProvider: func() (string, error) {
+			return "", nil
+		},
+		TokenRefresher: func(ctx context.Context) (string, error) {
+			refreshCount++
+			return "session token", nil
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		token, err := ba.Token(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "session token", token)
+	}
+	assert.Equal(t, 1, refreshCount)
+}
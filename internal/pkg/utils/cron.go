@@ -0,0 +1,227 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears limits the search of the next run, so expressions like '0 0 30 2 *' which never match are detected,
+// 9 years cover the longest gap between two leap years
+const cronSearchYears = 9
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	// 7 is accepted as sunday as well
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// CronSchedule is a parsed cron expression with the standard 5 fields: minute, hour, day of month, month and day of week
+type CronSchedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	// as in the classic cron, if both day fields are restricted, a day matches if any of them matches
+	anyDayMatches bool
+}
+
+// ParseCron parses expressions like '*/15 8-18 * * mon-fri' or macros like '@daily'
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macroExpr, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macroExpr
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("expected %d fields (minute, hour, day of month, month, day of week), got %d", len(cronFields), len(fields))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		fieldBits, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = fieldBits
+	}
+
+	// sunday can be given as 0 or 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	cs := &CronSchedule{
+		minutes:       bits[0],
+		hours:         bits[1],
+		daysOfMonth:   bits[2],
+		months:        bits[3],
+		daysOfWeek:    bits[4],
+		anyDayMatches: !strings.HasPrefix(fields[2], "*") && !strings.HasPrefix(fields[4], "*"),
+	}
+
+	if cs.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("expression '%s' never matches", expr)
+	}
+
+	return cs, nil
+}
+
+func parseCronField(field string, def cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			rangeExpr = item[:i]
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step '%s' in %s field", item[i+1:], def.name)
+			}
+		}
+
+		from, to, err := parseCronRange(rangeExpr, def)
+		if err != nil {
+			return 0, err
+		}
+		// a single value with a step means a range till the max value, e.g. 5/10 is 5-59/10
+		if step > 1 && !strings.ContainsAny(rangeExpr, "*-") {
+			to = def.max
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronRange(rangeExpr string, def cronField) (from, to int, err error) {
+	if rangeExpr == "*" {
+		return def.min, def.max, nil
+	}
+
+	bounds := strings.SplitN(rangeExpr, "-", 2)
+	from, err = parseCronValue(bounds[0], def)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	to = from
+	if len(bounds) == 2 {
+		to, err = parseCronValue(bounds[1], def)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if from > to {
+		return 0, 0, fmt.Errorf("invalid range '%s' in %s field", rangeExpr, def.name)
+	}
+
+	return from, to, nil
+}
+
+func parseCronValue(val string, def cronField) (int, error) {
+	if v, ok := def.names[strings.ToLower(val)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s' in %s field", val, def.name)
+	}
+
+	if v < def.min || v > def.max {
+		return 0, fmt.Errorf("value %d is out of range %d-%d in %s field", v, def.min, def.max, def.name)
+	}
+
+	return v, nil
+}
+
+// Next gives the first time after the given one matching the schedule in the location of the given time,
+// zero time is returned if there is no such time
+func (cs *CronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + cronSearchYears
+
+	for t.Year() <= yearLimit {
+		if cs.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !cs.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if cs.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if cs.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// NextRuns gives the next count times matching the schedule
+func (cs *CronSchedule) NextRuns(after time.Time, count int) []time.Time {
+	runs := make([]time.Time, 0, count)
+	for len(runs) < count {
+		after = cs.Next(after)
+		if after.IsZero() {
+			break
+		}
+		runs = append(runs, after)
+	}
+
+	return runs
+}
+
+func (cs *CronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonthMatches := cs.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeekMatches := cs.daysOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if cs.anyDayMatches {
+		return dayOfMonthMatches || dayOfWeekMatches
+	}
+
+	return dayOfMonthMatches && dayOfWeekMatches
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronNextRuns(t *testing.T) {
+	// a wednesday
+	after := time.Date(2021, 3, 3, 10, 7, 30, 0, time.UTC)
+
+	testCases := []struct {
+		expr         string
+		expectedRuns []string
+	}{
+		{
+			expr: "*/15 * * * *",
+			expectedRuns: []string{
+				"2021-03-03T10:15:00Z",
+				"2021-03-03T10:30:00Z",
+				"2021-03-03T10:45:00Z",
+			},
+		},
+		{
+			expr: "0 8-18/5 * * mon-fri",
+			expectedRuns: []string{
+				"2021-03-03T13:00:00Z",
+				"2021-03-03T18:00:00Z",
+				"2021-03-04T08:00:00Z",
+			},
+		},
+		{
+			expr: "30 2 * * 6,7",
+			expectedRuns: []string{
+				"2021-03-06T02:30:00Z",
+				"2021-03-07T02:30:00Z",
+				"2021-03-13T02:30:00Z",
+			},
+		},
+		{
+			expr: "0 0 1,15 * 1",
+			expectedRuns: []string{
+				"2021-03-08T00:00:00Z",
+				"2021-03-15T00:00:00Z",
+				"2021-03-22T00:00:00Z",
+			},
+		},
+		{
+			expr: "0 0 29 feb *",
+			expectedRuns: []string{
+				"2024-02-29T00:00:00Z",
+				"2028-02-29T00:00:00Z",
+				"2032-02-29T00:00:00Z",
+			},
+		},
+		{
+			expr: "@monthly",
+			expectedRuns: []string{
+				"2021-04-01T00:00:00Z",
+				"2021-05-01T00:00:00Z",
+				"2021-06-01T00:00:00Z",
+			},
+		},
+		{
+			expr: "5/20 10 * * *",
+			expectedRuns: []string{
+				"2021-03-03T10:25:00Z",
+				"2021-03-03T10:45:00Z",
+				"2021-03-04T10:05:00Z",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.expr, func(t *testing.T) {
+			cs, err := ParseCron(tc.expr)
+			require.NoError(t, err)
+
+			runs := cs.NextRuns(after, len(tc.expectedRuns))
+			actualRuns := make([]string, 0, len(runs))
+			for _, run := range runs {
+				actualRuns = append(actualRuns, run.Format(time.RFC3339))
+			}
+			assert.Equal(t, tc.expectedRuns, actualRuns)
+		})
+	}
+}
+
+func TestParseInvalidCron(t *testing.T) {
+	testCases := []struct {
+		expr          string
+		expectedError string
+	}{
+		{
+			expr:          "* * * *",
+			expectedError: "expected 5 fields (minute, hour, day of month, month, day of week), got 4",
+		},
+		{
+			expr:          "60 * * * *",
+			expectedError: "value 60 is out of range 0-59 in minute field",
+		},
+		{
+			expr:          "* 18-8 * * *",
+			expectedError: "invalid range '18-8' in hour field",
+		},
+		{
+			expr:          "*/0 * * * *",
+			expectedError: "invalid step '0' in minute field",
+		},
+		{
+			expr:          "* * * foo *",
+			expectedError: "invalid value 'foo' in month field",
+		},
+		{
+			expr:          "0 0 31 4 *",
+			expectedError: "expression '0 0 31 4 *' never matches",
+		},
+		{
+			expr:          "@every 5m",
+			expectedError: "expected 5 fields (minute, hour, day of month, month, day of week), got 2",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.expr, func(t *testing.T) {
+			_, err := ParseCron(tc.expr)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
@@ -56,6 +56,13 @@ func (c *BaseClient) Call(req *http.Request, target interface{}, errTarget error
 	if err != nil {
 		return resp, fmt.Errorf("operation failed with an error: %v", err)
 	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp, err = c.retryWithRefreshedAuth(cl, req, resp)
+		if err != nil {
+			return resp, err
+		}
+	}
 	var respBodyBytes []byte
 	if resp.StatusCode > maxValidResponseCode {
 		respBodyBytes, err = ioutil.ReadAll(resp.Body)
@@ -96,6 +103,55 @@ func (c *BaseClient) Call(req *http.Request, target interface{}, errTarget error
 	return resp, nil
 }
 
+// retryWithRefreshedAuth repeats the request once if the auth could be refreshed after the server rejected it
+func (c *BaseClient) retryWithRefreshedAuth(cl *http.Client, req *http.Request, resp *http.Response) (*http.Response, error) {
+	refreshableAuth, ok := c.auth.(RefreshableAuth)
+	if !ok {
+		return resp, nil
+	}
+
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	if hasBody && req.GetBody == nil {
+		logrus.Debug("cannot repeat the request since its body cannot be read twice")
+		return resp, nil
+	}
+
+	isRefreshed, err := refreshableAuth.RefreshAuth(req.Context())
+	if err != nil {
+		return resp, fmt.Errorf("the server rejected the auth token and it could not be refreshed: %v", err)
+	}
+	if !isRefreshed {
+		return resp, nil
+	}
+
+	retryReq := req.Clone(req.Context())
+	if hasBody {
+		retryReq.Body, err = req.GetBody()
+		if err != nil {
+			return resp, err
+		}
+	}
+	retryReq.Header.Del("Authorization")
+
+	err = c.auth.AuthRequest(retryReq)
+	if err != nil {
+		return resp, err
+	}
+
+	closeErr := resp.Body.Close()
+	if closeErr != nil {
+		logrus.Warn(closeErr)
+	}
+
+	logrus.Debug("repeating the request with the refreshed auth")
+	retryResp, err := cl.Do(retryReq)
+	if err != nil {
+		return retryResp, fmt.Errorf("operation failed with an error: %v", err)
+	}
+
+	return retryResp, nil
+}
+
 func (c *BaseClient) convertResponseCodeToError(respCode int, errTarget error) (err error) {
 	if respCode == http.StatusNotFound {
 		err = errors.New("the specified item doesn't exist")
@@ -2,11 +2,14 @@ package utils
 
 import (
 	"context"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type ErrorMock struct {
@@ -100,3 +103,71 @@ func TestBaseClient(t *testing.T) {
 		assert.Equal(t, testCase.expectedTarget, testCase.target)
 	}
 }
+
+func TestBaseClientRetriesWithRefreshedToken(t *testing.T) {
+	receivedBodies := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, e := ioutil.ReadAll(r.Body)
+		assert.NoError(t, e)
+		receivedBodies = append(receivedBodies, string(body))
+
+		if r.Header.Get("Authorization") != "Bearer refreshed token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			_, e = rw.Write([]byte(`{"error":"unauthorized"}`))
+			assert.NoError(t, e)
+			return
+		}
+
+		_, e = rw.Write([]byte(`{"color":"red"}`))
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	refreshCount := 0
+	bc := &BaseClient{}
+	bc.WithAuth(&BearerAuth{
+		TokenProvider: func() (string, error) {
+			return "rejected token", nil
+		},
+		TokenRefresher: func(ctx context.Context) (string, error) {
+			refreshCount++
+			return "refreshed token", nil
+		},
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, strings.NewReader("some body"))
+	require.NoError(t, err)
+
+	target := &SomeModel{}
+	_, err = bc.Call(req, target, &ErrorMock{})
+	require.NoError(t, err)
+
+	assert.Equal(t, &SomeModel{Color: "red"}, target)
+	assert.Equal(t, 1, refreshCount)
+	assert.Equal(t, []string{"some body", "some body"}, receivedBodies)
+}
+
+func TestBaseClientWithRejectedTokenAndWithoutRefresher(t *testing.T) {
+	requestsCount := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requestsCount++
+		rw.WriteHeader(http.StatusUnauthorized)
+		_, e := rw.Write([]byte(`{"error":"unauthorized"}`))
+		assert.NoError(t, e)
+	}))
+	defer srv.Close()
+
+	bc := &BaseClient{}
+	bc.WithAuth(&BearerAuth{
+		TokenProvider: func() (string, error) {
+			return "rejected token", nil
+		},
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = bc.Call(req, &SomeModel{}, &ErrorMock{})
+	assert.EqualError(t, err, "unauthorized")
+	assert.Equal(t, 1, requestsCount)
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+const (
+	keyCtrlC = 3
+	keyCtrlE = 5
+	keyCtrlU = 21
+	keyEnter = '\r'
+)
+
+// ErrLineInterrupted is given when the user cancels the input of a line with Ctrl-C
+var ErrLineInterrupted = errors.New("line input interrupted")
+
+// TerminalLineReader reads lines with editing and history of the entered lines like in a shell
+type TerminalLineReader struct {
+	fd       int
+	input    *interruptReader
+	terminal *term.Terminal
+}
+
+func NewTerminalLineReader(in *os.File, out io.Writer) *TerminalLineReader {
+	input := &interruptReader{Reader: in}
+
+	return &TerminalLineReader{
+		fd:    int(in.Fd()),
+		input: input,
+		terminal: term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{input, out}, ""),
+	}
+}
+
+// ReadLine switches the terminal to raw mode only while the line is entered,
+// so the output of commands and Ctrl-C signals work as usual in between
+func (tlr *TerminalLineReader) ReadLine(prompt string) (string, error) {
+	oldState, err := term.MakeRaw(tlr.fd)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = term.Restore(tlr.fd, oldState)
+	}()
+
+	if width, height, err := term.GetSize(tlr.fd); err == nil {
+		_ = tlr.terminal.SetSize(width, height)
+	}
+
+	tlr.terminal.SetPrompt(prompt)
+	line, err := tlr.terminal.ReadLine()
+	if tlr.input.isInterrupted {
+		tlr.input.isInterrupted = false
+		return "", ErrLineInterrupted
+	}
+
+	return line, err
+}
+
+// interruptReader replaces Ctrl-C with keys clearing the line since the terminal stops reading on Ctrl-C
+// without clearing the entered text
+type interruptReader struct {
+	io.Reader
+	pending       []byte
+	isInterrupted bool
+}
+
+func (ir *interruptReader) Read(p []byte) (int, error) {
+	if len(ir.pending) == 0 {
+		buf := make([]byte, len(p))
+		n, err := ir.Reader.Read(buf)
+		for _, b := range buf[:n] {
+			if b == keyCtrlC {
+				ir.isInterrupted = true
+				ir.pending = append(ir.pending, keyCtrlE, keyCtrlU, keyEnter)
+				continue
+			}
+			ir.pending = append(ir.pending, b)
+		}
+		if len(ir.pending) == 0 {
+			return 0, err
+		}
+	}
+
+	n := copy(p, ir.pending)
+	ir.pending = ir.pending[n:]
+
+	return n, nil
+}
+
+// ScannerLineReader reads lines without prompts e.g. when commands are piped to stdin
+type ScannerLineReader struct {
+	Sc Scanner
+}
+
+func (slr *ScannerLineReader) ReadLine(prompt string) (string, error) {
+	if slr.Sc.Scan() {
+		return slr.Sc.Text(), nil
+	}
+
+	err := slr.Sc.Err()
+	if err != nil {
+		return "", err
+	}
+
+	return "", io.EOF
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/term"
+)
+
+func TestInterruptReaderClearsLine(t *testing.T) {
+	input := &interruptReader{Reader: strings.NewReader("rm -rf\x03ls\r")}
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{input, &bytes.Buffer{}}, "")
+
+	line, err := terminal.ReadLine()
+	require.NoError(t, err)
+	assert.Equal(t, "", line)
+	assert.True(t, input.isInterrupted)
+
+	input.isInterrupted = false
+	line, err = terminal.ReadLine()
+	require.NoError(t, err)
+	assert.Equal(t, "ls", line)
+	assert.False(t, input.isInterrupted)
+}
+
+func TestScannerLineReader(t *testing.T) {
+	lr := &ScannerLineReader{Sc: bufio.NewScanner(strings.NewReader("pwd\nls -la\n"))}
+
+	line, err := lr.ReadLine("$ ")
+	require.NoError(t, err)
+	assert.Equal(t, "pwd", line)
+
+	line, err = lr.ReadLine("$ ")
+	require.NoError(t, err)
+	assert.Equal(t, "ls -la", line)
+
+	_, err = lr.ReadLine("$ ")
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"runtime"
+)
+
+// RunLocalCommand executes the command with the shell of the local machine
+func RunLocalCommand(ctx context.Context, command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	return cmd.Run()
+}
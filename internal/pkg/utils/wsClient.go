@@ -2,7 +2,9 @@ package utils
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"net/http"
 
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
@@ -20,13 +22,20 @@ type WsClient struct {
 	Conn         *websocket.Conn
 }
 
-func NewWsClient(ctx context.Context, wsURLBuilder WsURLBuilder) (wsc *WsClient, err error) {
-	wsURL, err := wsURLBuilder(ctx)
-	if err != nil {
-		return nil, err
+// NewWsClient connects to the websocket, if the server rejects the auth and authRefresher is given,
+// the auth is refreshed and the connection is opened once more
+func NewWsClient(ctx context.Context, wsURLBuilder WsURLBuilder, authRefresher RefreshableAuth) (wsc *WsClient, err error) {
+	conn, resp, err := dialWs(ctx, wsURLBuilder)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusUnauthorized && authRefresher != nil {
+		isRefreshed, refreshErr := authRefresher.RefreshAuth(ctx)
+		if refreshErr != nil {
+			return nil, fmt.Errorf("the server rejected the auth token and it could not be refreshed: %v", refreshErr)
+		}
+		if isRefreshed {
+			logrus.Debug("opening the websocket with the refreshed auth")
+			conn, _, err = dialWs(ctx, wsURLBuilder)
+		}
 	}
-
-	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -37,6 +46,15 @@ func NewWsClient(ctx context.Context, wsURLBuilder WsURLBuilder) (wsc *WsClient,
 	}, nil
 }
 
+func dialWs(ctx context.Context, wsURLBuilder WsURLBuilder) (*websocket.Conn, *http.Response, error) {
+	wsURL, err := wsURLBuilder(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return websocket.DefaultDialer.Dial(wsURL, nil)
+}
+
 func (wc *WsClient) Close() error {
 	if wc.Conn != nil {
 		logrus.Debugf("closing connection to  the rportd server: %s", wc.Conn.RemoteAddr().String())
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"errors"
+	"os"
+
 	"github.com/cloudradar-monitoring/rportcli/cmd"
 	"github.com/sirupsen/logrus"
 )
@@ -8,6 +11,14 @@ import (
 func main() {
 	err := cmd.Execute()
 	if err != nil {
+		// e.g. commands which failed on some of the clients exit with a code distinguishing it from a total failure
+		var exitCodeErr interface {
+			ExitCode() int
+		}
+		if errors.As(err, &exitCodeErr) {
+			logrus.Error(err)
+			os.Exit(exitCodeErr.ExitCode())
+		}
 		logrus.Fatal(err)
 	}
 }